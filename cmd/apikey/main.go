@@ -0,0 +1,76 @@
+// Command apikey creates an API key in MongoDB and prints it. The key is not
+// stored and cannot be shown again. -roles grants the key roles. The memory
+// store cannot be reached from here, set DEV_API_KEY for it instead.
+//
+//	go run ./cmd/apikey -name backoffice -roles front-desk
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+
+	"members.com/membership/internal/config"
+	"members.com/membership/internal/database"
+	"members.com/membership/pkg/auth"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/utils"
+)
+
+func main() {
+	name := flag.String("name", "", "name of the client the key is for")
+	roles := flag.String("roles", "", "comma separated roles to grant the key")
+	flag.Parse()
+	if *name == "" {
+		log.Fatal("-name is required")
+	}
+
+	settings, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mongoConnection, err := database.ConnectToMongoDB(context.Background(), settings.Mongo, nil, slog.Default())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mongoConnection.Client().Disconnect(context.Background())
+	apiKeys := mongoConnection.Collection(settings.Mongo.Collections.ApiKeys)
+	grants := mongoConnection.Collection(settings.Mongo.Collections.Grants)
+	err = repository.CreateApiKeyIndexes(context.Background(), apiKeys)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = repository.CreateGrantIndexes(context.Background(), grants)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	apiKey, err := auth.GenerateApiKey()
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = repository.NewApiKeyRepository(apiKeys).CreateApiKey(context.Background(), &models.ApiKey{
+		Name:      *name,
+		Hash:      auth.HashApiKey(apiKey),
+		CreatedAt: utils.FormatTimestamp(utils.SystemClock{}.Now()),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *roles != "" {
+		principal := auth.Principal{Subject: *name, Method: auth.MethodApiKey}
+		err = repository.NewGrantRepository(grants).SaveGrant(context.Background(), &models.Grant{
+			Subject: principal.ID(),
+			Roles:   strings.Split(*roles, ","),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	fmt.Println(apiKey)
+}
@@ -1,27 +1,313 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/event"
+	"members.com/membership/internal/config"
 	"members.com/membership/internal/database"
 	"members.com/membership/internal/routes"
+	"members.com/membership/internal/telemetry"
+	"members.com/membership/pkg/auth"
 	"members.com/membership/pkg/handler"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/metrics"
+	"members.com/membership/pkg/models"
 	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/scheduler"
 	"members.com/membership/pkg/service"
+	"members.com/membership/pkg/tracing"
+	"members.com/membership/pkg/utils"
 )
 
+// devApiKeyName names the API key created from the DEV_API_KEY setting.
+const devApiKeyName = "dev"
+
+// store holds the repositories and ID allocators of the configured store.
+type store struct {
+	memberRepository  repository.MemberRepositoryI
+	memberIdAllocator repository.IdAllocatorI
+	planRepository    repository.PlanRepositoryI
+	planIdAllocator   repository.IdAllocatorI
+	apiKeyRepository  repository.ApiKeyRepositoryI
+	roleRepository    repository.RoleRepositoryI
+	grantRepository   repository.GrantRepositoryI
+	auditRepository   repository.AuditRepositoryI
+	healthChecks      []service.HealthCheck
+	close             func(ctx context.Context) error
+}
+
 func main() {
-	mongoConnection, err := database.ConnectToMongoDB()
+	// SIGTERM, sent by docker compose and orchestrators, or SIGINT stop the
+	// server gracefully. A second signal stops it straight away.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	settings, err := config.Load()
 	if err != nil {
 		log.Fatal(err)
 	}
-	server := gin.Default()
+	logger := logging.New(os.Stdout, settings.Log.Level)
+	// Anything still logging through the log package, such as gin's debug
+	// output, is logged as JSON too.
+	slog.SetDefault(logger)
+	if settings.Log.Level == config.LogDebug {
+		gin.SetMode(gin.DebugMode)
+	} else {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	tracerProvider, shutdownTracing, err := telemetry.NewTracerProvider(ctx, settings.Tracing, os.Stdout)
+	if err != nil {
+		fatal(logger, "error setting up tracing", err)
+	}
+
+	store := newStore(ctx, settings, tracing.NewCommandMonitor(tracerProvider), logger)
+	recorder := metrics.New()
+	// Member counts are read from the bare repository so that scrapes do not
+	// show up as repository calls.
+	recorder.Register(metrics.NewMemberCollector(store.memberRepository, logger))
+	store.memberRepository = metrics.NewMemberRepository(store.memberRepository, recorder)
+
+	server := gin.New()
+	// Services are handed the gin context, which only looks up values such as
+	// the caller's principal in the request's context with the fallback on.
+	server.ContextWithFallback = true
+	// Requests are traced and logged at the info level, except for the health
+	// probes that orchestrators send every few seconds. Panics are logged by
+	// handler.Recover rather than gin.
+	server.Use(
+		handler.RequestID(),
+		handler.Trace(tracerProvider, telemetry.Propagator(), "/healthz", "/readyz", "/metrics"),
+		handler.LogRequests(logger, "/healthz", "/readyz"),
+		handler.Instrument(recorder),
+		gin.CustomRecoveryWithWriter(io.Discard, handler.Recover(logger)),
+	)
+
+	memberService := service.NewMemberService(store.memberRepository, store.planRepository, store.auditRepository, store.memberIdAllocator, utils.SystemClock{}, logger)
+	tracedMemberService := tracing.NewMemberService(memberService, tracerProvider)
+	MemberHandler := handler.NewMemberHandler(server, tracedMemberService, logger)
+
+	planService := service.NewPlanService(store.planRepository, store.memberRepository, store.planIdAllocator, logger)
+	planHandler := handler.NewPlanHandler(server, planService)
+
+	auditor := service.NewMemberAuditor(store.auditRepository, utils.SystemClock{}, logger)
+	memberAdminService := service.NewMemberAdminService(store.memberRepository, tracedMemberService, auditor, utils.SystemClock{}, settings.Members.Retention, logger)
+	adminHandler := handler.NewAdminHandler(server, memberAdminService)
+
+	keySet, err := auth.LoadKeySet(settings.Auth.JwtKeysFile)
+	if err != nil {
+		fatal(logger, "error loading JWT keys", err)
+	}
+	authenticator := auth.NewAuthenticator(store.apiKeyRepository, keySet, auth.TokenOptions{
+		Issuer:   settings.Auth.JwtIssuer,
+		Audience: settings.Auth.JwtAudience,
+	}, utils.SystemClock{})
+
+	if err := auth.EnsureDefaultRoles(ctx, store.roleRepository); err != nil {
+		fatal(logger, "error creating default roles", err)
+	}
+	if subject := settings.Auth.BootstrapAdmin; subject != "" {
+		err := store.grantRepository.SaveGrant(ctx, &models.Grant{Subject: subject, Roles: []string{auth.RoleAdmin}})
+		if err != nil {
+			fatal(logger, "error granting bootstrap admin", err)
+		}
+	}
+	if apiKey := settings.Auth.DevApiKey; apiKey != "" {
+		if err := createDevApiKey(ctx, store, apiKey); err != nil {
+			fatal(logger, "error creating dev API key", err)
+		}
+	}
+	authorizer := auth.NewAuthorizer(store.roleRepository, store.grantRepository)
+	accessHandler := handler.NewAccessHandler(server, service.NewAccessService(store.roleRepository, store.grantRepository, logger))
+	auditHandler := handler.NewAuditHandler(server, service.NewAuditService(store.auditRepository, logger))
+
+	healthService := service.NewHealthService(utils.SystemClock{}, logger, store.healthChecks...)
+	healthHandler := handler.NewHealthHandler(server, healthService)
+
+	routes.RegisterRoutes(server, settings.Features, handler.Authenticate(authenticator), handler.Authorize(authorizer), routes.Handlers{
+		Member:  MemberHandler,
+		Plan:    planHandler,
+		Admin:   adminHandler,
+		Access:  accessHandler,
+		Audit:   auditHandler,
+		Health:  healthHandler,
+		Metrics: recorder.Handler(),
+	})
+
+	jobs := scheduler.NewScheduler(utils.SystemClock{}, settings.Scheduler.Interval, logger,
+		scheduler.NewLapseJob(store.memberRepository, auditor, logger),
+		scheduler.NewPurgeJob(store.memberRepository, auditor, settings.Members.Retention, logger),
+	)
+	if settings.Features.Scheduler {
+		jobs.Start(ctx)
+	}
+
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", settings.Server.Port),
+		Handler:           server,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+		ReadHeaderTimeout: settings.Server.ReadHeaderTimeout,
+		ReadTimeout:       settings.Server.ReadTimeout,
+		WriteTimeout:      settings.Server.WriteTimeout,
+		IdleTimeout:       settings.Server.IdleTimeout,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	var failed bool
+	select {
+	case err := <-serveErr:
+		logger.Error("server stopped", "error", err)
+		failed = true
+	case <-ctx.Done():
+		logger.Info("shutting down, draining in-flight requests")
+	}
+	stop()
+	// Readiness reports draining from here on. The drain delay gives load
+	// balancers time to notice before the server stops accepting connections.
+	healthService.Drain()
+	if !failed {
+		time.Sleep(settings.Server.DrainDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), settings.Server.ShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("error shutting down server", "error", err)
+		failed = true
+	}
+	jobs.Wait()
+	if err := store.close(shutdownCtx); err != nil {
+		logger.Error("error closing store", "error", err)
+		failed = true
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("error flushing traces", "error", err)
+		failed = true
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// newStore returns the repositories of the configured store, either Mongo or
+// memory. The monitor is told about every Mongo command run.
+func newStore(ctx context.Context, settings config.Config, monitor *event.CommandMonitor, logger *slog.Logger) store {
+	if settings.Store == config.StoreMemory {
+		return store{
+			memberRepository:  repository.NewMemoryMemberRepository(),
+			memberIdAllocator: repository.NewMemoryIdAllocator(repository.MemberIdSequence),
+			planRepository:    repository.NewMemoryPlanRepository(),
+			planIdAllocator:   repository.NewMemoryIdAllocator(repository.PlanIdSequence),
+			apiKeyRepository:  repository.NewMemoryApiKeyRepository(),
+			roleRepository:    repository.NewMemoryRoleRepository(),
+			grantRepository:   repository.NewMemoryGrantRepository(),
+			auditRepository:   repository.NewMemoryAuditRepository(),
+			close:             func(ctx context.Context) error { return nil },
+		}
+	}
+
+	mongoConnection, err := database.ConnectToMongoDB(ctx, settings.Mongo, monitor, logger)
+	if err != nil {
+		fatal(logger, "error connecting to MongoDB", err)
+	}
+	collections := settings.Mongo.Collections
+	members := mongoConnection.Collection(collections.Members)
+	plans := mongoConnection.Collection(collections.Plans)
+	counters := mongoConnection.Collection(collections.Counters)
+	apiKeys := mongoConnection.Collection(collections.ApiKeys)
+	roles := mongoConnection.Collection(collections.Roles)
+	grants := mongoConnection.Collection(collections.Grants)
+	audit := mongoConnection.Collection(collections.Audit)
 
-	memberService := service.NewMemberService(repository.NewMembershipRepository(mongoConnection))
-	MemberHandler := handler.NewMemberHandler(server, memberService)
+	memberIdAllocator := repository.NewMongoIdAllocator(counters, repository.MemberIdSequence)
+	reassigned, err := repository.ReassignDuplicateMemberIds(ctx, members, memberIdAllocator)
+	for _, reassignment := range reassigned {
+		logger.Warn("member given a new id, its old one was shared", "memberId", reassignment.NewId, "oldMemberId", reassignment.OldId)
+	}
+	if err != nil {
+		fatal(logger, "error reassigning duplicate member ids", err)
+	}
+	_, err = repository.NormalizeMemberEmails(ctx, members)
+	if err != nil {
+		fatal(logger, "error normalizing member emails", err)
+	}
+	err = repository.CreateMemberIndexes(ctx, members)
+	if err != nil {
+		fatal(logger, "error creating member indexes", err)
+	}
+	_, err = repository.IndexMemberSearchTerms(ctx, members)
+	if err != nil {
+		fatal(logger, "error indexing member search terms", err)
+	}
+	err = repository.CreatePlanIndexes(ctx, plans)
+	if err != nil {
+		fatal(logger, "error creating plan indexes", err)
+	}
+	err = repository.CreateApiKeyIndexes(ctx, apiKeys)
+	if err != nil {
+		fatal(logger, "error creating API key indexes", err)
+	}
+	err = repository.CreateRoleIndexes(ctx, roles)
+	if err != nil {
+		fatal(logger, "error creating role indexes", err)
+	}
+	err = repository.CreateGrantIndexes(ctx, grants)
+	if err != nil {
+		fatal(logger, "error creating grant indexes", err)
+	}
+	err = repository.CreateAuditIndexes(ctx, audit)
+	if err != nil {
+		fatal(logger, "error creating audit indexes", err)
+	}
+	return store{
+		memberRepository:  repository.NewMembershipRepository(members),
+		memberIdAllocator: memberIdAllocator,
+		planRepository:    repository.NewPlanRepository(plans),
+		planIdAllocator:   repository.NewMongoIdAllocator(counters, repository.PlanIdSequence),
+		apiKeyRepository:  repository.NewApiKeyRepository(apiKeys),
+		roleRepository:    repository.NewRoleRepository(roles),
+		grantRepository:   repository.NewGrantRepository(grants),
+		auditRepository:   repository.NewAuditRepository(audit),
+		healthChecks: []service.HealthCheck{{
+			Name:  "mongo",
+			Check: func(ctx context.Context) error { return mongoConnection.Client().Ping(ctx, nil) },
+		}},
+		close: mongoConnection.Client().Disconnect,
+	}
+}
 
-	routes.RegisterRoutes(server, MemberHandler)
+// createDevApiKey stores the configured dev API key and makes it an admin, so
+// that the memory store, which cmd/apikey cannot reach, can be called.
+func createDevApiKey(ctx context.Context, store store, apiKey string) error {
+	err := store.apiKeyRepository.CreateApiKey(ctx, &models.ApiKey{
+		Name:      devApiKeyName,
+		Hash:      auth.HashApiKey(apiKey),
+		CreatedAt: utils.FormatTimestamp(utils.SystemClock{}.Now()),
+	})
+	if err != nil {
+		return err
+	}
+	principal := auth.Principal{Subject: devApiKeyName, Method: auth.MethodApiKey}
+	return store.grantRepository.SaveGrant(ctx, &models.Grant{Subject: principal.ID(), Roles: []string{auth.RoleAdmin}})
+}
 
-	server.Run(":8080")
+// fatal logs an error the application cannot start with and exits.
+func fatal(logger *slog.Logger, message string, err error) {
+	logger.Error(message, "error", err)
+	os.Exit(1)
 }
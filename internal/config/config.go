@@ -0,0 +1,336 @@
+// Package config loads the application's settings. Each setting has a
+// default, which an optional YAML file named by CONFIG_FILE overrides, which
+// in turn the setting's environment variable overrides.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+	"gopkg.in/yaml.v3"
+)
+
+// Stores the repositories can be kept in.
+const (
+	StoreMongo  = "mongo"
+	StoreMemory = "memory"
+)
+
+// Log levels, from the most to the least verbose.
+const (
+	LogDebug = "debug"
+	LogInfo  = "info"
+	LogWarn  = "warn"
+	LogError = "error"
+)
+
+// Exporters traces can be sent to.
+const (
+	TracingNone   = "none"
+	TracingStdout = "stdout"
+	TracingOtlp   = "otlp"
+)
+
+// minDevApiKeyLength keeps the dev API key from being trivially guessable.
+const minDevApiKeyLength = 16
+
+type Config struct {
+	Server    Server    `yaml:"server"`
+	Store     string    `yaml:"store" env:"MEMBER_STORE"`
+	Mongo     Mongo     `yaml:"mongo"`
+	Auth      Auth      `yaml:"auth"`
+	Members   Members   `yaml:"members"`
+	Scheduler Scheduler `yaml:"scheduler"`
+	Log       Log       `yaml:"log"`
+	Tracing   Tracing   `yaml:"tracing"`
+	Features  Features  `yaml:"features"`
+}
+
+type Server struct {
+	Port              int           `yaml:"port" env:"PORT"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"readTimeout" env:"HTTP_READ_TIMEOUT"`
+	// WriteTimeout does not apply to exports, which may stream for longer.
+	WriteTimeout    time.Duration `yaml:"writeTimeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout     time.Duration `yaml:"idleTimeout" env:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT"`
+	// DrainDelay is how long the server keeps accepting requests, while
+	// reporting not ready, before it shuts down. It gives load balancers time
+	// to notice and stop sending requests.
+	DrainDelay time.Duration `yaml:"drainDelay" env:"SHUTDOWN_DRAIN_DELAY"`
+}
+
+type Mongo struct {
+	URI      string `yaml:"uri" env:"MONGODB_URI"`
+	Database string `yaml:"database" env:"MONGODB_DATABASE"`
+	// PingAttempts is how often the server is pinged on startup before
+	// giving up, backing off a little longer after each attempt.
+	PingAttempts int         `yaml:"pingAttempts" env:"MONGODB_PING_ATTEMPTS"`
+	Collections  Collections `yaml:"collections"`
+}
+
+// Collections names the collection each repository is stored in.
+type Collections struct {
+	Members  string `yaml:"members" env:"MONGODB_MEMBERS_COLLECTION"`
+	Plans    string `yaml:"plans" env:"MONGODB_PLANS_COLLECTION"`
+	Counters string `yaml:"counters" env:"MONGODB_COUNTERS_COLLECTION"`
+	ApiKeys  string `yaml:"apiKeys" env:"MONGODB_API_KEYS_COLLECTION"`
+	Roles    string `yaml:"roles" env:"MONGODB_ROLES_COLLECTION"`
+	Grants   string `yaml:"grants" env:"MONGODB_GRANTS_COLLECTION"`
+	Audit    string `yaml:"audit" env:"MONGODB_AUDIT_COLLECTION"`
+}
+
+type Auth struct {
+	JwtKeysFile string `yaml:"jwtKeysFile" env:"JWT_KEYS_FILE"`
+	JwtIssuer   string `yaml:"jwtIssuer" env:"JWT_ISSUER"`
+	JwtAudience string `yaml:"jwtAudience" env:"JWT_AUDIENCE"`
+	// BootstrapAdmin names a principal, such as jwt:jane.smith, that is made
+	// an admin so that somebody can manage grants on a fresh deployment.
+	BootstrapAdmin string `yaml:"bootstrapAdmin" env:"BOOTSTRAP_ADMIN"`
+	// DevApiKey is an API key, named dev, that is made an admin on start. It
+	// is only accepted with the memory store, where keys cannot be created
+	// with cmd/apikey.
+	DevApiKey string `yaml:"devApiKey" env:"DEV_API_KEY"`
+}
+
+type Members struct {
+	// Retention is how long deleted members are kept before they are purged.
+	Retention time.Duration `yaml:"retention" env:"MEMBER_RETENTION"`
+}
+
+type Scheduler struct {
+	Interval time.Duration `yaml:"interval" env:"SCHEDULER_INTERVAL"`
+}
+
+type Log struct {
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+}
+
+type Tracing struct {
+	// Exporter is where spans are sent: nowhere, to standard output as JSON,
+	// or to an OpenTelemetry collector over OTLP/HTTP.
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// Endpoint is the URL the OTLP exporter posts spans to, path included.
+	Endpoint    string `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"`
+	ServiceName string `yaml:"serviceName" env:"OTEL_SERVICE_NAME"`
+}
+
+// Features switch optional parts of the application on or off.
+type Features struct {
+	Search bool `yaml:"search" env:"FEATURE_SEARCH"`
+	Import bool `yaml:"import" env:"FEATURE_IMPORT"`
+	Export bool `yaml:"export" env:"FEATURE_EXPORT"`
+	// Metrics serves Prometheus metrics on /metrics without credentials.
+	Metrics bool `yaml:"metrics" env:"FEATURE_METRICS"`
+	// Scheduler runs the background jobs, such as lapsing expired
+	// memberships. With several instances it can be left on in only one.
+	Scheduler bool `yaml:"scheduler" env:"FEATURE_SCHEDULER"`
+}
+
+// Default returns the settings used when nothing overrides them.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:              8080,
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Store: StoreMongo,
+		Mongo: Mongo{
+			URI:          "mongodb://localhost:27017",
+			Database:     "membership",
+			PingAttempts: 6,
+			Collections: Collections{
+				Members:  "members",
+				Plans:    "plans",
+				Counters: "counters",
+				ApiKeys:  "apikeys",
+				Roles:    "roles",
+				Grants:   "grants",
+				Audit:    "audit",
+			},
+		},
+		Members:   Members{Retention: 30 * 24 * time.Hour},
+		Scheduler: Scheduler{Interval: time.Hour},
+		Log:       Log{Level: LogInfo},
+		Tracing: Tracing{
+			Exporter:    TracingNone,
+			Endpoint:    "http://localhost:4318/v1/traces",
+			ServiceName: "membership-app",
+		},
+		Features: Features{Search: true, Import: true, Export: true, Metrics: true, Scheduler: true},
+	}
+}
+
+// Load reads the settings from the YAML file named by CONFIG_FILE, if set,
+// and the environment, and validates them. Every problem found is reported
+// in the returned error.
+func Load() (Config, error) {
+	return load(os.Getenv("CONFIG_FILE"), os.LookupEnv)
+}
+
+func load(file string, lookupEnv func(name string) (string, bool)) (Config, error) {
+	config := Default()
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return config, fmt.Errorf("reading config file: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+			return config, fmt.Errorf("parsing config file %s: %w", file, err)
+		}
+	}
+
+	errs := applyEnv(reflect.ValueOf(&config).Elem(), lookupEnv)
+	if len(errs) == 0 {
+		errs = config.validate()
+	}
+	if len(errs) > 0 {
+		return config, fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return config, nil
+}
+
+// applyEnv sets every field tagged with an environment variable that is set
+// and not empty, as variables often are in .env files.
+func applyEnv(value reflect.Value, lookupEnv func(name string) (string, bool)) []error {
+	var errs []error
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() == reflect.Struct {
+			errs = append(errs, applyEnv(field, lookupEnv)...)
+			continue
+		}
+		name := value.Type().Field(i).Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		env, set := lookupEnv(name)
+		if !set || env == "" {
+			continue
+		}
+		if err := setField(field, env); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errs
+}
+
+func setField(field reflect.Value, env string) error {
+	switch field.Interface().(type) {
+	case time.Duration:
+		duration, err := time.ParseDuration(env)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 30s or 15m", env)
+		}
+		field.SetInt(int64(duration))
+	case int:
+		number, err := strconv.Atoi(env)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", env)
+		}
+		field.SetInt(int64(number))
+	case bool:
+		enabled, err := strconv.ParseBool(env)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", env)
+		}
+		field.SetBool(enabled)
+	case string:
+		field.SetString(env)
+	}
+	return nil
+}
+
+func (c *Config) validate() []error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		invalid("server port %d must be between 1 and 65535", c.Server.Port)
+	}
+	if c.Server.DrainDelay < 0 {
+		invalid("server drain delay %s must not be negative", c.Server.DrainDelay)
+	}
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server read header timeout", c.Server.ReadHeaderTimeout},
+		{"server read timeout", c.Server.ReadTimeout},
+		{"server write timeout", c.Server.WriteTimeout},
+		{"server idle timeout", c.Server.IdleTimeout},
+		{"server shutdown timeout", c.Server.ShutdownTimeout},
+		{"member retention", c.Members.Retention},
+		{"scheduler interval", c.Scheduler.Interval},
+	}
+	for _, duration := range durations {
+		if duration.value <= 0 {
+			invalid("%s %s must be positive", duration.name, duration.value)
+		}
+	}
+
+	if c.Auth.DevApiKey != "" && len(c.Auth.DevApiKey) < minDevApiKeyLength {
+		invalid("auth dev API key must be at least %d characters", minDevApiKeyLength)
+	}
+
+	switch c.Store {
+	case StoreMemory:
+	case StoreMongo:
+		if c.Auth.DevApiKey != "" {
+			invalid("auth dev API key is only accepted with the memory store, create keys with cmd/apikey")
+		}
+		if c.Mongo.URI == "" {
+			invalid("mongo uri is required")
+		}
+		if c.Mongo.Database == "" {
+			invalid("mongo database is required")
+		}
+		if c.Mongo.PingAttempts < 1 {
+			invalid("mongo ping attempts %d must be at least 1", c.Mongo.PingAttempts)
+		}
+		collections := reflect.ValueOf(c.Mongo.Collections)
+		for i := 0; i < collections.NumField(); i++ {
+			if collections.Field(i).String() == "" {
+				invalid("mongo collection for %s is required", collections.Type().Field(i).Tag.Get("yaml"))
+			}
+		}
+	default:
+		invalid("store %q must be %q or %q", c.Store, StoreMongo, StoreMemory)
+	}
+
+	switch c.Log.Level {
+	case LogDebug, LogInfo, LogWarn, LogError:
+	default:
+		invalid("log level %q must be %s, %s, %s or %s", c.Log.Level, LogDebug, LogInfo, LogWarn, LogError)
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingStdout:
+	case TracingOtlp:
+		if endpoint, err := url.Parse(c.Tracing.Endpoint); err != nil || endpoint.Host == "" {
+			invalid("tracing endpoint %q must be a URL such as http://localhost:4318/v1/traces", c.Tracing.Endpoint)
+		}
+	default:
+		invalid("tracing exporter %q must be %s, %s or %s", c.Tracing.Exporter, TracingNone, TracingStdout, TracingOtlp)
+	}
+	if c.Tracing.ServiceName == "" {
+		invalid("tracing service name is required")
+	}
+	return errs
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(values map[string]string) func(name string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, set := values[name]
+		return value, set
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	return file
+}
+
+func TestLoadDefaults(t *testing.T) {
+	config, err := load("", env(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, Default(), config)
+	assert.Equal(t, 8080, config.Server.Port)
+	assert.Equal(t, "membership", config.Mongo.Database)
+	assert.True(t, config.Features.Export)
+}
+
+func TestLoadFileOverridesDefaults(t *testing.T) {
+	file := writeConfigFile(t, `
+server:
+  port: 9090
+  writeTimeout: 1m
+mongo:
+  database: members-test
+  collections:
+    members: people
+features:
+  import: false
+`)
+
+	config, err := load(file, env(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, 9090, config.Server.Port)
+	assert.Equal(t, time.Minute, config.Server.WriteTimeout)
+	assert.Equal(t, 30*time.Second, config.Server.ReadTimeout)
+	assert.Equal(t, "members-test", config.Mongo.Database)
+	assert.Equal(t, "people", config.Mongo.Collections.Members)
+	assert.Equal(t, "plans", config.Mongo.Collections.Plans)
+	assert.False(t, config.Features.Import)
+	assert.True(t, config.Features.Export)
+}
+
+func TestLoadEnvironmentOverridesFile(t *testing.T) {
+	file := writeConfigFile(t, `
+server:
+  port: 9090
+store: mongo
+log:
+  level: warn
+`)
+
+	config, err := load(file, env(map[string]string{
+		"PORT":                       "9191",
+		"MEMBER_STORE":               "memory",
+		"SCHEDULER_INTERVAL":         "15m",
+		"MONGODB_MEMBERS_COLLECTION": "people",
+		"FEATURE_SEARCH":             "false",
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 9191, config.Server.Port)
+	assert.Equal(t, StoreMemory, config.Store)
+	assert.Equal(t, 15*time.Minute, config.Scheduler.Interval)
+	assert.Equal(t, "people", config.Mongo.Collections.Members)
+	assert.False(t, config.Features.Search)
+	assert.Equal(t, LogWarn, config.Log.Level)
+}
+
+func TestLoadEmptyFile(t *testing.T) {
+	config, err := load(writeConfigFile(t, ""), env(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, Default(), config)
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, err := load(filepath.Join(t.TempDir(), "missing.yaml"), env(nil))
+
+	assert.ErrorContains(t, err, "reading config file")
+}
+
+func TestLoadUnknownField(t *testing.T) {
+	_, err := load(writeConfigFile(t, "server:\n  prot: 9090\n"), env(nil))
+
+	assert.ErrorContains(t, err, "field prot not found")
+}
+
+func TestLoadInvalidEnvironment(t *testing.T) {
+	_, err := load("", env(map[string]string{
+		"PORT":             "eighty",
+		"SHUTDOWN_TIMEOUT": "30",
+		"FEATURE_EXPORT":   "maybe",
+	}))
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `PORT: "eighty" is not a whole number`)
+	assert.ErrorContains(t, err, `SHUTDOWN_TIMEOUT: "30" is not a duration such as 30s or 15m`)
+	assert.ErrorContains(t, err, `FEATURE_EXPORT: "maybe" is not true or false`)
+}
+
+func TestLoadInvalidSettings(t *testing.T) {
+	file := writeConfigFile(t, `
+mongo:
+  database: ""
+  collections:
+    audit: ""
+`)
+
+	_, err := load(file, env(map[string]string{
+		"PORT":                  "70000",
+		"HTTP_READ_TIMEOUT":     "-1s",
+		"LOG_LEVEL":             "verbose",
+		"MONGODB_PING_ATTEMPTS": "0",
+		"SHUTDOWN_DRAIN_DELAY":  "-5s",
+	}))
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "server port 70000 must be between 1 and 65535")
+	assert.ErrorContains(t, err, "server read timeout -1s must be positive")
+	assert.ErrorContains(t, err, "server drain delay -5s must not be negative")
+	assert.ErrorContains(t, err, "mongo database is required")
+	assert.ErrorContains(t, err, "mongo collection for audit is required")
+	assert.ErrorContains(t, err, "mongo ping attempts 0 must be at least 1")
+	assert.ErrorContains(t, err, `log level "verbose" must be debug, info, warn or error`)
+}
+
+func TestLoadUnknownStore(t *testing.T) {
+	_, err := load("", env(map[string]string{"MEMBER_STORE": "postgres"}))
+
+	assert.ErrorContains(t, err, `store "postgres" must be "mongo" or "memory"`)
+}
+
+func TestLoadTracing(t *testing.T) {
+	config, err := load("", env(map[string]string{
+		"TRACING_EXPORTER":                   "otlp",
+		"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector:4318/v1/traces",
+		"OTEL_SERVICE_NAME":                  "membership-test",
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, Tracing{
+		Exporter:    TracingOtlp,
+		Endpoint:    "http://collector:4318/v1/traces",
+		ServiceName: "membership-test",
+	}, config.Tracing)
+}
+
+func TestLoadInvalidTracing(t *testing.T) {
+	_, err := load("", env(map[string]string{"TRACING_EXPORTER": "jaeger"}))
+	assert.ErrorContains(t, err, `tracing exporter "jaeger" must be none, stdout or otlp`)
+
+	_, err = load("", env(map[string]string{
+		"TRACING_EXPORTER":                   "otlp",
+		"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "collector",
+	}))
+	assert.ErrorContains(t, err, `tracing endpoint "collector" must be a URL`)
+}
+
+func TestLoadIgnoresEmptyEnvironment(t *testing.T) {
+	config, err := load("", env(map[string]string{"MEMBER_STORE": "", "PORT": ""}))
+
+	require.NoError(t, err)
+	assert.Equal(t, StoreMongo, config.Store)
+	assert.Equal(t, 8080, config.Server.Port)
+}
+
+func TestLoadDevApiKey(t *testing.T) {
+	config, err := load("", env(map[string]string{"MEMBER_STORE": "memory", "DEV_API_KEY": "local-dev-key-0123"}))
+	require.NoError(t, err)
+	assert.Equal(t, "local-dev-key-0123", config.Auth.DevApiKey)
+
+	_, err = load("", env(map[string]string{"MEMBER_STORE": "memory", "DEV_API_KEY": "short"}))
+	assert.ErrorContains(t, err, "auth dev API key must be at least 16 characters")
+
+	_, err = load("", env(map[string]string{"DEV_API_KEY": "local-dev-key-0123"}))
+	assert.ErrorContains(t, err, "auth dev API key is only accepted with the memory store")
+}
+
+func TestMemoryStoreNeedsNoMongoSettings(t *testing.T) {
+	file := writeConfigFile(t, "store: memory\nmongo:\n  uri: \"\"\n")
+
+	_, err := load(file, env(nil))
+
+	assert.NoError(t, err)
+}
@@ -2,24 +2,59 @@ package database
 
 import (
 	"context"
-	"log"
-	"os"
+	"fmt"
+	"log/slog"
+	"time"
 
-	_ "github.com/joho/godotenv/autoload"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/internal/config"
 )
 
-func ConnectToMongoDB() (*mongo.Database, error) {
-	mongoUri := os.Getenv("MONGODB_URI")
-	if mongoUri == "" {
-		mongoUri = "mongodb://localhost:27017"
-	}
+// Mongo may still be starting when the app does, as under docker compose, so
+// the startup ping is retried with a backoff that doubles up to
+// maxPingBackoff.
+const (
+	initialPingBackoff = 500 * time.Millisecond
+	maxPingBackoff     = 8 * time.Second
+	pingTimeout        = 5 * time.Second
+)
 
-	clientOptions := options.Client().ApplyURI(mongoUri)
-	client, err := mongo.Connect(context.Background(), clientOptions)
+// ConnectToMongoDB connects to the configured server and pings it until it
+// answers. The monitor, if not nil, is told about every command run. Callers
+// disconnect the returned database's client when done.
+func ConnectToMongoDB(ctx context.Context, settings config.Mongo, monitor *event.CommandMonitor, logger *slog.Logger) (*mongo.Database, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(settings.URI).SetMonitor(monitor))
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("connecting to MongoDB: %w", err)
+	}
+	if err := pingWithBackoff(ctx, client, settings.PingAttempts, logger); err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+	return client.Database(settings.Database), nil
+}
+
+func pingWithBackoff(ctx context.Context, client *mongo.Client, pingAttempts int, logger *slog.Logger) error {
+	backoff := initialPingBackoff
+	for attempt := 1; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		err := client.Ping(pingCtx, nil)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if attempt == pingAttempts {
+			return fmt.Errorf("pinging MongoDB after %d attempts: %w", attempt, err)
+		}
+
+		logger.WarnContext(ctx, "MongoDB is not reachable yet", "attempt", attempt, "retryIn", backoff.String(), "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxPingBackoff)
 	}
-	return client.Database("membership"), err
 }
@@ -1,14 +1,79 @@
 package routes
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"members.com/membership/internal/config"
+	"members.com/membership/pkg/auth"
 	"members.com/membership/pkg/handler"
 )
 
-func RegisterRoutes(server *gin.Engine, handler handler.MemberHandlerI) {
-	server.POST("/member", handler.CreateMember)
-	server.GET("/member/:id", handler.GetMemberById)
-	server.GET("/members", handler.GetAllMembers)
-	server.PUT("/member/:id", handler.UpdateMemberById)
-	server.DELETE("/member/:id", handler.DeleteMemberById)
+// Handlers are the handlers the routes are served by.
+type Handlers struct {
+	Member  handler.MemberHandlerI
+	Plan    handler.PlanHandlerI
+	Admin   handler.AdminHandlerI
+	Access  handler.AccessHandlerI
+	Audit   handler.AuditHandlerI
+	Health  handler.HealthHandlerI
+	Metrics http.Handler
+}
+
+// RegisterRoutes registers the API routes. Every route but the health probes
+// and metrics requires the caller to pass authenticate, and authorize for the
+// permission the route needs. Routes of features that are switched off are
+// not registered.
+func RegisterRoutes(server *gin.Engine, features config.Features, authenticate gin.HandlerFunc, authorize func(permission string) gin.HandlerFunc, handlers Handlers) {
+	server.GET("/healthz", handlers.Health.Liveness)
+	server.GET("/readyz", handlers.Health.Readiness)
+	if features.Metrics {
+		server.GET("/metrics", gin.WrapH(handlers.Metrics))
+	}
+
+	api := server.Group("/", authenticate)
+	api.POST("/member", authorize(auth.PermissionMemberWrite), handlers.Member.CreateMember)
+	api.GET("/member/:id", authorize(auth.PermissionMemberRead), handlers.Member.GetMemberById)
+	api.GET("/members", authorize(auth.PermissionMemberRead), handlers.Member.GetAllMembers)
+	if features.Search {
+		api.GET("/members/search", authorize(auth.PermissionMemberRead), handlers.Member.SearchMembers)
+	}
+	if features.Import {
+		api.POST("/members/import", authorize(auth.PermissionMemberWrite), handlers.Member.ImportMembers)
+	}
+	if features.Export {
+		api.GET("/members/export", authorize(auth.PermissionMemberRead), handlers.Member.ExportMembers)
+	}
+	api.PUT("/member/:id", authorize(auth.PermissionMemberWrite), handlers.Member.UpdateMemberById)
+	api.PATCH("/member/:id", authorize(auth.PermissionMemberWrite), handlers.Member.PatchMemberById)
+	api.DELETE("/member/:id", authorize(auth.PermissionMemberDelete), handlers.Member.DeleteMemberById)
+	api.POST("/member/:id/renew", authorize(auth.PermissionMemberWrite), handlers.Member.RenewMemberById)
+	api.POST("/member/:id/activate", authorize(auth.PermissionMemberWrite), handlers.Member.ActivateMember)
+	api.POST("/member/:id/suspend", authorize(auth.PermissionMemberWrite), handlers.Member.SuspendMember)
+	api.POST("/member/:id/reactivate", authorize(auth.PermissionMemberWrite), handlers.Member.ReactivateMember)
+	api.POST("/member/:id/cancel", authorize(auth.PermissionMemberWrite), handlers.Member.CancelMember)
+	api.GET("/member/:id/history", authorize(auth.PermissionAuditRead), handlers.Audit.GetMemberHistory)
+	api.GET("/audit", authorize(auth.PermissionAuditRead), handlers.Audit.GetAuditEntries)
+
+	api.POST("/plan", authorize(auth.PermissionPlanWrite), handlers.Plan.CreatePlan)
+	api.GET("/plan/:id", authorize(auth.PermissionPlanRead), handlers.Plan.GetPlanById)
+	api.GET("/plans", authorize(auth.PermissionPlanRead), handlers.Plan.GetAllPlans)
+	api.PUT("/plan/:id", authorize(auth.PermissionPlanWrite), handlers.Plan.UpdatePlanById)
+	api.DELETE("/plan/:id", authorize(auth.PermissionPlanWrite), handlers.Plan.DeletePlanById)
+
+	admin := api.Group("/admin")
+	admin.GET("/members/deleted", authorize(auth.PermissionMemberRestore), handlers.Admin.GetDeletedMembers)
+	admin.POST("/member/:id/restore", authorize(auth.PermissionMemberRestore), handlers.Admin.RestoreMemberById)
+	admin.POST("/members/purge", authorize(auth.PermissionMemberPurge), handlers.Admin.PurgeDeletedMembers)
+
+	admin.GET("/roles", authorize(auth.PermissionAccessAdmin), handlers.Access.GetAllRoles)
+	admin.PUT("/role/:name", authorize(auth.PermissionAccessAdmin), handlers.Access.SaveRole)
+	admin.DELETE("/role/:name", authorize(auth.PermissionAccessAdmin), handlers.Access.DeleteRoleByName)
+	admin.GET("/grants", authorize(auth.PermissionAccessAdmin), handlers.Access.GetAllGrants)
+	admin.PUT("/grant/:subject", authorize(auth.PermissionAccessAdmin), handlers.Access.SaveGrant)
+	admin.DELETE("/grant/:subject", authorize(auth.PermissionAccessAdmin), handlers.Access.DeleteGrantBySubject)
+
+	server.HandleMethodNotAllowed = true
+	server.NoRoute(handler.NotFound)
+	server.NoMethod(handler.MethodNotAllowed)
 }
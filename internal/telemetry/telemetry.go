@@ -0,0 +1,58 @@
+// Package telemetry sets up the OpenTelemetry tracer provider the configured
+// exporter sends spans to.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"members.com/membership/internal/config"
+)
+
+// Propagator reads and writes W3C traceparent, tracestate and baggage
+// headers.
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// NewTracerProvider returns a tracer provider sending spans to the configured
+// exporter, and a function that flushes the spans still buffered and stops
+// it. Requests are sampled when the caller's traceparent says so, or always
+// when there is none. The stdout exporter writes to stdout.
+func NewTracerProvider(ctx context.Context, settings config.Tracing, stdout io.Writer) (trace.TracerProvider, func(ctx context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch settings.Exporter {
+	case config.TracingNone:
+		return noop.NewTracerProvider(), func(ctx context.Context) error { return nil }, nil
+	case config.TracingStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(stdout))
+	case config.TracingOtlp:
+		exporter, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(settings.Endpoint))
+	default:
+		err = fmt.Errorf("unknown exporter %q", settings.Exporter)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating trace exporter: %w", err)
+	}
+
+	service, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(settings.ServiceName)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("describing service for traces: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(service),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())),
+	)
+	return provider, provider.Shutdown, nil
+}
@@ -0,0 +1,40 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"members.com/membership/internal/config"
+)
+
+func TestNewTracerProviderNone(t *testing.T) {
+	var stdout bytes.Buffer
+	provider, shutdown, err := NewTracerProvider(context.Background(), config.Default().Tracing, &stdout)
+	require.NoError(t, err)
+
+	_, span := provider.Tracer("test").Start(context.Background(), "GET /member/:id")
+	span.End()
+
+	assert.False(t, span.SpanContext().IsValid())
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Empty(t, stdout.String())
+}
+
+func TestNewTracerProviderStdout(t *testing.T) {
+	var stdout bytes.Buffer
+	settings := config.Default().Tracing
+	settings.Exporter = config.TracingStdout
+	provider, shutdown, err := NewTracerProvider(context.Background(), settings, &stdout)
+	require.NoError(t, err)
+
+	_, span := provider.Tracer("test").Start(context.Background(), "GET /member/:id")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	assert.Contains(t, stdout.String(), `"Name":"GET /member/:id"`)
+	assert.Contains(t, stdout.String(), `"Value":"membership-app"`)
+	assert.Contains(t, stdout.String(), span.SpanContext().TraceID().String())
+}
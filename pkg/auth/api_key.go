@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// apiKeyPrefix makes API keys easy to recognise, e.g. by secret scanners.
+const apiKeyPrefix = "mk_"
+
+// GenerateApiKey returns a new random API key.
+func GenerateApiKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashApiKey returns the form an API key is stored and looked up in. The keys
+// are long and random, so a fast unsalted hash is enough to make a leaked
+// table useless.
+func HashApiKey(apiKey string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:])
+}
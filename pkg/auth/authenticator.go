@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/utils"
+)
+
+// ErrUnauthenticated is returned for credentials that are unknown, expired or
+// not signed by a trusted key.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+type AuthenticatorI interface {
+	AuthenticateApiKey(ctx context.Context, apiKey string) (*Principal, error)
+	AuthenticateToken(ctx context.Context, token string) (*Principal, error)
+}
+
+// TokenOptions are the claims tokens must carry besides a valid signature
+// and expiry. Empty options are not checked.
+type TokenOptions struct {
+	Issuer   string
+	Audience string
+}
+
+type Authenticator struct {
+	apiKeyRepository repository.ApiKeyRepositoryI
+	keySet           *KeySet
+	tokenOptions     TokenOptions
+	clock            utils.Clock
+}
+
+func NewAuthenticator(apiKeyRepository repository.ApiKeyRepositoryI, keySet *KeySet, tokenOptions TokenOptions, clock utils.Clock) AuthenticatorI {
+	return &Authenticator{
+		apiKeyRepository: apiKeyRepository,
+		keySet:           keySet,
+		tokenOptions:     tokenOptions,
+		clock:            clock,
+	}
+}
+
+func (a *Authenticator) AuthenticateApiKey(ctx context.Context, apiKey string) (*Principal, error) {
+	if apiKey == "" {
+		return nil, ErrUnauthenticated
+	}
+	storedKey, err := a.apiKeyRepository.GetApiKeyByHash(ctx, HashApiKey(apiKey))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Principal{Subject: storedKey.Name, Method: MethodApiKey}, nil
+}
+
+// AuthenticateToken verifies an HS256 or RS256 signed JWT against the key
+// set. Tokens must expire and name their subject.
+func (a *Authenticator) AuthenticateToken(ctx context.Context, token string) (*Principal, error) {
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{AlgorithmHS256, AlgorithmRS256}),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(a.clock.Now),
+	}
+	if a.tokenOptions.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(a.tokenOptions.Issuer))
+	}
+	if a.tokenOptions.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(a.tokenOptions.Audience))
+	}
+
+	var claims jwt.RegisteredClaims
+	if _, err := jwt.ParseWithClaims(token, &claims, a.keySet.keyFor, parserOptions...); err != nil {
+		return nil, ErrUnauthenticated
+	}
+	if strings.TrimSpace(claims.Subject) == "" {
+		return nil, ErrUnauthenticated
+	}
+	return &Principal{Subject: claims.Subject, Method: MethodToken}, nil
+}
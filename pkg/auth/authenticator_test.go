@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/utils"
+)
+
+var testClock = utils.FixedClock{Time: time.Date(2024, time.June, 1, 9, 30, 0, 0, time.UTC)}
+
+func TestAuthenticateApiKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	apiKey, err := GenerateApiKey()
+	require.NoError(t, err)
+
+	apiKeyRepository := repository.NewMemoryApiKeyRepository()
+	require.NoError(t, apiKeyRepository.CreateApiKey(ctx, &models.ApiKey{Name: "backoffice", Hash: HashApiKey(apiKey)}))
+	authenticator := NewAuthenticator(apiKeyRepository, &KeySet{}, TokenOptions{}, testClock)
+
+	principal, err := authenticator.AuthenticateApiKey(ctx, apiKey)
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Subject: "backoffice", Method: MethodApiKey}, principal)
+
+	_, err = authenticator.AuthenticateApiKey(ctx, apiKey+"x")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = authenticator.AuthenticateApiKey(ctx, "")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("a-shared-secret-of-at-least-32-bytes")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherRsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keySet, err := ParseKeySet([]byte(fmt.Sprintf(`{"keys": [
+		{"kty": "oct", "kid": "shared", "k": %q},
+		{"kty": "RSA", "kid": "signing-2024", "alg": "RS256", "n": %q, "e": %q}
+	]}`,
+		base64.RawURLEncoding.EncodeToString(secret),
+		base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+	)))
+	require.NoError(t, err)
+
+	validClaims := func() jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Subject:   "jane.smith",
+			Issuer:    "https://login.members.com",
+			Audience:  jwt.ClaimStrings{"membership-api"},
+			ExpiresAt: jwt.NewNumericDate(testClock.Now().Add(time.Hour)),
+		}
+	}
+	sign := func(method jwt.SigningMethod, kid string, key any, claims jwt.RegisteredClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(testClock.Now().Add(-time.Minute))
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+	noSubject := validClaims()
+	noSubject.Subject = ""
+	otherAudience := validClaims()
+	otherAudience.Audience = jwt.ClaimStrings{"billing-api"}
+
+	testCases := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "HS256 token", token: sign(jwt.SigningMethodHS256, "shared", secret, validClaims())},
+		{name: "HS256 token without kid", token: sign(jwt.SigningMethodHS256, "", secret, validClaims())},
+		{name: "RS256 token", token: sign(jwt.SigningMethodRS256, "signing-2024", rsaKey, validClaims())},
+		{name: "Token signed with an unknown key", token: sign(jwt.SigningMethodRS256, "signing-2024", otherRsaKey, validClaims()), wantErr: true},
+		{name: "Token naming an unknown kid", token: sign(jwt.SigningMethodHS256, "retired", secret, validClaims()), wantErr: true},
+		{name: "Token using another algorithm", token: sign(jwt.SigningMethodHS512, "shared", secret, validClaims()), wantErr: true},
+		{name: "Expired token", token: sign(jwt.SigningMethodHS256, "shared", secret, expired), wantErr: true},
+		{name: "Token without expiry", token: sign(jwt.SigningMethodHS256, "shared", secret, noExpiry), wantErr: true},
+		{name: "Token without subject", token: sign(jwt.SigningMethodHS256, "shared", secret, noSubject), wantErr: true},
+		{name: "Token for another audience", token: sign(jwt.SigningMethodHS256, "shared", secret, otherAudience), wantErr: true},
+		{name: "Malformed token", token: "not.a.token", wantErr: true},
+	}
+
+	authenticator := NewAuthenticator(repository.NewMemoryApiKeyRepository(), keySet, TokenOptions{
+		Issuer:   "https://login.members.com",
+		Audience: "membership-api",
+	}, testClock)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			principal, err := authenticator.AuthenticateToken(context.Background(), tc.token)
+
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrUnauthenticated)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, &Principal{Subject: "jane.smith", Method: MethodToken}, principal)
+			}
+		})
+	}
+}
+
+func TestParseKeySet(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		keySet  string
+		wantErr bool
+	}{
+		{name: "Empty key set", keySet: `{"keys": []}`},
+		{name: "Not JSON", keySet: `keys`, wantErr: true},
+		{name: "Unsupported key type", keySet: `{"keys": [{"kty": "EC", "crv": "P-256"}]}`, wantErr: true},
+		{name: "Algorithm does not fit the key", keySet: `{"keys": [{"kty": "oct", "alg": "RS256", "k": "c2VjcmV0"}]}`, wantErr: true},
+		{name: "Secret is not base64url", keySet: `{"keys": [{"kty": "oct", "k": "not base64!"}]}`, wantErr: true},
+		{name: "RSA key without exponent", keySet: `{"keys": [{"kty": "RSA", "n": "AQAB"}]}`, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseKeySet([]byte(tc.keySet))
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms tokens may use.
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+var errNoMatchingKey = errors.New("no key matches the token")
+
+// KeySet holds the keys tokens are verified against, read from a JSON Web Key
+// Set (RFC 7517). Shared secrets are "oct" keys for HS256, public keys are
+// "RSA" keys for RS256.
+type KeySet struct {
+	keys []verificationKey
+}
+
+type verificationKey struct {
+	id        string
+	algorithm string
+	key       any
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// LoadKeySet reads a key set from a JWKS file. An empty path gives an empty
+// key set, which rejects every token.
+func LoadKeySet(path string) (*KeySet, error) {
+	if path == "" {
+		return &KeySet{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeySet(data)
+}
+
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid key set: %w", err)
+	}
+
+	keySet := &KeySet{}
+	for i, webKey := range document.Keys {
+		key, err := webKey.verificationKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %d in key set: %w", i, err)
+		}
+		keySet.keys = append(keySet.keys, key)
+	}
+	return keySet, nil
+}
+
+func (k jsonWebKey) verificationKey() (verificationKey, error) {
+	switch k.Kty {
+	case "oct":
+		if k.Alg != "" && k.Alg != AlgorithmHS256 {
+			return verificationKey{}, fmt.Errorf("unsupported algorithm %q for oct key", k.Alg)
+		}
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil || len(secret) == 0 {
+			return verificationKey{}, errors.New("oct key needs a base64url encoded k")
+		}
+		return verificationKey{id: k.Kid, algorithm: AlgorithmHS256, key: secret}, nil
+	case "RSA":
+		if k.Alg != "" && k.Alg != AlgorithmRS256 {
+			return verificationKey{}, fmt.Errorf("unsupported algorithm %q for RSA key", k.Alg)
+		}
+		modulus, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil || len(modulus) == 0 {
+			return verificationKey{}, errors.New("RSA key needs a base64url encoded n")
+		}
+		exponent, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(exponent) == 0 {
+			return verificationKey{}, errors.New("RSA key needs a base64url encoded e")
+		}
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}
+		return verificationKey{id: k.Kid, algorithm: AlgorithmRS256, key: publicKey}, nil
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// keyFor is the jwt.Keyfunc picking the key a token was signed with. Tokens
+// naming a key with kid must use that key, tokens without one are accepted
+// when only one key has their algorithm.
+func (k *KeySet) keyFor(token *jwt.Token) (any, error) {
+	algorithm := token.Method.Alg()
+	kid, _ := token.Header["kid"].(string)
+
+	var match *verificationKey
+	for i := range k.keys {
+		key := &k.keys[i]
+		if key.algorithm != algorithm || (kid != "" && key.id != kid) {
+			continue
+		}
+		if match != nil {
+			return nil, errNoMatchingKey
+		}
+		match = key
+	}
+	if match == nil {
+		return nil, errNoMatchingKey
+	}
+	return match.key, nil
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+)
+
+// Permissions checked by the API routes.
+const (
+	PermissionMemberRead    = "member:read"
+	PermissionMemberWrite   = "member:write"
+	PermissionMemberDelete  = "member:delete"
+	PermissionMemberRestore = "member:restore"
+	PermissionMemberPurge   = "member:purge"
+	PermissionPlanRead      = "plan:read"
+	PermissionPlanWrite     = "plan:write"
+	PermissionAccessAdmin   = "access:admin"
+	PermissionAuditRead     = "audit:read"
+)
+
+// Permissions lists every permission roles can hold.
+var Permissions = []string{
+	PermissionMemberRead,
+	PermissionMemberWrite,
+	PermissionMemberDelete,
+	PermissionMemberRestore,
+	PermissionMemberPurge,
+	PermissionPlanRead,
+	PermissionPlanWrite,
+	PermissionAccessAdmin,
+	PermissionAuditRead,
+}
+
+// Roles created on startup when they do not exist yet, so that there is
+// always an admin role to grant.
+const (
+	RoleAdmin     = "admin"
+	RoleFrontDesk = "front-desk"
+)
+
+var defaultRoles = []models.Role{
+	{Name: RoleAdmin, Permissions: Permissions},
+	{Name: RoleFrontDesk, Permissions: []string{PermissionMemberRead, PermissionMemberWrite, PermissionPlanRead}},
+}
+
+func IsPermission(permission string) bool {
+	return slices.Contains(Permissions, permission)
+}
+
+// EnsureDefaultRoles creates the default roles that are missing. Roles that
+// exist are left as they are, they may have been changed on purpose, except
+// for admin which always gets every permission, including ones added since
+// it was created.
+func EnsureDefaultRoles(ctx context.Context, roleRepository repository.RoleRepositoryI) error {
+	for _, role := range defaultRoles {
+		if role.Name != RoleAdmin {
+			_, err := roleRepository.GetRoleByName(ctx, role.Name)
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := roleRepository.SaveRole(ctx, &role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type AuthorizerI interface {
+	HasPermission(ctx context.Context, principal *Principal, permission string) (bool, error)
+}
+
+// Authorizer looks up a principal's permissions through the roles its grant
+// gives it. Principals without a grant have no permissions.
+type Authorizer struct {
+	roleRepository  repository.RoleRepositoryI
+	grantRepository repository.GrantRepositoryI
+}
+
+func NewAuthorizer(roleRepository repository.RoleRepositoryI, grantRepository repository.GrantRepositoryI) AuthorizerI {
+	return &Authorizer{
+		roleRepository:  roleRepository,
+		grantRepository: grantRepository,
+	}
+}
+
+func (a *Authorizer) HasPermission(ctx context.Context, principal *Principal, permission string) (bool, error) {
+	grant, err := a.grantRepository.GetGrantBySubject(ctx, principal.ID())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, roleName := range grant.Roles {
+		role, err := a.roleRepository.GetRoleByName(ctx, roleName)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if slices.Contains(role.Permissions, permission) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+)
+
+func TestEnsureDefaultRoles(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	roleRepository := repository.NewMemoryRoleRepository()
+	customised := &models.Role{Name: RoleFrontDesk, Permissions: []string{PermissionMemberRead}}
+	require.NoError(t, roleRepository.SaveRole(ctx, customised))
+	outdated := &models.Role{Name: RoleAdmin, Permissions: []string{PermissionMemberRead, PermissionAccessAdmin}}
+	require.NoError(t, roleRepository.SaveRole(ctx, outdated))
+
+	require.NoError(t, EnsureDefaultRoles(ctx, roleRepository))
+
+	admin, err := roleRepository.GetRoleByName(ctx, RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, Permissions, admin.Permissions)
+	frontDesk, err := roleRepository.GetRoleByName(ctx, RoleFrontDesk)
+	require.NoError(t, err)
+	assert.Equal(t, customised, frontDesk)
+}
+
+func TestHasPermission(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	roleRepository := repository.NewMemoryRoleRepository()
+	grantRepository := repository.NewMemoryGrantRepository()
+	require.NoError(t, EnsureDefaultRoles(ctx, roleRepository))
+	require.NoError(t, grantRepository.SaveGrant(ctx, &models.Grant{Subject: "jwt:jane.smith", Roles: []string{"retired", RoleFrontDesk}}))
+	require.NoError(t, grantRepository.SaveGrant(ctx, &models.Grant{Subject: "api_key:backoffice", Roles: []string{RoleAdmin}}))
+
+	testCases := []struct {
+		name       string
+		principal  *Principal
+		permission string
+		expected   bool
+	}{
+		{name: "Front desk can read members", principal: &Principal{Subject: "jane.smith", Method: MethodToken}, permission: PermissionMemberRead, expected: true},
+		{name: "Front desk cannot delete members", principal: &Principal{Subject: "jane.smith", Method: MethodToken}, permission: PermissionMemberDelete},
+		{name: "Admin can purge members", principal: &Principal{Subject: "backoffice", Method: MethodApiKey}, permission: PermissionMemberPurge, expected: true},
+		{name: "Grants are per authentication method", principal: &Principal{Subject: "jane.smith", Method: MethodApiKey}, permission: PermissionMemberRead},
+		{name: "Principal without a grant", principal: &Principal{Subject: "john.doe", Method: MethodToken}, permission: PermissionMemberRead},
+	}
+
+	authorizer := NewAuthorizer(roleRepository, grantRepository)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, err := authorizer.HasPermission(ctx, tc.principal, tc.permission)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, allowed)
+		})
+	}
+}
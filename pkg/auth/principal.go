@@ -0,0 +1,38 @@
+// Package auth works out who is calling the API, from either a static API
+// key or a signed JWT.
+package auth
+
+import "context"
+
+// How a principal authenticated.
+const (
+	MethodApiKey = "api_key"
+	MethodToken  = "jwt"
+)
+
+// Principal is the authenticated caller of a request. Subject is the API
+// key's name or the token's sub claim.
+type Principal struct {
+	Subject string `json:"subject"`
+	Method  string `json:"method"`
+}
+
+// ID identifies the principal in grants. The method is part of it, so an API
+// key cannot share a token subject's roles by sharing its name.
+func (p *Principal) ID() string {
+	return p.Method + ":" + p.Subject
+}
+
+type principalContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the principal, for code below the
+// handlers that needs to know who is calling.
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// FromContext returns the principal stored by NewContext.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return principal, ok
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/service"
+)
+
+type AccessHandlerI interface {
+	GetAllRoles(ctx *gin.Context)
+	SaveRole(ctx *gin.Context)
+	DeleteRoleByName(ctx *gin.Context)
+	GetAllGrants(ctx *gin.Context)
+	SaveGrant(ctx *gin.Context)
+	DeleteGrantBySubject(ctx *gin.Context)
+}
+
+type AccessHandler struct {
+	server        *gin.Engine
+	accessService service.AccessServiceI
+}
+
+func NewAccessHandler(server *gin.Engine, accessService service.AccessServiceI) AccessHandlerI {
+	return &AccessHandler{
+		server:        server,
+		accessService: accessService,
+	}
+}
+
+func (a *AccessHandler) GetAllRoles(ctx *gin.Context) {
+	response := a.accessService.GetAllRoles(ctx)
+	writeResponse(ctx, response)
+}
+
+func (a *AccessHandler) SaveRole(ctx *gin.Context) {
+	var role models.UpdateRole
+	if !bindJsonBody(ctx, &role) {
+		return
+	}
+
+	response := a.accessService.SaveRole(ctx, ctx.Param("name"), &role)
+	writeResponse(ctx, response)
+}
+
+func (a *AccessHandler) DeleteRoleByName(ctx *gin.Context) {
+	response := a.accessService.DeleteRoleByName(ctx, ctx.Param("name"))
+	writeResponse(ctx, response)
+}
+
+func (a *AccessHandler) GetAllGrants(ctx *gin.Context) {
+	response := a.accessService.GetAllGrants(ctx)
+	writeResponse(ctx, response)
+}
+
+func (a *AccessHandler) SaveGrant(ctx *gin.Context) {
+	var grant models.UpdateGrant
+	if !bindJsonBody(ctx, &grant) {
+		return
+	}
+
+	response := a.accessService.SaveGrant(ctx, ctx.Param("subject"), &grant)
+	writeResponse(ctx, response)
+}
+
+func (a *AccessHandler) DeleteGrantBySubject(ctx *gin.Context) {
+	response := a.accessService.DeleteGrantBySubject(ctx, ctx.Param("subject"))
+	writeResponse(ctx, response)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"members.com/membership/pkg/models"
+)
+
+type MockAccessService struct {
+	mock.Mock
+}
+
+func TestSaveRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockAccessService)
+	mockService.On("SaveRole", mock.Anything, "front-desk", &models.UpdateRole{Permissions: []string{"member:read"}}).Return(createResponse(http.StatusOK, &models.Role{Name: "front-desk", Permissions: []string{"member:read"}}))
+
+	accessHandler := NewAccessHandler(router, mockService)
+	router.PUT("/admin/role/:name", accessHandler.SaveRole)
+
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Success saving role",
+			requestBody:          `{"permissions": ["member:read"]}`,
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "{\"name\":\"front-desk\",\"permissions\":[\"member:read\"]}",
+		},
+		{
+			name:                 "Permissions are required",
+			requestBody:          `{}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"fields\":[{\"field\":\"permissions\",\"code\":\"required\",\"message\":\"permissions is required\"}]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPut, "/admin/role/front-desk", bytes.NewBufferString(tc.requestBody))
+			request.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSaveGrant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockAccessService)
+	mockService.On("SaveGrant", mock.Anything, "jwt:jane.smith", &models.UpdateGrant{Roles: []string{"front-desk"}}).Return(createResponse(http.StatusOK, &models.Grant{Subject: "jwt:jane.smith", Roles: []string{"front-desk"}}))
+
+	accessHandler := NewAccessHandler(router, mockService)
+	router.PUT("/admin/grant/:subject", accessHandler.SaveGrant)
+
+	request, _ := http.NewRequest(http.MethodPut, "/admin/grant/jwt:jane.smith", bytes.NewBufferString(`{"roles": ["front-desk"]}`))
+	request.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{\"subject\":\"jwt:jane.smith\",\"roles\":[\"front-desk\"]}", w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func (m *MockAccessService) GetAllRoles(ctx context.Context) models.Response {
+	args := m.Called(ctx)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockAccessService) SaveRole(ctx context.Context, name string, role *models.UpdateRole) models.Response {
+	args := m.Called(ctx, name, role)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockAccessService) DeleteRoleByName(ctx context.Context, name string) models.Response {
+	args := m.Called(ctx, name)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockAccessService) GetAllGrants(ctx context.Context) models.Response {
+	args := m.Called(ctx)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockAccessService) SaveGrant(ctx context.Context, subject string, grant *models.UpdateGrant) models.Response {
+	args := m.Called(ctx, subject, grant)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockAccessService) DeleteGrantBySubject(ctx context.Context, subject string) models.Response {
+	args := m.Called(ctx, subject)
+	return args.Get(0).(models.Response)
+}
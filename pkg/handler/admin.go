@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/service"
+)
+
+type AdminHandlerI interface {
+	GetDeletedMembers(ctx *gin.Context)
+	RestoreMemberById(ctx *gin.Context)
+	PurgeDeletedMembers(ctx *gin.Context)
+}
+
+type AdminHandler struct {
+	server             *gin.Engine
+	memberAdminService service.MemberAdminServiceI
+}
+
+func NewAdminHandler(server *gin.Engine, memberAdminService service.MemberAdminServiceI) AdminHandlerI {
+	return &AdminHandler{
+		server:             server,
+		memberAdminService: memberAdminService,
+	}
+}
+
+func (a *AdminHandler) GetDeletedMembers(ctx *gin.Context) {
+	var query models.MemberQuery
+	if !bindQuery(ctx, &query) {
+		return
+	}
+
+	response := a.memberAdminService.GetDeletedMembers(ctx, query)
+	writeResponse(ctx, response)
+}
+
+func (a *AdminHandler) RestoreMemberById(ctx *gin.Context) {
+	memberId, valid := extractMemberIdfromUrlPath(ctx)
+	if !valid {
+		return
+	}
+
+	response := a.memberAdminService.RestoreMemberById(ctx, int(memberId))
+	setETagHeader(ctx, response)
+	writeResponse(ctx, response)
+}
+
+func (a *AdminHandler) PurgeDeletedMembers(ctx *gin.Context) {
+	response := a.memberAdminService.PurgeDeletedMembers(ctx)
+	writeResponse(ctx, response)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"members.com/membership/pkg/models"
+)
+
+type MockMemberAdminService struct {
+	mock.Mock
+}
+
+func TestGetDeletedMembers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	deleted := &models.MemberPage{
+		Items: []models.Member{{ID: 1, FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com", DateOfBirth: "1990-01-01", DeletedAt: "2024-05-20T10:00:00Z", Version: 3}},
+		Total: 1,
+	}
+
+	mockService := new(MockMemberAdminService)
+	mockService.On("GetDeletedMembers", mock.Anything, models.MemberQuery{LastName: "Doe"}).Return(createResponse(http.StatusOK, deleted))
+
+	adminHandler := NewAdminHandler(router, mockService)
+	router.GET("/admin/members/deleted", adminHandler.GetDeletedMembers)
+
+	request, _ := http.NewRequest(http.MethodGet, "/admin/members/deleted?lastName=Doe", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "\"deletedAt\":\"2024-05-20T10:00:00Z\"")
+	mockService.AssertExpectations(t)
+}
+
+func TestRestoreMemberById(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockMemberAdminService)
+
+	adminHandler := NewAdminHandler(router, mockService)
+	router.POST("/admin/member/:id/restore", adminHandler.RestoreMemberById)
+
+	testCases := []struct {
+		name                 string
+		memberId             string
+		mockAdminService     func(mockService *MockMemberAdminService)
+		expectedStatusCode   int
+		expectedETag         string
+		expectedResponseBody string
+	}{
+		{
+			name:     "Success restoring deleted member",
+			memberId: "1",
+			mockAdminService: func(mockService *MockMemberAdminService) {
+				mockService.On("RestoreMemberById", mock.Anything, 1).Return(createResponse(http.StatusOK, &models.Member{ID: 1, FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com", DateOfBirth: "1990-01-01", Version: 4}))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         `"4"`,
+			expectedResponseBody: "\"id\":1",
+		},
+		{
+			name:     "Invalid member ID",
+			memberId: "1x",
+			mockAdminService: func(mockService *MockMemberAdminService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid member ID\"",
+		},
+		{
+			name:     "Deleted member not found",
+			memberId: "1",
+			mockAdminService: func(mockService *MockMemberAdminService) {
+				mockService.On("RestoreMemberById", mock.Anything, 1).Return(createResponse(http.StatusNotFound, models.ErrorMessage{Error: "Deleted member 1 not found"}))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: "\"detail\":\"Deleted member 1 not found\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockAdminService(mockService)
+			request, _ := http.NewRequest(http.MethodPost, "/admin/member/"+tc.memberId+"/restore", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestPurgeDeletedMembers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockMemberAdminService)
+	mockService.On("PurgeDeletedMembers", mock.Anything).Return(createResponse(http.StatusOK, models.SuccessMessage{Message: "3 deleted members purged"}))
+
+	adminHandler := NewAdminHandler(router, mockService)
+	router.POST("/admin/members/purge", adminHandler.PurgeDeletedMembers)
+
+	request, _ := http.NewRequest(http.MethodPost, "/admin/members/purge", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{\"message\":\"3 deleted members purged\"}", w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func (m *MockMemberAdminService) GetDeletedMembers(ctx context.Context, query models.MemberQuery) models.Response {
+	args := m.Called(ctx, query)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockMemberAdminService) RestoreMemberById(ctx context.Context, memberId int) models.Response {
+	args := m.Called(ctx, memberId)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockMemberAdminService) PurgeDeletedMembers(ctx context.Context) models.Response {
+	args := m.Called(ctx)
+	return args.Get(0).(models.Response)
+}
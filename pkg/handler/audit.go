@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/service"
+)
+
+type AuditHandlerI interface {
+	GetMemberHistory(ctx *gin.Context)
+	GetAuditEntries(ctx *gin.Context)
+}
+
+type AuditHandler struct {
+	server       *gin.Engine
+	auditService service.AuditServiceI
+}
+
+func NewAuditHandler(server *gin.Engine, auditService service.AuditServiceI) AuditHandlerI {
+	return &AuditHandler{
+		server:       server,
+		auditService: auditService,
+	}
+}
+
+func (a *AuditHandler) GetMemberHistory(ctx *gin.Context) {
+	memberId, valid := extractMemberIdfromUrlPath(ctx)
+	if !valid {
+		return
+	}
+
+	var query models.AuditQuery
+	if !bindQuery(ctx, &query) {
+		return
+	}
+
+	response := a.auditService.GetMemberHistory(ctx, int(memberId), query)
+	writeResponse(ctx, response)
+}
+
+func (a *AuditHandler) GetAuditEntries(ctx *gin.Context) {
+	var query models.AuditQuery
+	if !bindQuery(ctx, &query) {
+		return
+	}
+
+	response := a.auditService.GetAuditEntries(ctx, query)
+	writeResponse(ctx, response)
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"members.com/membership/pkg/models"
+)
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func TestGetMemberHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockAuditService)
+
+	auditHandler := NewAuditHandler(router, mockService)
+	router.GET("/member/:id/history", auditHandler.GetMemberHistory)
+
+	history := &models.AuditPage{
+		Items: []models.AuditEntry{{
+			ID:        "665ae9b8c3a5f1d2e4b6a7c8",
+			Actor:     "jwt:jane.smith",
+			Timestamp: "2024-06-01T09:30:00Z",
+			Action:    models.AuditUpdate,
+			MemberId:  100001,
+			Changes:   []models.FieldChange{{Field: "email", Before: "john.doe@gmail.com", After: "john.doe@tennis.com"}},
+		}},
+		Total: 1,
+	}
+
+	testCases := []struct {
+		name                 string
+		url                  string
+		mockAuditService     func(mockService *MockAuditService)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Success fetching member history",
+			url:  "/member/100001/history?limit=10",
+			mockAuditService: func(mockService *MockAuditService) {
+				mockService.On("GetMemberHistory", mock.Anything, 100001, models.AuditQuery{Limit: 10}).Return(createResponse(http.StatusOK, history))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "\"changes\":[{\"field\":\"email\",\"before\":\"john.doe@gmail.com\",\"after\":\"john.doe@tennis.com\"}]",
+		},
+		{
+			name:                 "Invalid member ID",
+			url:                  "/member/1x/history",
+			mockAuditService:     func(mockService *MockAuditService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid member ID\"",
+		},
+		{
+			name:                 "Invalid query parameters",
+			url:                  "/member/100001/history?limit=ten",
+			mockAuditService:     func(mockService *MockAuditService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid query parameters\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockAuditService(mockService)
+			request, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestGetAuditEntries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	query := models.AuditQuery{Actor: "jwt:jane.smith", Action: models.AuditDelete, From: "2024-06-01", To: "2024-06-30"}
+	mockService := new(MockAuditService)
+	mockService.On("GetAuditEntries", mock.Anything, query).Return(createResponse(http.StatusOK, &models.AuditPage{Items: []models.AuditEntry{}}))
+
+	auditHandler := NewAuditHandler(router, mockService)
+	router.GET("/audit", auditHandler.GetAuditEntries)
+
+	request, _ := http.NewRequest(http.MethodGet, "/audit?actor=jwt:jane.smith&action=delete&from=2024-06-01&to=2024-06-30", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{\"items\":[],\"total\":0}", w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func (m *MockAuditService) GetMemberHistory(ctx context.Context, memberId int, query models.AuditQuery) models.Response {
+	args := m.Called(ctx, memberId, query)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockAuditService) GetAuditEntries(ctx context.Context, query models.AuditQuery) models.Response {
+	args := m.Called(ctx, query)
+	return args.Get(0).(models.Response)
+}
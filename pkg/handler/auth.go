@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/auth"
+)
+
+// ApiKeyHeader carries a static API key.
+const ApiKeyHeader = "X-API-Key"
+
+// principalKey is where Authenticate stores the principal on the context.
+const principalKey = "principal"
+
+// Authenticate rejects requests without a valid API key or bearer token with
+// 401, and stores the principal of the others for PrincipalFromContext. The
+// principal is also added to the request's context for auth.FromContext.
+func Authenticate(authenticator auth.AuthenticatorI) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var principal *auth.Principal
+		var err error
+		if apiKey := ctx.GetHeader(ApiKeyHeader); apiKey != "" {
+			principal, err = authenticator.AuthenticateApiKey(ctx, apiKey)
+		} else if token, found := bearerToken(ctx); found {
+			principal, err = authenticator.AuthenticateToken(ctx, token)
+		} else {
+			writeUnauthorized(ctx, "Authentication required")
+			return
+		}
+
+		if errors.Is(err, auth.ErrUnauthenticated) {
+			writeUnauthorized(ctx, "Invalid credentials")
+			return
+		}
+		if err != nil {
+			writeProblem(ctx, http.StatusInternalServerError, "Error authenticating request")
+			return
+		}
+		ctx.Set(principalKey, principal)
+		ctx.Request = ctx.Request.WithContext(auth.NewContext(ctx.Request.Context(), principal))
+		ctx.Next()
+	}
+}
+
+// Authorize returns middleware factories for routes, each letting through
+// only principals holding the given permission. Others get 403 naming it.
+func Authorize(authorizer auth.AuthorizerI) func(permission string) gin.HandlerFunc {
+	return func(permission string) gin.HandlerFunc {
+		return func(ctx *gin.Context) {
+			principal, ok := PrincipalFromContext(ctx)
+			if !ok {
+				writeUnauthorized(ctx, "Authentication required")
+				return
+			}
+
+			allowed, err := authorizer.HasPermission(ctx, principal, permission)
+			if err != nil {
+				writeProblem(ctx, http.StatusInternalServerError, "Error authorizing request")
+				return
+			}
+			if !allowed {
+				writeProblem(ctx, http.StatusForbidden, fmt.Sprintf("Missing permission %s", permission))
+				return
+			}
+			ctx.Next()
+		}
+	}
+}
+
+// PrincipalFromContext returns the caller of an authenticated request.
+func PrincipalFromContext(ctx *gin.Context) (*auth.Principal, bool) {
+	value, _ := ctx.Get(principalKey)
+	principal, ok := value.(*auth.Principal)
+	return principal, ok
+}
+
+func bearerToken(ctx *gin.Context) (string, bool) {
+	scheme, token, found := strings.Cut(ctx.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func writeUnauthorized(ctx *gin.Context, detail string) {
+	ctx.Header("WWW-Authenticate", `Bearer, ApiKey header="`+ApiKeyHeader+`"`)
+	writeProblem(ctx, http.StatusUnauthorized, detail)
+}
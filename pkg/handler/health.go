@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/service"
+)
+
+type HealthHandlerI interface {
+	Liveness(ctx *gin.Context)
+	Readiness(ctx *gin.Context)
+}
+
+type HealthHandler struct {
+	server        *gin.Engine
+	healthService service.HealthServiceI
+}
+
+func NewHealthHandler(server *gin.Engine, healthService service.HealthServiceI) HealthHandlerI {
+	return &HealthHandler{
+		server:        server,
+		healthService: healthService,
+	}
+}
+
+func (h *HealthHandler) Liveness(ctx *gin.Context) {
+	response := h.healthService.Liveness(ctx)
+	writeResponse(ctx, response)
+}
+
+func (h *HealthHandler) Readiness(ctx *gin.Context) {
+	response := h.healthService.Readiness(ctx)
+	writeResponse(ctx, response)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"members.com/membership/pkg/models"
+)
+
+type MockHealthService struct {
+	mock.Mock
+}
+
+func TestHealthProbes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name                 string
+		url                  string
+		mockHealthService    func(mockService *MockHealthService)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Alive",
+			url:  "/healthz",
+			mockHealthService: func(mockService *MockHealthService) {
+				mockService.On("Liveness", mock.Anything).Return(createResponse(http.StatusOK, models.HealthReport{Status: models.HealthOk}))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name: "Ready",
+			url:  "/readyz",
+			mockHealthService: func(mockService *MockHealthService) {
+				mockService.On("Readiness", mock.Anything).Return(createResponse(http.StatusOK, models.HealthReport{
+					Status: models.HealthOk,
+					Checks: map[string]models.DependencyHealth{"mongo": {Status: models.DependencyUp, LatencyMs: 1.5}},
+				}))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"status":"ok","checks":{"mongo":{"status":"up","latencyMs":1.5}}}`,
+		},
+		{
+			name: "Not ready",
+			url:  "/readyz",
+			mockHealthService: func(mockService *MockHealthService) {
+				mockService.On("Readiness", mock.Anything).Return(createResponse(http.StatusServiceUnavailable, models.HealthReport{
+					Status: models.HealthUnavailable,
+					Checks: map[string]models.DependencyHealth{"mongo": {Status: models.DependencyDown, LatencyMs: 2000}},
+				}))
+			},
+			expectedStatusCode:   http.StatusServiceUnavailable,
+			expectedResponseBody: `{"status":"unavailable","checks":{"mongo":{"status":"down","latencyMs":2000}}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			mockService := new(MockHealthService)
+			tc.mockHealthService(mockService)
+			healthHandler := NewHealthHandler(router, mockService)
+			router.GET("/healthz", healthHandler.Liveness)
+			router.GET("/readyz", healthHandler.Readiness)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.JSONEq(t, tc.expectedResponseBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func (m *MockHealthService) Liveness(ctx context.Context) models.Response {
+	args := m.Called(ctx)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockHealthService) Readiness(ctx context.Context) models.Response {
+	args := m.Called(ctx)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockHealthService) Drain() {
+	m.Called()
+}
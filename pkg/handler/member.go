@@ -1,42 +1,65 @@
 package handler
 
 import (
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"members.com/membership/pkg/models"
 	"members.com/membership/pkg/service"
+	"members.com/membership/pkg/validation"
 )
 
 type MemberHandlerI interface {
 	CreateMember(ctx *gin.Context)
 	GetMemberById(ctx *gin.Context)
 	GetAllMembers(ctx *gin.Context)
+	SearchMembers(ctx *gin.Context)
+	ImportMembers(ctx *gin.Context)
+	ExportMembers(ctx *gin.Context)
 	UpdateMemberById(ctx *gin.Context)
+	PatchMemberById(ctx *gin.Context)
 	DeleteMemberById(ctx *gin.Context)
+	RenewMemberById(ctx *gin.Context)
+	ActivateMember(ctx *gin.Context)
+	SuspendMember(ctx *gin.Context)
+	ReactivateMember(ctx *gin.Context)
+	CancelMember(ctx *gin.Context)
 }
 
 type MemberHander struct {
 	server        *gin.Engine
 	memberService service.MemberServiceI
+	logger        *slog.Logger
 }
 
-func NewMemberHandler(server *gin.Engine, memberService service.MemberServiceI) MemberHandlerI {
+func NewMemberHandler(server *gin.Engine, memberService service.MemberServiceI, logger *slog.Logger) MemberHandlerI {
 	return &MemberHander{
 		server:        server,
 		memberService: memberService,
+		logger:        logger,
 	}
 }
 
 func (m *MemberHander) CreateMember(ctx *gin.Context) {
-	var newMember models.Member
+	var newMember models.UpdateMember
 	if !bindJsonBody(ctx, &newMember) {
 		return
 	}
 
-	response := m.memberService.CreateMember(ctx, &newMember)
-	ctx.JSON(response.StatusCode, response.Body)
+	response := m.memberService.CreateMember(ctx, &models.Member{
+		FirstName:   newMember.FirstName,
+		LastName:    newMember.LastName,
+		Email:       newMember.Email,
+		DateOfBirth: newMember.DateOfBirth,
+		PlanId:      newMember.PlanId,
+		StartDate:   newMember.StartDate,
+	})
+	setETagHeader(ctx, response)
+	writeResponse(ctx, response)
 }
 
 func (m *MemberHander) GetMemberById(ctx *gin.Context) {
@@ -46,12 +69,28 @@ func (m *MemberHander) GetMemberById(ctx *gin.Context) {
 	}
 
 	response := m.memberService.GetMemberById(ctx, int(memberId))
-	ctx.JSON(response.StatusCode, response.Body)
+	setETagHeader(ctx, response)
+	writeResponse(ctx, response)
 }
 
 func (m *MemberHander) GetAllMembers(ctx *gin.Context) {
-	response := m.memberService.GetAllMembers(ctx)
-	ctx.JSON(response.StatusCode, response.Body)
+	var query models.MemberQuery
+	if !bindQuery(ctx, &query) {
+		return
+	}
+
+	response := m.memberService.GetAllMembers(ctx, query)
+	writeResponse(ctx, response)
+}
+
+func (m *MemberHander) SearchMembers(ctx *gin.Context) {
+	var query models.MemberSearchQuery
+	if !bindQuery(ctx, &query) {
+		return
+	}
+
+	response := m.memberService.SearchMembers(ctx, query)
+	writeResponse(ctx, response)
 }
 
 func (m *MemberHander) UpdateMemberById(ctx *gin.Context) {
@@ -65,8 +104,40 @@ func (m *MemberHander) UpdateMemberById(ctx *gin.Context) {
 		return
 	}
 
-	response := m.memberService.UpdateMemberById(ctx, &updateMember, int(memberId))
-	ctx.JSON(response.StatusCode, response.Body)
+	expectedVersion, valid := extractIfMatchVersion(ctx)
+	if !valid {
+		return
+	}
+
+	response := m.memberService.UpdateMemberById(ctx, &updateMember, int(memberId), expectedVersion)
+	setETagHeader(ctx, response)
+	writeResponse(ctx, response)
+}
+
+func (m *MemberHander) PatchMemberById(ctx *gin.Context) {
+	memberId, valid := extractMemberIdfromUrlPath(ctx)
+	if !valid {
+		return
+	}
+
+	expectedVersion, valid := extractIfMatchVersion(ctx)
+	if !valid {
+		return
+	}
+
+	document, err := ctx.GetRawData()
+	if err != nil {
+		writeProblem(ctx, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	patch := models.MemberPatch{
+		ContentType: ctx.ContentType(),
+		Document:    document,
+	}
+	response := m.memberService.PatchMemberById(ctx, patch, int(memberId), expectedVersion)
+	setETagHeader(ctx, response)
+	writeResponse(ctx, response)
 }
 
 func (m *MemberHander) DeleteMemberById(ctx *gin.Context) {
@@ -75,13 +146,85 @@ func (m *MemberHander) DeleteMemberById(ctx *gin.Context) {
 		return
 	}
 
-	response := m.memberService.DeleteMemberById(ctx, int(memberId))
-	ctx.JSON(response.StatusCode, response.Body)
+	expectedVersion, valid := extractIfMatchVersion(ctx)
+	if !valid {
+		return
+	}
+
+	response := m.memberService.DeleteMemberById(ctx, int(memberId), expectedVersion)
+	writeResponse(ctx, response)
 }
 
+func (m *MemberHander) RenewMemberById(ctx *gin.Context) {
+	memberId, valid := extractMemberIdfromUrlPath(ctx)
+	if !valid {
+		return
+	}
+
+	expectedVersion, valid := extractIfMatchVersion(ctx)
+	if !valid {
+		return
+	}
+
+	response := m.memberService.RenewMemberById(ctx, int(memberId), expectedVersion)
+	setETagHeader(ctx, response)
+	writeResponse(ctx, response)
+}
+
+func (m *MemberHander) ActivateMember(ctx *gin.Context) {
+	m.changeMemberStatus(ctx, models.TransitionActivate)
+}
+
+func (m *MemberHander) SuspendMember(ctx *gin.Context) {
+	m.changeMemberStatus(ctx, models.TransitionSuspend)
+}
+
+func (m *MemberHander) ReactivateMember(ctx *gin.Context) {
+	m.changeMemberStatus(ctx, models.TransitionReactivate)
+}
+
+func (m *MemberHander) CancelMember(ctx *gin.Context) {
+	m.changeMemberStatus(ctx, models.TransitionCancel)
+}
+
+func (m *MemberHander) changeMemberStatus(ctx *gin.Context, transition string) {
+	var statusChange models.StatusChangeRequest
+	if !bindJsonBody(ctx, &statusChange) {
+		return
+	}
+
+	memberId, valid := extractMemberIdfromUrlPath(ctx)
+	if !valid {
+		return
+	}
+
+	expectedVersion, valid := extractIfMatchVersion(ctx)
+	if !valid {
+		return
+	}
+
+	response := m.memberService.ChangeMemberStatus(ctx, int(memberId), transition, statusChange.Reason, expectedVersion)
+	setETagHeader(ctx, response)
+	writeResponse(ctx, response)
+}
+
+// bindJsonBody reports missing or mistyped fields the same way the service
+// reports invalid ones, anything else as an invalid request.
 func bindJsonBody(ctx *gin.Context, obj interface{}) bool {
 	if err := ctx.ShouldBindJSON(obj); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		if fieldErrors := validation.FromBindingError(err, obj); fieldErrors != nil {
+			writeProblem(ctx, http.StatusBadRequest, validation.ErrorMessage, fieldErrors...)
+			return false
+		}
+		writeProblem(ctx, http.StatusBadRequest, "Invalid request")
+		return false
+	}
+	return true
+}
+
+func bindQuery(ctx *gin.Context, obj interface{}) bool {
+	if err := ctx.ShouldBindQuery(obj); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, "Invalid query parameters")
 		return false
 	}
 	return true
@@ -90,10 +233,31 @@ func bindJsonBody(ctx *gin.Context, obj interface{}) bool {
 func extractMemberIdfromUrlPath(ctx *gin.Context) (int64, bool) {
 	memberId, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid member ID",
-		})
+		writeProblem(ctx, http.StatusBadRequest, "Invalid member ID")
 		return 0, false
 	}
 	return memberId, true
 }
+
+// extractIfMatchVersion reads the member version from the If-Match header.
+// Without the header, or with "*", any version is accepted. Members stored
+// before versions existed are at version 0, so "0" is a valid precondition.
+func extractIfMatchVersion(ctx *gin.Context) (int, bool) {
+	ifMatch := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return service.AnyVersion, true
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`))
+	if err != nil || version < 0 {
+		writeProblem(ctx, http.StatusBadRequest, "Invalid If-Match header")
+		return 0, false
+	}
+	return version, true
+}
+
+func setETagHeader(ctx *gin.Context, response models.Response) {
+	if member, ok := response.Body.(*models.Member); ok && response.StatusCode < http.StatusMultipleChoices {
+		ctx.Header("ETag", fmt.Sprintf(`"%d"`, member.Version))
+	}
+}
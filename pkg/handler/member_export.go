@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/spreadsheet"
+)
+
+// exportContentTypes are offered to the Accept header in order of
+// preference, so clients accepting anything get CSV.
+var exportContentTypes = []string{spreadsheet.CSVContentType, spreadsheet.NDJSONContentType, spreadsheet.XLSXContentType}
+
+// ExportMembers streams the members matching the query as a file download.
+// The format query parameter picks csv, ndjson or xlsx, otherwise the Accept
+// header does.
+func (m *MemberHander) ExportMembers(ctx *gin.Context) {
+	var query models.MemberExportQuery
+	if !bindQuery(ctx, &query) {
+		return
+	}
+	query.Format = ctx.Query("format")
+	if query.Format == "" {
+		switch ctx.NegotiateFormat(exportContentTypes...) {
+		case spreadsheet.CSVContentType:
+			query.Format = spreadsheet.FormatCSV
+		case spreadsheet.NDJSONContentType:
+			query.Format = spreadsheet.FormatNDJSON
+		case spreadsheet.XLSXContentType:
+			query.Format = spreadsheet.FormatXLSX
+		default:
+			writeProblem(ctx, http.StatusNotAcceptable, "Export is available as CSV, NDJSON or XLSX")
+			return
+		}
+	}
+
+	response := m.memberService.ExportMembers(ctx, query)
+	export, ok := response.Body.(*models.MemberExport)
+	if !ok {
+		writeResponse(ctx, response)
+		return
+	}
+
+	// Large exports take longer to stream than the server's write timeout
+	// allows. Writers that cannot change their deadline have none.
+	http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{})
+	ctx.Header("Content-Type", spreadsheet.ContentType(export.Format))
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="members.%s"`, export.Format))
+	ctx.Status(response.StatusCode)
+	// Once streaming has started the status cannot change, so a failure
+	// leaves the client with a truncated file.
+	if err := export.Write(ctx.Writer); err != nil {
+		m.logger.ErrorContext(ctx, "error streaming member export", "format", export.Format, "error", err)
+		ctx.Error(err)
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+)
+
+func TestExportMembers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockMemberService)
+
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
+	router.GET("/members/export", memberHandler.ExportMembers)
+
+	export := func(format string, err error) models.Response {
+		return createResponse(http.StatusOK, &models.MemberExport{Format: format, Write: func(w io.Writer) error {
+			io.WriteString(w, "id\n100001\n")
+			return err
+		}})
+	}
+
+	testCases := []struct {
+		name                 string
+		url                  string
+		accept               string
+		mockMemberService    func(mockService *MockMemberService)
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name: "Format from query parameter",
+			url:  "/members/export?format=ndjson&lastName=Doe&columns=id",
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ExportMembers", mock.Anything, models.MemberExportQuery{LastName: "Doe", Columns: "id", Format: "ndjson"}).Return(export("ndjson", nil))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "application/x-ndjson",
+			expectedResponseBody: "id\n100001\n",
+		},
+		{
+			name:   "Format from Accept header",
+			url:    "/members/export",
+			accept: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ExportMembers", mock.Anything, models.MemberExportQuery{Format: "xlsx"}).Return(export("xlsx", nil))
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		},
+		{
+			name: "CSV by default",
+			url:  "/members/export",
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ExportMembers", mock.Anything, models.MemberExportQuery{Format: "csv"}).Return(export("csv", nil))
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv",
+		},
+		{
+			name: "Failure while streaming truncates the file",
+			url:  "/members/export?format=csv",
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ExportMembers", mock.Anything, models.MemberExportQuery{Format: "csv"}).Return(export("csv", errors.New("repository error")))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/csv",
+			expectedResponseBody: "id\n100001\n",
+		},
+		{
+			name: "Invalid export query",
+			url:  "/members/export?format=pdf",
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ExportMembers", mock.Anything, models.MemberExportQuery{Format: "pdf"}).
+					Return(createResponse(http.StatusBadRequest, models.ErrorMessage{Error: "Export format must be csv, ndjson or xlsx"}))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  models.ProblemContentType,
+			expectedResponseBody: "\"detail\":\"Export format must be csv, ndjson or xlsx\"",
+		},
+		{
+			name:                 "Unacceptable format",
+			url:                  "/members/export",
+			accept:               "application/pdf",
+			mockMemberService:    func(mockService *MockMemberService) {},
+			expectedStatusCode:   http.StatusNotAcceptable,
+			expectedContentType:  models.ProblemContentType,
+			expectedResponseBody: "\"detail\":\"Export is available as CSV, NDJSON or XLSX\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockMemberService(mockService)
+			request, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+			if tc.accept != "" {
+				request.Header.Set("Accept", tc.accept)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/spreadsheet"
+)
+
+// maxImportBytes limits the size of an import file.
+const maxImportBytes = 10 << 20
+
+// ImportMembers reads the members to import either from the "file" field of
+// a multipart form, or from the request body sent as CSV or XLSX. Query
+// parameters such as column[email]=E-mail map fields to other headers.
+func (m *MemberHander) ImportMembers(ctx *gin.Context) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		writeProblem(ctx, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportBytes)
+	rows, valid := readImportRows(ctx)
+	if !valid {
+		return
+	}
+
+	memberImport := &models.MemberImport{
+		Rows:    rows,
+		Columns: ctx.QueryMap("column"),
+		DryRun:  dryRun,
+	}
+	response := m.memberService.ImportMembers(ctx, memberImport)
+	writeResponse(ctx, response)
+}
+
+func readImportRows(ctx *gin.Context) ([][]string, bool) {
+	var file io.Reader
+	var format string
+	var supported bool
+	if ctx.ContentType() == gin.MIMEMultipartPOSTForm {
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			writeImportReadError(ctx, err)
+			return nil, false
+		}
+		format, supported = spreadsheet.FormatOf(fileHeader.Filename, fileHeader.Header.Get("Content-Type"))
+		if supported {
+			formFile, err := fileHeader.Open()
+			if err != nil {
+				writeImportReadError(ctx, err)
+				return nil, false
+			}
+			defer formFile.Close()
+			file = formFile
+		}
+	} else {
+		format, supported = spreadsheet.FormatOf("", ctx.ContentType())
+		file = ctx.Request.Body
+	}
+	if !supported {
+		writeProblem(ctx, http.StatusUnsupportedMediaType, "Import must be CSV or XLSX")
+		return nil, false
+	}
+
+	rows, err := spreadsheet.ReadRows(file, format)
+	if err != nil {
+		writeImportReadError(ctx, err)
+		return nil, false
+	}
+	return rows, true
+}
+
+func writeImportReadError(ctx *gin.Context, err error) {
+	var maxBytesError *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesError):
+		writeProblem(ctx, http.StatusRequestEntityTooLarge, "Import file is larger than 10 MiB")
+	case errors.Is(err, http.ErrMissingFile):
+		writeProblem(ctx, http.StatusBadRequest, "Import file is required")
+	default:
+		writeProblem(ctx, http.StatusBadRequest, "Could not read import file")
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+)
+
+func TestImportMembers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockMemberService)
+
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
+	router.POST("/members/import", memberHandler.ImportMembers)
+
+	csvFile := "First Name,Last Name,E-mail,Date of Birth\nJohn,Doe,john.doe@gmail.com,1990-01-01\n"
+	rows := [][]string{
+		{"First Name", "Last Name", "E-mail", "Date of Birth"},
+		{"John", "Doe", "john.doe@gmail.com", "1990-01-01"},
+	}
+	report := &models.ImportReport{
+		DryRun: true,
+		Total:  1,
+		Rows:   []models.ImportRow{{Row: 2, Status: models.ImportRowValid}},
+	}
+
+	multipartBody := func(filename string) (*bytes.Buffer, string) {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", filename)
+		part.Write([]byte(csvFile))
+		writer.Close()
+		return body, writer.FormDataContentType()
+	}
+
+	testCases := []struct {
+		name                 string
+		url                  string
+		body                 func() (*bytes.Buffer, string)
+		mockMemberService    func(mockService *MockMemberService)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Success importing CSV body",
+			url:  "/members/import?dryRun=true&column[email]=E-mail",
+			body: func() (*bytes.Buffer, string) {
+				return bytes.NewBufferString(csvFile), "text/csv"
+			},
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ImportMembers", mock.Anything, &models.MemberImport{Rows: rows, Columns: map[string]string{"email": "E-mail"}, DryRun: true}).
+					Return(createResponse(http.StatusOK, report))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "\"rows\":[{\"row\":2,\"status\":\"valid\"}]",
+		},
+		{
+			name: "Success importing multipart file",
+			url:  "/members/import",
+			body: func() (*bytes.Buffer, string) {
+				return multipartBody("members.csv")
+			},
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ImportMembers", mock.Anything, &models.MemberImport{Rows: rows, Columns: map[string]string{}}).
+					Return(createResponse(http.StatusOK, report))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "\"total\":1",
+		},
+		{
+			name: "Unsupported file format",
+			url:  "/members/import",
+			body: func() (*bytes.Buffer, string) {
+				return multipartBody("members.txt")
+			},
+			mockMemberService:    func(mockService *MockMemberService) {},
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedResponseBody: "\"detail\":\"Import must be CSV or XLSX\"",
+		},
+		{
+			name: "Missing multipart file",
+			url:  "/members/import",
+			body: func() (*bytes.Buffer, string) {
+				body := new(bytes.Buffer)
+				writer := multipart.NewWriter(body)
+				writer.Close()
+				return body, writer.FormDataContentType()
+			},
+			mockMemberService:    func(mockService *MockMemberService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Import file is required\"",
+		},
+		{
+			name: "Invalid dry run",
+			url:  "/members/import?dryRun=maybe",
+			body: func() (*bytes.Buffer, string) {
+				return bytes.NewBufferString(csvFile), "text/csv"
+			},
+			mockMemberService:    func(mockService *MockMemberService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid query parameters\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockMemberService(mockService)
+			body, contentType := tc.body()
+			request, _ := http.NewRequest(http.MethodPost, tc.url, body)
+			request.Header.Set("Content-Type", contentType)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
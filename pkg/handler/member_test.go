@@ -10,7 +10,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"members.com/membership/pkg/logging"
 	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/service"
+	"members.com/membership/pkg/utils"
+	"members.com/membership/pkg/validation"
 )
 
 type MockMemberService struct {
@@ -32,7 +37,7 @@ func TestCreateMember(t *testing.T) {
 	mockService := new(MockMemberService)
 	mockService.On("CreateMember", mock.Anything, mock.Anything).Return(createResponse(http.StatusOK, member))
 
-	memberHandler := NewMemberHandler(router, mockService)
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
 	router.POST("/member", memberHandler.CreateMember)
 
 	testCases := []struct {
@@ -45,13 +50,13 @@ func TestCreateMember(t *testing.T) {
 			name:                 "Success creating new member",
 			requestBody:          `{"firstName": "John", "lastName": "Doe", "email": "John.Doe@gmail.com", "dateOfBirth": "1990-01-01"}`,
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: "{\"id\":1,\"firstName\":\"John\",\"lastName\":\"Doe\",\"email\":\"John.Doe@gmail.com\",\"dateOfBirth\":\"1990-01-01\"}",
+			expectedResponseBody: "{\"id\":1,\"firstName\":\"John\",\"lastName\":\"Doe\",\"email\":\"John.Doe@gmail.com\",\"dateOfBirth\":\"1990-01-01\",\"version\":0}",
 		},
 		{
-			name:                 "Invalid request",
-			requestBody:          `{"firstName": "John", "lastName": "Doe", "dateOfBirth": "1990-01-01"}`,
+			name:                 "Malformed JSON",
+			requestBody:          `{"firstName": "John",`,
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "{\"error\":\"Invalid request\"}",
+			expectedResponseBody: "\"detail\":\"Invalid request\"",
 		},
 	}
 
@@ -70,6 +75,35 @@ func TestCreateMember(t *testing.T) {
 	}
 }
 
+func TestMemberBodiesReportEveryInvalidField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	memberService := service.NewMemberService(repository.NewMemoryMemberRepository(), repository.NewMemoryPlanRepository(), repository.NewMemoryAuditRepository(),
+		repository.NewMemoryIdAllocator(repository.MemberIdSequence), utils.SystemClock{}, logging.Discard())
+	memberHandler := NewMemberHandler(router, memberService, logging.Discard())
+	router.POST("/member", memberHandler.CreateMember)
+	router.PUT("/member/:id", memberHandler.UpdateMemberById)
+
+	for _, tc := range []struct{ method, url string }{
+		{method: http.MethodPost, url: "/member"},
+		{method: http.MethodPut, url: "/member/100001"},
+	} {
+		t.Run(tc.method, func(t *testing.T) {
+			request, _ := http.NewRequest(tc.method, tc.url, bytes.NewBufferString(`{"lastName": "N", "email": "bad", "dateOfBirth": "x"}`))
+			request.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), `"fields":[`+
+				`{"field":"firstName","code":"required","message":"firstName is required"},`+
+				`{"field":"email","code":"invalid_email","message":"Invalid email"},`+
+				`{"field":"dateOfBirth","code":"invalid_date","message":"Invalid date of birth"}]`)
+		})
+	}
+}
+
 func TestGetMemberById(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -80,11 +114,12 @@ func TestGetMemberById(t *testing.T) {
 		LastName:    "Doe",
 		Email:       "John.Doe@gmail.com",
 		DateOfBirth: "1990-01-01",
+		Version:     3,
 	}
 
 	mockService := new(MockMemberService)
 
-	memberHandler := NewMemberHandler(router, mockService)
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
 	router.GET("/member/:id", memberHandler.GetMemberById)
 
 	testCases := []struct {
@@ -93,6 +128,7 @@ func TestGetMemberById(t *testing.T) {
 		mockMemberService    func(mockService *MockMemberService)
 		expectedStatusCode   int
 		expectedResponseBody string
+		expectedETag         string
 	}{
 		{
 			name:     "Success getting member by id",
@@ -101,7 +137,29 @@ func TestGetMemberById(t *testing.T) {
 				mockService.On("GetMemberById", mock.Anything, 1).Return(createResponse(http.StatusOK, member))
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: "{\"id\":1,\"firstName\":\"John\",\"lastName\":\"Doe\",\"email\":\"John.Doe@gmail.com\",\"dateOfBirth\":\"1990-01-01\"}",
+			expectedResponseBody: "{\"id\":1,\"firstName\":\"John\",\"lastName\":\"Doe\",\"email\":\"John.Doe@gmail.com\",\"dateOfBirth\":\"1990-01-01\",\"version\":3}",
+			expectedETag:         `"3"`,
+		},
+		{
+			name:     "Success getting member with a plan",
+			memberId: "2",
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("GetMemberById", mock.Anything, 2).Return(createResponse(http.StatusOK, &models.Member{
+					ID:               2,
+					FirstName:        "Jane",
+					LastName:         "Doe",
+					Email:            "jane.doe@gmail.com",
+					DateOfBirth:      "1992-02-02",
+					PlanId:           1,
+					StartDate:        "2024-01-01",
+					ExpiryDate:       "2025-01-01",
+					MembershipStatus: models.MembershipActive,
+					Version:          1,
+				}))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "{\"id\":2,\"firstName\":\"Jane\",\"lastName\":\"Doe\",\"email\":\"jane.doe@gmail.com\",\"dateOfBirth\":\"1992-02-02\",\"planId\":1,\"startDate\":\"2024-01-01\",\"expiryDate\":\"2025-01-01\",\"membershipStatus\":\"active\",\"version\":1}",
+			expectedETag:         `"1"`,
 		},
 		{
 			name:     "Invalid member ID",
@@ -109,7 +167,7 @@ func TestGetMemberById(t *testing.T) {
 			mockMemberService: func(mockService *MockMemberService) {
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "{\"error\":\"Invalid member ID\"}",
+			expectedResponseBody: "\"detail\":\"Invalid member ID\"",
 		},
 		{
 			name:     "Member not found",
@@ -118,7 +176,7 @@ func TestGetMemberById(t *testing.T) {
 				mockService.On("GetMemberById", mock.Anything, 1).Return(createResponse(http.StatusNotFound, models.ErrorMessage{Error: "Member 1 not found"}))
 			},
 			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: "{\"error\":\"Member 1 not found\"}",
+			expectedResponseBody: "\"detail\":\"Member 1 not found\"",
 		},
 	}
 
@@ -132,6 +190,7 @@ func TestGetMemberById(t *testing.T) {
 
 			assert.Equal(t, tc.expectedStatusCode, w.Code)
 			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
 			mockService.AssertExpectations(t)
 			mockService.ExpectedCalls = nil
 		})
@@ -142,36 +201,113 @@ func TestGetAllMembers(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 
-	members := []models.Member{
+	members := &models.MemberPage{
+		Items: []models.Member{
+			{
+				ID:          1,
+				FirstName:   "John",
+				LastName:    "Doe",
+				Email:       "John.Doe@gmail.com",
+				DateOfBirth: "1990-01-01",
+			},
+			{
+				ID:          2,
+				FirstName:   "Jane",
+				LastName:    "Smith",
+				Email:       "Jane.Smith@gmail.com",
+				DateOfBirth: "1985-05-05",
+			},
+		},
+		Total:      3,
+		NextCursor: "eyJpZCI6Mn0",
+	}
+
+	mockService := new(MockMemberService)
+
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
+	router.GET("/members", memberHandler.GetAllMembers)
+
+	testCases := []struct {
+		name                 string
+		queryString          string
+		mockMemberService    func(mockService *MockMemberService)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success getting all members",
+			queryString: "",
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("GetAllMembers", mock.Anything, models.MemberQuery{}).Return(createResponse(http.StatusOK, members))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "{\"items\":[{\"id\":1,\"firstName\":\"John\",\"lastName\":\"Doe\",\"email\":\"John.Doe@gmail.com\",\"dateOfBirth\":\"1990-01-01\",\"version\":0},{\"id\":2,\"firstName\":\"Jane\",\"lastName\":\"Smith\",\"email\":\"Jane.Smith@gmail.com\",\"dateOfBirth\":\"1985-05-05\",\"version\":0}],\"total\":3,\"nextCursor\":\"eyJpZCI6Mn0\"}",
+		},
 		{
-			ID:          1,
-			FirstName:   "John",
-			LastName:    "Doe",
-			Email:       "John.Doe@gmail.com",
-			DateOfBirth: "1990-01-01",
+			name:        "Query parameters are passed to the service",
+			queryString: "?limit=2&cursor=eyJpZCI6Mn0&sort=lastName&order=desc&lastName=Doe&emailDomain=gmail.com&dateOfBirthFrom=1980-01-01&dateOfBirthTo=1999-12-31",
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("GetAllMembers", mock.Anything, models.MemberQuery{
+					Limit:           2,
+					Cursor:          "eyJpZCI6Mn0",
+					SortBy:          "lastName",
+					SortOrder:       "desc",
+					LastName:        "Doe",
+					EmailDomain:     "gmail.com",
+					DateOfBirthFrom: "1980-01-01",
+					DateOfBirthTo:   "1999-12-31",
+				}).Return(createResponse(http.StatusOK, members))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "\"total\":3",
 		},
 		{
-			ID:          2,
-			FirstName:   "Jane",
-			LastName:    "Smith",
-			Email:       "Jane.Smith@gmail.com",
-			DateOfBirth: "1985-05-05",
+			name:        "Invalid limit",
+			queryString: "?limit=ten",
+			mockMemberService: func(mockService *MockMemberService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid query parameters\"",
 		},
 	}
 
-	mockService := new(MockMemberService)
-	mockService.On("GetAllMembers", mock.Anything).Return(createResponse(http.StatusOK, members))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockMemberService(mockService)
+			request, _ := http.NewRequest(http.MethodGet, "/members"+tc.queryString, nil)
 
-	memberHandler := NewMemberHandler(router, mockService)
-	router.GET("/members", memberHandler.GetAllMembers)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestSearchMembers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
 
-	request, _ := http.NewRequest(http.MethodGet, "/members", nil)
+	found := &models.MemberPage{
+		Items: []models.Member{{ID: 1, FirstName: "Rafael", LastName: "Nadal", Email: "rafael.nadal@gmail.com", DateOfBirth: "1986-06-03"}},
+		Total: 1,
+	}
+
+	mockService := new(MockMemberService)
+	mockService.On("SearchMembers", mock.Anything, models.MemberSearchQuery{Q: "Rafa Nadal", Limit: 5}).Return(createResponse(http.StatusOK, found))
 
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
+	router.GET("/members/search", memberHandler.SearchMembers)
+
+	request, _ := http.NewRequest(http.MethodGet, "/members/search?q=Rafa+Nadal&limit=5", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, request)
 
-	assert.Equal(t, 200, w.Code)
-	assert.Contains(t, w.Body.String(), "[{\"id\":1,\"firstName\":\"John\",\"lastName\":\"Doe\",\"email\":\"John.Doe@gmail.com\",\"dateOfBirth\":\"1990-01-01\"},{\"id\":2,\"firstName\":\"Jane\",\"lastName\":\"Smith\",\"email\":\"Jane.Smith@gmail.com\",\"dateOfBirth\":\"1985-05-05\"}]")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "\"lastName\":\"Nadal\"")
 	mockService.AssertExpectations(t)
 }
 
@@ -185,40 +321,116 @@ func TestUpdateMemberById(t *testing.T) {
 		LastName:    "Doe",
 		Email:       "John.Doe@gmail.com",
 		DateOfBirth: "1990-01-01",
+		Version:     4,
 	}
 
 	mockService := new(MockMemberService)
 
-	memberHandler := NewMemberHandler(router, mockService)
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
 	router.PUT("/member/:id", memberHandler.UpdateMemberById)
 
 	testCases := []struct {
 		name                 string
 		memberId             string
+		ifMatch              string
 		requestBody          string
 		mockMemberService    func(mockService *MockMemberService)
 		expectedStatusCode   int
 		expectedResponseBody string
+		expectedETag         string
 	}{
 		{
 			name:        "Success updating member by id",
 			memberId:    "1",
-			requestBody: `{"email": "John.Doe@gmail.com"}`,
+			requestBody: `{"firstName": "John", "lastName": "Doe", "email": "John.Doe@gmail.com", "dateOfBirth": "1990-01-01"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("UpdateMemberById", mock.Anything, mock.Anything, 1, service.AnyVersion).Return(createResponse(http.StatusOK, member))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "{\"id\":1,\"firstName\":\"John\",\"lastName\":\"Doe\",\"email\":\"John.Doe@gmail.com\",\"dateOfBirth\":\"1990-01-01\",\"version\":4}",
+			expectedETag:         `"4"`,
+		},
+		{
+			name:        "If-Match version is passed to the service",
+			memberId:    "1",
+			ifMatch:     `W/"3"`,
+			requestBody: `{"firstName": "John", "lastName": "Doe", "email": "John.Doe@gmail.com", "dateOfBirth": "1990-01-01"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("UpdateMemberById", mock.Anything, mock.Anything, 1, 3).Return(createResponse(http.StatusOK, member))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "\"version\":4",
+			expectedETag:         `"4"`,
+		},
+		{
+			name:        "Member modified since If-Match version",
+			memberId:    "1",
+			ifMatch:     `"2"`,
+			requestBody: `{"firstName": "John", "lastName": "Doe", "email": "John.Doe@gmail.com", "dateOfBirth": "1990-01-01"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("UpdateMemberById", mock.Anything, mock.Anything, 1, 2).Return(createResponse(http.StatusPreconditionFailed, models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"}))
+			},
+			expectedStatusCode:   http.StatusPreconditionFailed,
+			expectedResponseBody: "\"detail\":\"Member 1 has been modified, fetch it again and retry\"",
+		},
+		{
+			name:        "If-Match version of a member stored before versions existed",
+			memberId:    "1",
+			ifMatch:     `"0"`,
+			requestBody: `{"firstName": "John", "lastName": "Doe", "email": "John.Doe@gmail.com", "dateOfBirth": "1990-01-01"}`,
 			mockMemberService: func(mockService *MockMemberService) {
-				mockService.On("UpdateMemberById", mock.Anything, mock.Anything, 1).Return(createResponse(http.StatusOK, member))
+				mockService.On("UpdateMemberById", mock.Anything, mock.Anything, 1, 0).Return(createResponse(http.StatusOK, member))
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: "{\"id\":1,\"firstName\":\"John\",\"lastName\":\"Doe\",\"email\":\"John.Doe@gmail.com\",\"dateOfBirth\":\"1990-01-01\"}",
+			expectedResponseBody: "\"version\":4",
+			expectedETag:         `"4"`,
+		},
+		{
+			name:        "Negative If-Match version",
+			memberId:    "1",
+			ifMatch:     `"-1"`,
+			requestBody: `{"firstName": "John", "lastName": "Doe", "email": "John.Doe@gmail.com", "dateOfBirth": "1990-01-01"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid If-Match header\"",
+		},
+		{
+			name:        "Invalid If-Match header",
+			memberId:    "1",
+			ifMatch:     `"abc"`,
+			requestBody: `{"firstName": "John", "lastName": "Doe", "email": "John.Doe@gmail.com", "dateOfBirth": "1990-01-01"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid If-Match header\"",
 		},
 		{
 			name:        "Invalid request where member ID is invalid",
 			memberId:    "1x",
-			requestBody: `{"email": "John.Doe@gmail.com"}`,
+			requestBody: `{"firstName": "John", "lastName": "Doe", "email": "John.Doe@gmail.com", "dateOfBirth": "1990-01-01"}`,
 			mockMemberService: func(mockService *MockMemberService) {
 
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "{\"error\":\"Invalid member ID\"}",
+			expectedResponseBody: "\"detail\":\"Invalid member ID\"",
+		},
+		{
+			name:        "Invalid request where fields are missing",
+			memberId:    "1",
+			requestBody: `{"email": "John.Doe@gmail.com"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("UpdateMemberById", mock.Anything, &models.UpdateMember{Email: "John.Doe@gmail.com"}, 1, service.AnyVersion).Return(createResponse(http.StatusBadRequest, models.ErrorMessage{
+					Error: validation.ErrorMessage,
+					Fields: []models.FieldError{
+						validation.Required("firstName"),
+						validation.Required("lastName"),
+						validation.Required("dateOfBirth"),
+					},
+				}))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"fields\":[{\"field\":\"firstName\",\"code\":\"required\",\"message\":\"firstName is required\"},{\"field\":\"lastName\",\"code\":\"required\",\"message\":\"lastName is required\"},{\"field\":\"dateOfBirth\",\"code\":\"required\",\"message\":\"dateOfBirth is required\"}]",
 		},
 		{
 			name:        "Invalid request where email is invalid",
@@ -228,7 +440,7 @@ func TestUpdateMemberById(t *testing.T) {
 
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "{\"error\":\"Invalid request\"}",
+			expectedResponseBody: "\"fields\":[{\"field\":\"email\",\"code\":\"invalid_type\",\"message\":\"email has the wrong type\"}]",
 		},
 	}
 
@@ -237,12 +449,120 @@ func TestUpdateMemberById(t *testing.T) {
 			tc.mockMemberService(mockService)
 			request, _ := http.NewRequest(http.MethodPut, "/member/"+tc.memberId, bytes.NewBufferString(tc.requestBody))
 			request.Header.Set("Content-Type", "application/json")
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
 
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, request)
 
 			assert.Equal(t, tc.expectedStatusCode, w.Code)
 			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestPatchMemberById(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	member := &models.Member{
+		ID:          1,
+		FirstName:   "John",
+		LastName:    "Doe",
+		Email:       "john.doe@tennis.com",
+		DateOfBirth: "1990-01-01",
+		Version:     5,
+	}
+
+	mockService := new(MockMemberService)
+
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
+	router.PATCH("/member/:id", memberHandler.PatchMemberById)
+
+	testCases := []struct {
+		name                 string
+		memberId             string
+		contentType          string
+		ifMatch              string
+		requestBody          string
+		mockMemberService    func(mockService *MockMemberService)
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedETag         string
+	}{
+		{
+			name:        "Success applying merge patch",
+			memberId:    "1",
+			contentType: "application/merge-patch+json",
+			requestBody: `{"email": "john.doe@tennis.com"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("PatchMemberById", mock.Anything, models.MemberPatch{
+					ContentType: models.MergePatchContentType,
+					Document:    []byte(`{"email": "john.doe@tennis.com"}`),
+				}, 1, service.AnyVersion).Return(createResponse(http.StatusOK, member))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "{\"id\":1,\"firstName\":\"John\",\"lastName\":\"Doe\",\"email\":\"john.doe@tennis.com\",\"dateOfBirth\":\"1990-01-01\",\"version\":5}",
+			expectedETag:         `"5"`,
+		},
+		{
+			name:        "Success applying json patch at If-Match version",
+			memberId:    "1",
+			contentType: "application/json-patch+json; charset=utf-8",
+			ifMatch:     `"4"`,
+			requestBody: `[{"op": "replace", "path": "/email", "value": "john.doe@tennis.com"}]`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("PatchMemberById", mock.Anything, models.MemberPatch{
+					ContentType: models.JsonPatchContentType,
+					Document:    []byte(`[{"op": "replace", "path": "/email", "value": "john.doe@tennis.com"}]`),
+				}, 1, 4).Return(createResponse(http.StatusOK, member))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "\"version\":5",
+			expectedETag:         `"5"`,
+		},
+		{
+			name:        "Unsupported patch content type",
+			memberId:    "1",
+			contentType: "application/json",
+			requestBody: `{"email": "john.doe@tennis.com"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("PatchMemberById", mock.Anything, mock.Anything, 1, service.AnyVersion).Return(createResponse(http.StatusUnsupportedMediaType, models.ErrorMessage{Error: "Patch must be application/merge-patch+json or application/json-patch+json"}))
+			},
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedResponseBody: "\"detail\":\"Patch must be application/merge-patch+json or application/json-patch+json\"",
+		},
+		{
+			name:        "Invalid member ID",
+			memberId:    "1x",
+			contentType: "application/merge-patch+json",
+			requestBody: `{"email": "john.doe@tennis.com"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid member ID\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockMemberService(mockService)
+			request, _ := http.NewRequest(http.MethodPatch, "/member/"+tc.memberId, bytes.NewBufferString(tc.requestBody))
+			request.Header.Set("Content-Type", tc.contentType)
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
 			mockService.AssertExpectations(t)
 			mockService.ExpectedCalls = nil
 		})
@@ -255,12 +575,13 @@ func TestDeleteMemberById(t *testing.T) {
 
 	mockService := new(MockMemberService)
 
-	memberHandler := NewMemberHandler(router, mockService)
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
 	router.DELETE("/member/:id", memberHandler.DeleteMemberById)
 
 	testCases := []struct {
 		name                 string
 		memberId             string
+		ifMatch              string
 		mockMemberService    func(mockService *MockMemberService)
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -269,27 +590,37 @@ func TestDeleteMemberById(t *testing.T) {
 			name:     "Success deleting existing member by id",
 			memberId: "1",
 			mockMemberService: func(mockService *MockMemberService) {
-				mockService.On("DeleteMemberById", mock.Anything, 1).Return(createResponse(http.StatusOK, models.SuccessMessage{Message: "Member 1 deleted"}))
+				mockService.On("DeleteMemberById", mock.Anything, 1, service.AnyVersion).Return(createResponse(http.StatusOK, models.SuccessMessage{Message: "Member 1 deleted"}))
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: "{\"message\":\"Member 1 deleted\"}",
 		},
+		{
+			name:     "Delete only if member is still at If-Match version",
+			memberId: "1",
+			ifMatch:  `"7"`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("DeleteMemberById", mock.Anything, 1, 7).Return(createResponse(http.StatusPreconditionFailed, models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"}))
+			},
+			expectedStatusCode:   http.StatusPreconditionFailed,
+			expectedResponseBody: "\"detail\":\"Member 1 has been modified, fetch it again and retry\"",
+		},
 		{
 			name:     "Invalid member ID",
 			memberId: "1x",
 			mockMemberService: func(mockService *MockMemberService) {
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "{\"error\":\"Invalid member ID\"}",
+			expectedResponseBody: "\"detail\":\"Invalid member ID\"",
 		},
 		{
 			name:     "Member not found",
 			memberId: "1",
 			mockMemberService: func(mockService *MockMemberService) {
-				mockService.On("DeleteMemberById", mock.Anything, 1).Return(createResponse(http.StatusNotFound, models.ErrorMessage{Error: "Member 1 not found"}))
+				mockService.On("DeleteMemberById", mock.Anything, 1, service.AnyVersion).Return(createResponse(http.StatusNotFound, models.ErrorMessage{Error: "Member 1 not found"}))
 			},
 			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: "{\"error\":\"Member 1 not found\"}",
+			expectedResponseBody: "\"detail\":\"Member 1 not found\"",
 		},
 	}
 
@@ -297,12 +628,215 @@ func TestDeleteMemberById(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockMemberService(mockService)
 			request, _ := http.NewRequest(http.MethodDelete, "/member/"+tc.memberId, nil)
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestRenewMemberById(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockMemberService)
+
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
+	router.POST("/member/:id/renew", memberHandler.RenewMemberById)
+
+	renewedMember := &models.Member{
+		ID:               1,
+		FirstName:        "John",
+		LastName:         "Doe",
+		Email:            "john.doe@gmail.com",
+		DateOfBirth:      "1990-01-01",
+		PlanId:           1,
+		StartDate:        "2024-01-01",
+		ExpiryDate:       "2026-01-01",
+		MembershipStatus: models.MembershipActive,
+		Renewals:         []models.Renewal{{RenewedOn: "2024-12-20", PlanId: 1, PreviousExpiryDate: "2025-01-01", ExpiryDate: "2026-01-01"}},
+		Version:          4,
+	}
+
+	testCases := []struct {
+		name                 string
+		memberId             string
+		ifMatch              string
+		mockMemberService    func(mockService *MockMemberService)
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedETag         string
+	}{
+		{
+			name:     "Success renewing member",
+			memberId: "1",
+			ifMatch:  `"3"`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("RenewMemberById", mock.Anything, 1, 3).Return(createResponse(http.StatusOK, renewedMember))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "\"expiryDate\":\"2026-01-01\",\"membershipStatus\":\"active\",\"renewals\":[{\"renewedOn\":\"2024-12-20\",\"planId\":1,\"previousExpiryDate\":\"2025-01-01\",\"expiryDate\":\"2026-01-01\"}],\"version\":4}",
+			expectedETag:         `"4"`,
+		},
+		{
+			name:     "Member without a plan",
+			memberId: "1",
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("RenewMemberById", mock.Anything, 1, service.AnyVersion).Return(createResponse(http.StatusConflict, models.ErrorMessage{Error: "Member 1 has no plan to renew"}))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: "\"detail\":\"Member 1 has no plan to renew\"",
+		},
+		{
+			name:     "Invalid member ID",
+			memberId: "1x",
+			mockMemberService: func(mockService *MockMemberService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid member ID\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockMemberService(mockService)
+			request, _ := http.NewRequest(http.MethodPost, "/member/"+tc.memberId+"/renew", nil)
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
 
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, request)
 
 			assert.Equal(t, tc.expectedStatusCode, w.Code)
 			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestChangeMemberStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockMemberService)
+
+	memberHandler := NewMemberHandler(router, mockService, logging.Discard())
+	router.POST("/member/:id/activate", memberHandler.ActivateMember)
+	router.POST("/member/:id/suspend", memberHandler.SuspendMember)
+	router.POST("/member/:id/reactivate", memberHandler.ReactivateMember)
+	router.POST("/member/:id/cancel", memberHandler.CancelMember)
+
+	suspendedMember := &models.Member{
+		ID:          1,
+		FirstName:   "John",
+		LastName:    "Doe",
+		Email:       "john.doe@gmail.com",
+		DateOfBirth: "1990-01-01",
+		Status:      models.MemberSuspended,
+		StatusChanges: []models.StatusChange{
+			{From: models.MemberActive, To: models.MemberSuspended, Reason: "Unpaid fees", ChangedAt: "2024-06-01T09:30:00Z"},
+		},
+		Version: 4,
+	}
+
+	testCases := []struct {
+		name                 string
+		path                 string
+		requestBody          string
+		ifMatch              string
+		mockMemberService    func(mockService *MockMemberService)
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedETag         string
+	}{
+		{
+			name:        "Success suspending member",
+			path:        "/member/1/suspend",
+			requestBody: `{"reason": "Unpaid fees"}`,
+			ifMatch:     `"3"`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ChangeMemberStatus", mock.Anything, 1, models.TransitionSuspend, "Unpaid fees", 3).Return(createResponse(http.StatusOK, suspendedMember))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "\"status\":\"suspended\",\"statusChanges\":[{\"from\":\"active\",\"to\":\"suspended\",\"reason\":\"Unpaid fees\",\"changedAt\":\"2024-06-01T09:30:00Z\"}],\"version\":4}",
+			expectedETag:         `"4"`,
+		},
+		{
+			name:        "Activate member",
+			path:        "/member/1/activate",
+			requestBody: `{"reason": "Payment received"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ChangeMemberStatus", mock.Anything, 1, models.TransitionActivate, "Payment received", service.AnyVersion).Return(createResponse(http.StatusConflict, models.ErrorMessage{Error: "Member 1 is active and cannot be activated"}))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: "\"detail\":\"Member 1 is active and cannot be activated\"",
+		},
+		{
+			name:        "Reactivate member",
+			path:        "/member/1/reactivate",
+			requestBody: `{"reason": "Fees paid"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ChangeMemberStatus", mock.Anything, 1, models.TransitionReactivate, "Fees paid", service.AnyVersion).Return(createResponse(http.StatusOK, suspendedMember))
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       `"4"`,
+		},
+		{
+			name:        "Cancel member",
+			path:        "/member/1/cancel",
+			requestBody: `{"reason": "Moved abroad"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+				mockService.On("ChangeMemberStatus", mock.Anything, 1, models.TransitionCancel, "Moved abroad", service.AnyVersion).Return(createResponse(http.StatusOK, suspendedMember))
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       `"4"`,
+		},
+		{
+			name:        "Missing reason",
+			path:        "/member/1/suspend",
+			requestBody: `{}`,
+			mockMemberService: func(mockService *MockMemberService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"fields\":[{\"field\":\"reason\",\"code\":\"required\",\"message\":\"reason is required\"}]",
+		},
+		{
+			name:        "Invalid member ID",
+			path:        "/member/1x/cancel",
+			requestBody: `{"reason": "Moved abroad"}`,
+			mockMemberService: func(mockService *MockMemberService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid member ID\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockMemberService(mockService)
+			request, _ := http.NewRequest(http.MethodPost, tc.path, bytes.NewBufferString(tc.requestBody))
+			request.Header.Set("Content-Type", "application/json")
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
 			mockService.AssertExpectations(t)
 			mockService.ExpectedCalls = nil
 		})
@@ -326,17 +860,47 @@ func (m *MockMemberService) GetMemberById(ctx context.Context, memberId int) mod
 	return args.Get(0).(models.Response)
 }
 
-func (m *MockMemberService) GetAllMembers(ctx context.Context) models.Response {
-	args := m.Called(ctx)
+func (m *MockMemberService) GetAllMembers(ctx context.Context, query models.MemberQuery) models.Response {
+	args := m.Called(ctx, query)
 	return args.Get(0).(models.Response)
 }
 
-func (m *MockMemberService) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int) models.Response {
-	args := m.Called(ctx, member, memberId)
+func (m *MockMemberService) SearchMembers(ctx context.Context, query models.MemberSearchQuery) models.Response {
+	args := m.Called(ctx, query)
 	return args.Get(0).(models.Response)
 }
 
-func (m *MockMemberService) DeleteMemberById(ctx context.Context, memberId int) models.Response {
-	args := m.Called(ctx, memberId)
+func (m *MockMemberService) ImportMembers(ctx context.Context, memberImport *models.MemberImport) models.Response {
+	args := m.Called(ctx, memberImport)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockMemberService) ExportMembers(ctx context.Context, query models.MemberExportQuery) models.Response {
+	args := m.Called(ctx, query)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockMemberService) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int, expectedVersion int) models.Response {
+	args := m.Called(ctx, member, memberId, expectedVersion)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockMemberService) PatchMemberById(ctx context.Context, patch models.MemberPatch, memberId int, expectedVersion int) models.Response {
+	args := m.Called(ctx, patch, memberId, expectedVersion)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockMemberService) DeleteMemberById(ctx context.Context, memberId int, expectedVersion int) models.Response {
+	args := m.Called(ctx, memberId, expectedVersion)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockMemberService) RenewMemberById(ctx context.Context, memberId int, expectedVersion int) models.Response {
+	args := m.Called(ctx, memberId, expectedVersion)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockMemberService) ChangeMemberStatus(ctx context.Context, memberId int, transition string, reason string, expectedVersion int) models.Response {
+	args := m.Called(ctx, memberId, transition, reason, expectedVersion)
 	return args.Get(0).(models.Response)
 }
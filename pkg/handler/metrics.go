@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/metrics"
+)
+
+// unmatchedRoute labels requests for paths that have no route, so that
+// arbitrary paths do not become labels.
+const unmatchedRoute = "unmatched"
+
+// Instrument records the method, route, status code and duration of every
+// request.
+func Instrument(recorder *metrics.Metrics) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		recorder.ObserveRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
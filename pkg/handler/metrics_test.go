@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/metrics"
+)
+
+func TestInstrument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := metrics.New()
+	router := gin.New()
+	router.Use(Instrument(recorder), gin.CustomRecovery(Recover(logging.Discard())))
+	router.GET("/member/:id", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	router.GET("/panic", func(ctx *gin.Context) { panic("boom") })
+	router.GET("/metrics", gin.WrapH(recorder.Handler()))
+	router.NoRoute(NotFound)
+
+	for _, url := range []string{"/member/100001", "/member/100002", "/panic", "/unknown/100001"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, url, nil))
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `membership_http_requests_total{method="GET",route="/member/:id",status="200"} 2`)
+	assert.Contains(t, w.Body.String(), `membership_http_requests_total{method="GET",route="/panic",status="500"} 1`)
+	assert.Contains(t, w.Body.String(), `membership_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.NotContains(t, w.Body.String(), "100001")
+}
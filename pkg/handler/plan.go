@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/service"
+)
+
+type PlanHandlerI interface {
+	CreatePlan(ctx *gin.Context)
+	GetPlanById(ctx *gin.Context)
+	GetAllPlans(ctx *gin.Context)
+	UpdatePlanById(ctx *gin.Context)
+	DeletePlanById(ctx *gin.Context)
+}
+
+type PlanHandler struct {
+	server      *gin.Engine
+	planService service.PlanServiceI
+}
+
+func NewPlanHandler(server *gin.Engine, planService service.PlanServiceI) PlanHandlerI {
+	return &PlanHandler{
+		server:      server,
+		planService: planService,
+	}
+}
+
+func (p *PlanHandler) CreatePlan(ctx *gin.Context) {
+	var newPlan models.Plan
+	if !bindJsonBody(ctx, &newPlan) {
+		return
+	}
+
+	response := p.planService.CreatePlan(ctx, &newPlan)
+	writeResponse(ctx, response)
+}
+
+func (p *PlanHandler) GetPlanById(ctx *gin.Context) {
+	planId, valid := extractPlanIdfromUrlPath(ctx)
+	if !valid {
+		return
+	}
+
+	response := p.planService.GetPlanById(ctx, planId)
+	writeResponse(ctx, response)
+}
+
+func (p *PlanHandler) GetAllPlans(ctx *gin.Context) {
+	response := p.planService.GetAllPlans(ctx)
+	writeResponse(ctx, response)
+}
+
+func (p *PlanHandler) UpdatePlanById(ctx *gin.Context) {
+	var updatePlan models.UpdatePlan
+	if !bindJsonBody(ctx, &updatePlan) {
+		return
+	}
+
+	planId, valid := extractPlanIdfromUrlPath(ctx)
+	if !valid {
+		return
+	}
+
+	response := p.planService.UpdatePlanById(ctx, &updatePlan, planId)
+	writeResponse(ctx, response)
+}
+
+func (p *PlanHandler) DeletePlanById(ctx *gin.Context) {
+	planId, valid := extractPlanIdfromUrlPath(ctx)
+	if !valid {
+		return
+	}
+
+	response := p.planService.DeletePlanById(ctx, planId)
+	writeResponse(ctx, response)
+}
+
+func extractPlanIdfromUrlPath(ctx *gin.Context) (int, bool) {
+	planId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		writeProblem(ctx, http.StatusBadRequest, "Invalid plan ID")
+		return 0, false
+	}
+	return planId, true
+}
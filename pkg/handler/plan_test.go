@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/service"
+)
+
+type MockPlanService struct {
+	mock.Mock
+}
+
+func TestCreatePlan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	plan := &models.Plan{ID: 1, Name: "Gold", DurationMonths: 12, PriceCents: 12000, Benefits: []string{"Sauna"}}
+
+	mockService := new(MockPlanService)
+	mockService.On("CreatePlan", mock.Anything, mock.Anything).Return(createResponse(http.StatusCreated, plan))
+
+	planHandler := NewPlanHandler(router, mockService)
+	router.POST("/plan", planHandler.CreatePlan)
+
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Success creating new plan",
+			requestBody:          `{"name": "Gold", "durationMonths": 12, "priceCents": 12000, "benefits": ["Sauna"]}`,
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: "{\"id\":1,\"name\":\"Gold\",\"durationMonths\":12,\"priceCents\":12000,\"benefits\":[\"Sauna\"]}",
+		},
+		{
+			name:                 "Invalid request",
+			requestBody:          `{"name": "Gold", "durationMonths": "twelve"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"fields\":[{\"field\":\"durationMonths\",\"code\":\"invalid_type\",\"message\":\"durationMonths has the wrong type\"}]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPost, "/plan", bytes.NewBufferString(tc.requestBody))
+			request.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPlanBodiesReportEveryInvalidField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	planService := service.NewPlanService(repository.NewMemoryPlanRepository(), repository.NewMemoryMemberRepository(),
+		repository.NewMemoryIdAllocator(repository.PlanIdSequence), logging.Discard())
+	planHandler := NewPlanHandler(router, planService)
+	router.POST("/plan", planHandler.CreatePlan)
+	router.PUT("/plan/:id", planHandler.UpdatePlanById)
+
+	for _, tc := range []struct{ method, url string }{
+		{method: http.MethodPost, url: "/plan"},
+		{method: http.MethodPut, url: "/plan/1"},
+	} {
+		t.Run(tc.method, func(t *testing.T) {
+			request, _ := http.NewRequest(tc.method, tc.url, bytes.NewBufferString(`{"priceCents": -100}`))
+			request.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), `"fields":[`+
+				`{"field":"name","code":"required","message":"name is required"},`+
+				`{"field":"durationMonths","code":"required","message":"durationMonths is required"},`+
+				`{"field":"priceCents","code":"invalid","message":"Price must not be negative"}]`)
+		})
+	}
+}
+
+func TestGetPlanById(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockPlanService)
+
+	planHandler := NewPlanHandler(router, mockService)
+	router.GET("/plan/:id", planHandler.GetPlanById)
+
+	testCases := []struct {
+		name                 string
+		planId               string
+		mockPlanService      func(mockService *MockPlanService)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Success getting plan by id",
+			planId: "1",
+			mockPlanService: func(mockService *MockPlanService) {
+				mockService.On("GetPlanById", mock.Anything, 1).Return(createResponse(http.StatusOK, &models.Plan{ID: 1, Name: "Gold", DurationMonths: 12, Benefits: []string{}}))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "{\"id\":1,\"name\":\"Gold\",\"durationMonths\":12,\"priceCents\":0,\"benefits\":[]}",
+		},
+		{
+			name:   "Invalid plan ID",
+			planId: "gold",
+			mockPlanService: func(mockService *MockPlanService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid plan ID\"",
+		},
+		{
+			name:   "Plan not found",
+			planId: "1",
+			mockPlanService: func(mockService *MockPlanService) {
+				mockService.On("GetPlanById", mock.Anything, 1).Return(createResponse(http.StatusNotFound, models.ErrorMessage{Error: "Plan 1 not found"}))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: "\"detail\":\"Plan 1 not found\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockPlanService(mockService)
+			request, _ := http.NewRequest(http.MethodGet, "/plan/"+tc.planId, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestGetAllPlans(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockPlanService)
+	mockService.On("GetAllPlans", mock.Anything).Return(createResponse(http.StatusOK, []models.Plan{
+		{ID: 1, Name: "Gold", DurationMonths: 12, Benefits: []string{}},
+	}))
+
+	planHandler := NewPlanHandler(router, mockService)
+	router.GET("/plans", planHandler.GetAllPlans)
+
+	request, _ := http.NewRequest(http.MethodGet, "/plans", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "[{\"id\":1,\"name\":\"Gold\",\"durationMonths\":12,\"priceCents\":0,\"benefits\":[]}]", w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdatePlanById(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockPlanService)
+
+	planHandler := NewPlanHandler(router, mockService)
+	router.PUT("/plan/:id", planHandler.UpdatePlanById)
+
+	testCases := []struct {
+		name                 string
+		planId               string
+		requestBody          string
+		mockPlanService      func(mockService *MockPlanService)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success updating plan by id",
+			planId:      "1",
+			requestBody: `{"name": "Platinum", "durationMonths": 24, "priceCents": 20000}`,
+			mockPlanService: func(mockService *MockPlanService) {
+				mockService.On("UpdatePlanById", mock.Anything, &models.UpdatePlan{Name: "Platinum", DurationMonths: 24, PriceCents: 20000}, 1).
+					Return(createResponse(http.StatusOK, &models.Plan{ID: 1, Name: "Platinum", DurationMonths: 24, PriceCents: 20000, Benefits: []string{}}))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "{\"id\":1,\"name\":\"Platinum\",\"durationMonths\":24,\"priceCents\":20000,\"benefits\":[]}",
+		},
+		{
+			name:        "Invalid request",
+			planId:      "1",
+			requestBody: `{"name": 7, "durationMonths": 24}`,
+			mockPlanService: func(mockService *MockPlanService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"fields\":[{\"field\":\"name\",\"code\":\"invalid_type\",\"message\":\"name has the wrong type\"}]",
+		},
+		{
+			name:        "Invalid plan ID",
+			planId:      "1x",
+			requestBody: `{"name": "Platinum", "durationMonths": 24}`,
+			mockPlanService: func(mockService *MockPlanService) {
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "\"detail\":\"Invalid plan ID\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockPlanService(mockService)
+			request, _ := http.NewRequest(http.MethodPut, "/plan/"+tc.planId, bytes.NewBufferString(tc.requestBody))
+			request.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestDeletePlanById(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	mockService := new(MockPlanService)
+
+	planHandler := NewPlanHandler(router, mockService)
+	router.DELETE("/plan/:id", planHandler.DeletePlanById)
+
+	testCases := []struct {
+		name                 string
+		planId               string
+		mockPlanService      func(mockService *MockPlanService)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Success deleting plan by id",
+			planId: "1",
+			mockPlanService: func(mockService *MockPlanService) {
+				mockService.On("DeletePlanById", mock.Anything, 1).Return(createResponse(http.StatusOK, models.SuccessMessage{Message: "Plan 1 deleted"}))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "{\"message\":\"Plan 1 deleted\"}",
+		},
+		{
+			name:   "Plan held by members",
+			planId: "1",
+			mockPlanService: func(mockService *MockPlanService) {
+				mockService.On("DeletePlanById", mock.Anything, 1).Return(createResponse(http.StatusConflict, models.ErrorMessage{Error: "Plan 1 cannot be deleted while 3 member(s) hold it"}))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: "\"detail\":\"Plan 1 cannot be deleted while 3 member(s) hold it\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockPlanService(mockService)
+			request, _ := http.NewRequest(http.MethodDelete, "/plan/"+tc.planId, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedResponseBody)
+			mockService.AssertExpectations(t)
+			mockService.ExpectedCalls = nil
+		})
+	}
+}
+
+func (m *MockPlanService) CreatePlan(ctx context.Context, plan *models.Plan) models.Response {
+	args := m.Called(ctx, plan)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockPlanService) GetPlanById(ctx context.Context, planId int) models.Response {
+	args := m.Called(ctx, planId)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockPlanService) GetAllPlans(ctx context.Context) models.Response {
+	args := m.Called(ctx)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockPlanService) UpdatePlanById(ctx context.Context, plan *models.UpdatePlan, planId int) models.Response {
+	args := m.Called(ctx, plan, planId)
+	return args.Get(0).(models.Response)
+}
+
+func (m *MockPlanService) DeletePlanById(ctx context.Context, planId int) models.Response {
+	args := m.Called(ctx, planId)
+	return args.Get(0).(models.Response)
+}
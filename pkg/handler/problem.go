@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/models"
+)
+
+// problemTypeBlank is the problem type of errors that need no more
+// explanation than their status code, see RFC 7807 section 4.2.
+const problemTypeBlank = "about:blank"
+
+// writeResponse writes a service response. Error bodies are rendered as
+// problem details, so handlers never build error bodies themselves.
+func writeResponse(ctx *gin.Context, response models.Response) {
+	if errorMessage, ok := response.Body.(models.ErrorMessage); ok {
+		writeProblem(ctx, response.StatusCode, errorMessage.Error, errorMessage.Fields...)
+		return
+	}
+	ctx.JSON(response.StatusCode, response.Body)
+}
+
+// writeProblem aborts the request with a problem details body describing it.
+func writeProblem(ctx *gin.Context, status int, detail string, fields ...models.FieldError) {
+	ctx.Header("Content-Type", models.ProblemContentType)
+	ctx.AbortWithStatusJSON(status, models.Problem{
+		Type:     problemTypeBlank,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: ctx.Request.URL.Path,
+		Fields:   fields,
+	})
+}
+
+// NotFound answers requests for paths that have no route.
+func NotFound(ctx *gin.Context) {
+	writeProblem(ctx, http.StatusNotFound, "No resource at this path")
+}
+
+// MethodNotAllowed answers requests for routes that exist with another method.
+func MethodNotAllowed(ctx *gin.Context) {
+	writeProblem(ctx, http.StatusMethodNotAllowed, "Method not supported for this path")
+}
+
+// Recover returns a recovery handler that logs the panic of a handler, with
+// its stack, and answers the request.
+func Recover(logger *slog.Logger) gin.RecoveryFunc {
+	return func(ctx *gin.Context, recovered any) {
+		logger.ErrorContext(ctx, "handler panicked", "panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+		writeProblem(ctx, http.StatusInternalServerError, "Unexpected error")
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+)
+
+func TestProblemResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.CustomRecovery(Recover(logging.Discard())))
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(NotFound)
+	router.NoMethod(MethodNotAllowed)
+
+	router.GET("/member/:id", func(ctx *gin.Context) {
+		writeResponse(ctx, models.Response{StatusCode: http.StatusNotFound, Body: models.ErrorMessage{Error: "Member 1 not found"}})
+	})
+	router.POST("/member", func(ctx *gin.Context) {
+		writeResponse(ctx, models.Response{StatusCode: http.StatusBadRequest, Body: models.ErrorMessage{
+			Error:  "Validation failed",
+			Fields: []models.FieldError{{Field: "email", Code: "invalid_email", Message: "Invalid email"}},
+		}})
+	})
+	router.GET("/panic", func(ctx *gin.Context) {
+		panic("handler bug")
+	})
+
+	testCases := []struct {
+		name                 string
+		method               string
+		path                 string
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Service error",
+			method:               http.MethodGet,
+			path:                 "/member/1",
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"Member 1 not found\",\"instance\":\"/member/1\"}",
+		},
+		{
+			name:                 "Validation error lists fields",
+			method:               http.MethodPost,
+			path:                 "/member",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Validation failed\",\"instance\":\"/member\",\"fields\":[{\"field\":\"email\",\"code\":\"invalid_email\",\"message\":\"Invalid email\"}]}",
+		},
+		{
+			name:                 "Unknown path",
+			method:               http.MethodGet,
+			path:                 "/members/1",
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"No resource at this path\",\"instance\":\"/members/1\"}",
+		},
+		{
+			name:                 "Unsupported method",
+			method:               http.MethodDelete,
+			path:                 "/member",
+			expectedStatusCode:   http.StatusMethodNotAllowed,
+			expectedResponseBody: "{\"type\":\"about:blank\",\"title\":\"Method Not Allowed\",\"status\":405,\"detail\":\"Method not supported for this path\",\"instance\":\"/member\"}",
+		},
+		{
+			name:                 "Handler panics",
+			method:               http.MethodGet,
+			path:                 "/panic",
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"detail\":\"Unexpected error\",\"instance\":\"/panic\"}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, _ := http.NewRequest(tc.method, tc.path, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, models.ProblemContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"members.com/membership/pkg/logging"
+)
+
+// RequestIDHeader carries the ID that correlates the log lines of a request,
+// within this service and across the services it passes through.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength keeps oversized client IDs out of the logs.
+const maxRequestIDLength = 128
+
+// RequestID reuses the caller's X-Request-ID, or generates one, echoes it in
+// the response and adds it to the request's context so every line logged
+// for the request carries it.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		ctx.Header(RequestIDHeader, requestID)
+		ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Request.Context(), requestID))
+		ctx.Next()
+	}
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		valid := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == ':'
+		if !valid {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// LogRequests logs every request once it has been handled, at the error
+// level if it failed with a 5xx status and at the info level otherwise.
+// Requests for skipPaths, such as the health probes, are not logged.
+func LogRequests(logger *slog.Logger, skipPaths ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		if slices.Contains(skipPaths, ctx.Request.URL.Path) {
+			return
+		}
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("durationMs", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", ctx.Writer.Size()),
+			slog.String("clientIp", ctx.ClientIP()),
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.Any("errors", ctx.Errors.Errors()))
+		}
+		logger.LogAttrs(ctx.Request.Context(), level, "request handled", attrs...)
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"members.com/membership/pkg/logging"
+)
+
+func TestRequestLogging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name              string
+		url               string
+		requestID         string
+		expectedStatus    int
+		expectedLevel     string
+		keepsRequestID    bool
+		expectedLineCount int
+	}{
+		{
+			name:              "Propagates the caller's request ID",
+			url:               "/member/100001",
+			requestID:         "abc-123",
+			expectedStatus:    http.StatusOK,
+			expectedLevel:     "INFO",
+			keepsRequestID:    true,
+			expectedLineCount: 1,
+		},
+		{
+			name:              "Generates a request ID",
+			url:               "/member/100001",
+			expectedStatus:    http.StatusOK,
+			expectedLevel:     "INFO",
+			expectedLineCount: 1,
+		},
+		{
+			name:              "Replaces a request ID that cannot be logged safely",
+			url:               "/member/100001",
+			requestID:         "abc\" 123",
+			expectedStatus:    http.StatusOK,
+			expectedLevel:     "INFO",
+			expectedLineCount: 1,
+		},
+		{
+			name:              "Logs panics and failed requests as errors",
+			url:               "/panic",
+			requestID:         "abc-456",
+			expectedStatus:    http.StatusInternalServerError,
+			expectedLevel:     "ERROR",
+			keepsRequestID:    true,
+			expectedLineCount: 2,
+		},
+		{
+			name:              "Skips health probes",
+			url:               "/healthz",
+			expectedStatus:    http.StatusOK,
+			expectedLineCount: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var output bytes.Buffer
+			logger := logging.New(&output, "info")
+			router := gin.New()
+			router.ContextWithFallback = true
+			router.Use(RequestID(), LogRequests(logger, "/healthz"), gin.CustomRecovery(Recover(logger)))
+			router.GET("/member/:id", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+			router.GET("/panic", func(ctx *gin.Context) { panic("handler bug") })
+			router.GET("/healthz", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+			if tc.requestID != "" {
+				req.Header.Set(RequestIDHeader, tc.requestID)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			requestID := w.Header().Get(RequestIDHeader)
+			require.NotEmpty(t, requestID)
+			if tc.keepsRequestID {
+				assert.Equal(t, tc.requestID, requestID)
+			} else {
+				assert.NotEqual(t, tc.requestID, requestID)
+			}
+
+			lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+			if tc.expectedLineCount == 0 {
+				assert.Empty(t, output.String())
+				return
+			}
+			require.Len(t, lines, tc.expectedLineCount)
+			for _, line := range lines {
+				var decoded map[string]any
+				require.NoError(t, json.Unmarshal([]byte(line), &decoded))
+				assert.Equal(t, requestID, decoded[logging.RequestIDKey])
+				assert.Equal(t, tc.expectedLevel, decoded["level"])
+			}
+			var access map[string]any
+			require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &access))
+			assert.Equal(t, "request handled", access["msg"])
+			assert.Equal(t, float64(tc.expectedStatus), access["status"])
+		})
+	}
+}
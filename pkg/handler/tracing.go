@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans the handler layer starts.
+const tracerName = "members.com/membership/pkg/handler"
+
+// Trace starts a server span for every request, continuing the trace of the
+// caller's traceparent header if it sent one, and puts it on the request's
+// context so that the spans of the service, the repository and Mongo
+// commands become its children. Requests for skipPaths, such as the health
+// probes, are not traced.
+func Trace(provider trace.TracerProvider, propagator propagation.TextMapPropagator, skipPaths ...string) gin.HandlerFunc {
+	tracer := provider.Tracer(tracerName)
+	return func(ctx *gin.Context) {
+		if slices.Contains(skipPaths, ctx.Request.URL.Path) {
+			ctx.Next()
+			return
+		}
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		parent := propagator.Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+		spanCtx, span := tracer.Start(parent, fmt.Sprintf("%s %s", ctx.Request.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(ctx.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(ctx.Request.URL.Path),
+				semconv.ClientAddress(ctx.ClientIP()),
+			),
+		)
+		defer span.End()
+		ctx.Request = ctx.Request.WithContext(spanCtx)
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		if len(ctx.Errors) > 0 {
+			span.RecordError(ctx.Errors.Last())
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"members.com/membership/pkg/logging"
+)
+
+func TestTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name               string
+		url                string
+		traceparent        string
+		expectedSpanName   string
+		expectedStatus     int
+		expectedSpanStatus codes.Code
+		continuesTrace     bool
+	}{
+		{
+			name:               "Continues the caller's trace",
+			url:                "/member/100001",
+			traceparent:        "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedSpanName:   "GET /member/:id",
+			expectedStatus:     http.StatusOK,
+			expectedSpanStatus: codes.Unset,
+			continuesTrace:     true,
+		},
+		{
+			name:               "Starts a trace",
+			url:                "/member/100001",
+			expectedSpanName:   "GET /member/:id",
+			expectedStatus:     http.StatusOK,
+			expectedSpanStatus: codes.Unset,
+		},
+		{
+			name:               "Starts a trace for a malformed traceparent",
+			url:                "/member/100001",
+			traceparent:        "00-not-a-trace-01",
+			expectedSpanName:   "GET /member/:id",
+			expectedStatus:     http.StatusOK,
+			expectedSpanStatus: codes.Unset,
+		},
+		{
+			name:               "Marks panics as errors",
+			url:                "/panic",
+			expectedSpanName:   "GET /panic",
+			expectedStatus:     http.StatusInternalServerError,
+			expectedSpanStatus: codes.Error,
+		},
+		{
+			name:               "Names unmatched paths by route",
+			url:                "/unknown/100001",
+			expectedSpanName:   "GET unmatched",
+			expectedStatus:     http.StatusNotFound,
+			expectedSpanStatus: codes.Unset,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spans := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+			var handlerSpan trace.SpanContext
+			router := gin.New()
+			router.ContextWithFallback = true
+			router.Use(Trace(provider, propagation.TraceContext{}, "/healthz"), gin.CustomRecovery(Recover(logging.Discard())))
+			router.GET("/member/:id", func(ctx *gin.Context) {
+				handlerSpan = trace.SpanContextFromContext(ctx)
+				ctx.Status(http.StatusOK)
+			})
+			router.GET("/panic", func(ctx *gin.Context) { panic("boom") })
+			router.NoRoute(NotFound)
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			if tc.traceparent != "" {
+				req.Header.Set("traceparent", tc.traceparent)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			ended := spans.Ended()
+			require.Len(t, ended, 1)
+			span := ended[0]
+			assert.Equal(t, tc.expectedSpanName, span.Name())
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.Equal(t, tc.expectedSpanStatus, span.Status().Code)
+			assert.Contains(t, span.Attributes(), attribute.Int("http.response.status_code", tc.expectedStatus))
+			if tc.continuesTrace {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+				assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+				assert.True(t, span.Parent().IsRemote())
+			} else {
+				assert.False(t, span.Parent().IsValid())
+			}
+			if tc.url == "/member/100001" {
+				assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID())
+			}
+		})
+	}
+}
+
+func TestTraceSkipsPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	router := gin.New()
+	router.Use(Trace(provider, propagation.TraceContext{}, "/healthz"))
+	router.GET("/healthz", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, spans.Ended())
+}
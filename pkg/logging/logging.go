@@ -0,0 +1,72 @@
+// Package logging builds the application's structured logger and carries the
+// request ID on the context so that every line logged for a request has it,
+// along with the IDs of the trace and span it was logged in.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes the request, trace and span IDs are logged as.
+const (
+	RequestIDKey = "requestId"
+	TraceIDKey   = "traceId"
+	SpanIDKey    = "spanId"
+)
+
+type requestIDKey struct{}
+
+// New returns a logger writing JSON lines to w, leaving out lines below level,
+// one of debug, info, warn or error. Lines logged with a context that carries
+// a request ID or a span are given their IDs.
+func New(w io.Writer, level string) *slog.Logger {
+	var minimum slog.Level
+	if err := minimum.UnmarshalText([]byte(level)); err != nil {
+		minimum = slog.LevelInfo
+	}
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: minimum})})
+}
+
+// Discard returns a logger that drops everything, for tests.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID ctx carries, if any.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHandler adds the request ID and span of the context a line is logged
+// with.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (c contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String(RequestIDKey, requestID))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String(TraceIDKey, span.TraceID().String()), slog.String(SpanIDKey, span.SpanID().String()))
+	}
+	return c.Handler.Handle(ctx, record)
+}
+
+func (c contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{c.Handler.WithAttrs(attrs)}
+}
+
+func (c contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{c.Handler.WithGroup(name)}
+}
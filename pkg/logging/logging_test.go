@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func decodeLines(t *testing.T, output *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &decoded))
+		lines = append(lines, decoded)
+	}
+	return lines
+}
+
+func TestNewAddsRequestID(t *testing.T) {
+	var output bytes.Buffer
+	logger := New(&output, "info").With("component", "test")
+
+	logger.InfoContext(WithRequestID(context.Background(), "abc-123"), "with request")
+	logger.InfoContext(context.Background(), "without request")
+
+	lines := decodeLines(t, &output)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "with request", lines[0]["msg"])
+	assert.Equal(t, "abc-123", lines[0][RequestIDKey])
+	assert.Equal(t, "test", lines[0]["component"])
+	assert.NotContains(t, lines[1], RequestIDKey)
+}
+
+func TestNewAddsTraceAndSpanIDs(t *testing.T) {
+	var output bytes.Buffer
+	logger := New(&output, "info")
+	span := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+
+	logger.InfoContext(trace.ContextWithSpanContext(context.Background(), span), "in span")
+	logger.Info("outside span")
+
+	lines := decodeLines(t, &output)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", lines[0][TraceIDKey])
+	assert.Equal(t, "00f067aa0ba902b7", lines[0][SpanIDKey])
+	assert.NotContains(t, lines[1], TraceIDKey)
+}
+
+func TestNewLeavesOutLinesBelowLevel(t *testing.T) {
+	testCases := []struct {
+		level            string
+		expectedMessages []any
+	}{
+		{level: "debug", expectedMessages: []any{"debug", "info", "warn", "error"}},
+		{level: "info", expectedMessages: []any{"info", "warn", "error"}},
+		{level: "warn", expectedMessages: []any{"warn", "error"}},
+		{level: "error", expectedMessages: []any{"error"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level, func(t *testing.T) {
+			var output bytes.Buffer
+			logger := New(&output, tc.level)
+
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Warn("warn")
+			logger.Error("error")
+
+			var messages []any
+			for _, line := range decodeLines(t, &output) {
+				messages = append(messages, line["msg"])
+			}
+			assert.Equal(t, tc.expectedMessages, messages)
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	assert.Equal(t, "", RequestID(context.Background()))
+	assert.Equal(t, "abc-123", RequestID(WithRequestID(context.Background(), "abc-123")))
+}
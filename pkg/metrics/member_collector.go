@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+)
+
+// memberCountTimeout bounds counting members, so a slow database cannot hang
+// a scrape.
+const memberCountTimeout = 5 * time.Second
+
+var membersDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "members"),
+	"Members in the repository, by state: current or deleted and not yet purged.",
+	[]string{"state"}, nil,
+)
+
+// memberCollector counts the members each time the metrics are scraped.
+type memberCollector struct {
+	members repository.MemberRepositoryI
+	logger  *slog.Logger
+}
+
+// NewMemberCollector returns a collector of the number of members.
+func NewMemberCollector(members repository.MemberRepositoryI, logger *slog.Logger) prometheus.Collector {
+	return &memberCollector{members: members, logger: logger}
+}
+
+func (m *memberCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- membersDesc
+}
+
+// Collect leaves out the counts it cannot read rather than failing the
+// whole scrape.
+func (m *memberCollector) Collect(metrics chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), memberCountTimeout)
+	defer cancel()
+
+	states := []struct {
+		name    string
+		deleted bool
+	}{
+		{"current", false},
+		{"deleted", true},
+	}
+	for _, state := range states {
+		page, err := m.members.GetAllMembers(ctx, models.MemberQuery{Limit: 1, Deleted: state.deleted})
+		if err != nil {
+			m.logger.ErrorContext(ctx, "error counting members", "state", state.name, "error", err)
+			continue
+		}
+		metrics <- prometheus.MustNewConstMetric(membersDesc, prometheus.GaugeValue, float64(page.Total), state.name)
+	}
+}
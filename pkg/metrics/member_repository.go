@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+)
+
+// memberRepository records the duration and unexpected errors of every call
+// to the member repository it wraps.
+type memberRepository struct {
+	members repository.MemberRepositoryI
+	metrics *Metrics
+}
+
+// NewMemberRepository wraps members so its calls are recorded.
+func NewMemberRepository(members repository.MemberRepositoryI, metrics *Metrics) repository.MemberRepositoryI {
+	return &memberRepository{
+		members: members,
+		metrics: metrics,
+	}
+}
+
+// observe records an operation that started at start. Missing members,
+// duplicates, version conflicts and bad cursors are answers the callers act
+// on rather than failures, so they are not counted as errors.
+func (m *memberRepository) observe(operation string, start time.Time, err error) {
+	m.metrics.ObserveRepositoryCall(operation, time.Since(start), err != nil && !expectedError(err))
+}
+
+func expectedError(err error) bool {
+	return errors.Is(err, mongo.ErrNoDocuments) ||
+		errors.Is(err, repository.ErrDuplicateMemberId) ||
+		errors.Is(err, repository.ErrDuplicateEmail) ||
+		errors.Is(err, repository.ErrVersionConflict) ||
+		errors.Is(err, repository.ErrInvalidCursor)
+}
+
+func (m *memberRepository) CreateMember(ctx context.Context, member *models.Member) error {
+	start := time.Now()
+	err := m.members.CreateMember(ctx, member)
+	m.observe("CreateMember", start, err)
+	return err
+}
+
+func (m *memberRepository) CreateMembers(ctx context.Context, members []models.Member) (map[int]error, error) {
+	start := time.Now()
+	rowErrors, err := m.members.CreateMembers(ctx, members)
+	m.observe("CreateMembers", start, err)
+	return rowErrors, err
+}
+
+func (m *memberRepository) GetRegisteredEmails(ctx context.Context, emails []string) ([]string, error) {
+	start := time.Now()
+	registered, err := m.members.GetRegisteredEmails(ctx, emails)
+	m.observe("GetRegisteredEmails", start, err)
+	return registered, err
+}
+
+func (m *memberRepository) GetMemberById(ctx context.Context, memberId int) (*models.Member, error) {
+	start := time.Now()
+	member, err := m.members.GetMemberById(ctx, memberId)
+	m.observe("GetMemberById", start, err)
+	return member, err
+}
+
+func (m *memberRepository) GetAllMembers(ctx context.Context, query models.MemberQuery) (*models.MemberPage, error) {
+	start := time.Now()
+	page, err := m.members.GetAllMembers(ctx, query)
+	m.observe("GetAllMembers", start, err)
+	return page, err
+}
+
+// StreamMembers records opening the stream. Errors met while reading it are
+// recorded when it is closed.
+func (m *memberRepository) StreamMembers(ctx context.Context, query models.MemberQuery) (repository.MemberIterator, error) {
+	start := time.Now()
+	members, err := m.members.StreamMembers(ctx, query)
+	m.observe("StreamMembers", start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &memberIterator{MemberIterator: members, metrics: m.metrics}, nil
+}
+
+func (m *memberRepository) SearchMembers(ctx context.Context, query models.MemberSearchQuery) (*models.MemberPage, error) {
+	start := time.Now()
+	page, err := m.members.SearchMembers(ctx, query)
+	m.observe("SearchMembers", start, err)
+	return page, err
+}
+
+func (m *memberRepository) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int, version int) error {
+	start := time.Now()
+	err := m.members.UpdateMemberById(ctx, member, memberId, version)
+	m.observe("UpdateMemberById", start, err)
+	return err
+}
+
+func (m *memberRepository) DeleteMemberById(ctx context.Context, memberId int, version int, deletedAt string) error {
+	start := time.Now()
+	err := m.members.DeleteMemberById(ctx, memberId, version, deletedAt)
+	m.observe("DeleteMemberById", start, err)
+	return err
+}
+
+func (m *memberRepository) RestoreMemberById(ctx context.Context, memberId int) (*models.Member, error) {
+	start := time.Now()
+	member, err := m.members.RestoreMemberById(ctx, memberId)
+	m.observe("RestoreMemberById", start, err)
+	return member, err
+}
+
+func (m *memberRepository) PurgeDeletedMembers(ctx context.Context, deletedBefore string) ([]int, error) {
+	start := time.Now()
+	purged, err := m.members.PurgeDeletedMembers(ctx, deletedBefore)
+	m.observe("PurgeDeletedMembers", start, err)
+	return purged, err
+}
+
+func (m *memberRepository) CountMembersWithPlan(ctx context.Context, planId int) (int64, error) {
+	start := time.Now()
+	count, err := m.members.CountMembersWithPlan(ctx, planId)
+	m.observe("CountMembersWithPlan", start, err)
+	return count, err
+}
+
+func (m *memberRepository) RenewMember(ctx context.Context, memberId int, version int, renewal models.Renewal) error {
+	start := time.Now()
+	err := m.members.RenewMember(ctx, memberId, version, renewal)
+	m.observe("RenewMember", start, err)
+	return err
+}
+
+func (m *memberRepository) LapseExpiredMembers(ctx context.Context, today string) ([]int, error) {
+	start := time.Now()
+	lapsed, err := m.members.LapseExpiredMembers(ctx, today)
+	m.observe("LapseExpiredMembers", start, err)
+	return lapsed, err
+}
+
+func (m *memberRepository) ChangeMemberStatus(ctx context.Context, memberId int, version int, change models.StatusChange) error {
+	start := time.Now()
+	err := m.members.ChangeMemberStatus(ctx, memberId, version, change)
+	m.observe("ChangeMemberStatus", start, err)
+	return err
+}
+
+// memberIterator counts an error that ends a member stream early.
+type memberIterator struct {
+	repository.MemberIterator
+	metrics *Metrics
+}
+
+func (i *memberIterator) Close(ctx context.Context) error {
+	if err := i.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		i.metrics.repositoryErrors.WithLabelValues("StreamMembers").Inc()
+	}
+	return i.MemberIterator.Close(ctx)
+}
@@ -0,0 +1,88 @@
+// Package metrics exposes the application's Prometheus metrics: HTTP
+// requests, repository calls and member counts.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "membership"
+
+// Metrics holds the collectors the application records to, in a registry of
+// its own so tests can create as many as they like.
+type Metrics struct {
+	registry           *prometheus.Registry
+	requests           *prometheus.CounterVec
+	requestDuration    *prometheus.HistogramVec
+	repositoryDuration *prometheus.HistogramVec
+	repositoryErrors   *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to handle HTTP requests, by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Time taken by member repository operations, by operation.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"operation"}),
+		repositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_errors_total",
+			Help:      "Member repository operations that failed unexpectedly, by operation.",
+		}, []string{"operation"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.repositoryDuration,
+		m.repositoryErrors,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a handled HTTP request. route is the route's
+// pattern, such as /member/:id, so that member IDs do not become labels.
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	statusCode := strconv.Itoa(status)
+	m.requests.WithLabelValues(method, route, statusCode).Inc()
+	m.requestDuration.WithLabelValues(method, route, statusCode).Observe(duration.Seconds())
+}
+
+// ObserveRepositoryCall records a member repository operation.
+func (m *Metrics) ObserveRepositoryCall(operation string, duration time.Duration, failed bool) {
+	m.repositoryDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	if failed {
+		m.repositoryErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// Register adds a collector, such as the member counts, to the metrics.
+func (m *Metrics) Register(collector prometheus.Collector) {
+	m.registry.MustRegister(collector)
+}
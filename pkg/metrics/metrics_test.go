@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+)
+
+// failingMemberRepository fails the calls the tests need to fail.
+type failingMemberRepository struct {
+	repository.MemberRepositoryI
+	err error
+}
+
+func (f *failingMemberRepository) GetMemberById(ctx context.Context, memberId int) (*models.Member, error) {
+	return nil, f.err
+}
+
+func (f *failingMemberRepository) GetAllMembers(ctx context.Context, query models.MemberQuery) (*models.MemberPage, error) {
+	if query.Deleted {
+		return nil, f.err
+	}
+	return f.MemberRepositoryI.GetAllMembers(ctx, query)
+}
+
+func TestObserveRequest(t *testing.T) {
+	t.Parallel()
+
+	recorder := New()
+	recorder.ObserveRequest("GET", "/member/:id", 200, 20*time.Millisecond)
+	recorder.ObserveRequest("GET", "/member/:id", 200, 30*time.Millisecond)
+	recorder.ObserveRequest("GET", "/member/:id", 404, time.Millisecond)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(recorder.requests.WithLabelValues("GET", "/member/:id", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(recorder.requests.WithLabelValues("GET", "/member/:id", "404")))
+	assert.Equal(t, 2, testutil.CollectAndCount(recorder.requestDuration))
+}
+
+func TestMemberRepositoryRecordsCalls(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	recorder := New()
+	members := NewMemberRepository(repository.NewMemoryMemberRepository(), recorder)
+
+	require.NoError(t, members.CreateMember(ctx, &models.Member{ID: 100001, FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com"}))
+	err := members.CreateMember(ctx, &models.Member{ID: 100001, FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com"})
+	require.ErrorIs(t, err, repository.ErrDuplicateMemberId)
+	_, err = members.GetMemberById(ctx, 100002)
+	require.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	expected := `
+# HELP membership_repository_operation_errors_total Member repository operations that failed unexpectedly, by operation.
+# TYPE membership_repository_operation_errors_total counter
+`
+	assert.NoError(t, testutil.CollectAndCompare(recorder.repositoryErrors, strings.NewReader(expected)))
+	assert.Equal(t, 2, testutil.CollectAndCount(recorder.repositoryDuration))
+}
+
+func TestMemberRepositoryCountsUnexpectedErrors(t *testing.T) {
+	t.Parallel()
+
+	recorder := New()
+	members := NewMemberRepository(&failingMemberRepository{err: errors.New("connection reset")}, recorder)
+
+	_, err := members.GetMemberById(context.Background(), 100001)
+
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, 1.0, testutil.ToFloat64(recorder.repositoryErrors.WithLabelValues("GetMemberById")))
+}
+
+func TestMemberRepositoryIgnoresCancelledStreams(t *testing.T) {
+	t.Parallel()
+
+	recorder := New()
+	memory := repository.NewMemoryMemberRepository()
+	require.NoError(t, memory.CreateMember(context.Background(), &models.Member{ID: 100001, FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com"}))
+	members := NewMemberRepository(memory, recorder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := members.StreamMembers(ctx, models.MemberQuery{})
+	require.NoError(t, err)
+	cancel()
+	assert.False(t, stream.Next(ctx))
+	require.NoError(t, stream.Close(context.Background()))
+	assert.Equal(t, 0.0, testutil.ToFloat64(recorder.repositoryErrors.WithLabelValues("StreamMembers")))
+}
+
+func TestMemberCollector(t *testing.T) {
+	t.Parallel()
+
+	memory := repository.NewMemoryMemberRepository()
+	ctx := context.Background()
+	for id := 100001; id <= 100003; id++ {
+		require.NoError(t, memory.CreateMember(ctx, &models.Member{ID: id, FirstName: "John", LastName: "Doe", Email: fmt.Sprintf("john.doe%d@gmail.com", id)}))
+	}
+	require.NoError(t, memory.DeleteMemberById(ctx, 100003, 0, "2024-06-01T09:30:00Z"))
+
+	expected := `
+# HELP membership_members Members in the repository, by state: current or deleted and not yet purged.
+# TYPE membership_members gauge
+membership_members{state="current"} 2
+membership_members{state="deleted"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(NewMemberCollector(memory, logging.Discard()), strings.NewReader(expected)))
+}
+
+func TestMemberCollectorLeavesOutFailedCounts(t *testing.T) {
+	t.Parallel()
+
+	members := &failingMemberRepository{MemberRepositoryI: repository.NewMemoryMemberRepository(), err: errors.New("connection reset")}
+
+	expected := `
+# HELP membership_members Members in the repository, by state: current or deleted and not yet purged.
+# TYPE membership_members gauge
+membership_members{state="current"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(NewMemberCollector(members, logging.Discard()), strings.NewReader(expected)))
+}
@@ -0,0 +1,24 @@
+package models
+
+// Role is a named set of permissions, such as member:read.
+type Role struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// UpdateRole is the body that creates or replaces a role.
+type UpdateRole struct {
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// Grant gives a principal roles. Subject is the principal's ID, such as
+// api_key:backoffice or jwt:jane.smith.
+type Grant struct {
+	Subject string   `json:"subject"`
+	Roles   []string `json:"roles"`
+}
+
+// UpdateGrant is the body that creates or replaces a grant.
+type UpdateGrant struct {
+	Roles []string `json:"roles" binding:"required"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// ApiKey is a static credential for a client of the API. Only a hash of the
+// key is stored, the key itself is shown once when it is created.
+type ApiKey struct {
+	Name      string `json:"name"`
+	Hash      string `json:"-"`
+	CreatedAt string `json:"createdAt"`
+}
@@ -0,0 +1,57 @@
+package models
+
+// Actions recorded in the audit log.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+	AuditRenew   = "renew"
+	AuditLapse   = "lapse"
+	AuditStatus  = "status"
+)
+
+// AuditSystemActor is recorded as the actor of changes made without an
+// authenticated caller, such as those of background jobs.
+const AuditSystemActor = "system"
+
+// AuditEntry records one change to a member. Entries are only ever appended,
+// never changed. Actor is the principal ID of the caller, such as
+// jwt:jane.smith.
+type AuditEntry struct {
+	ID        string        `json:"id"`
+	Actor     string        `json:"actor"`
+	Timestamp string        `json:"timestamp"`
+	Action    string        `json:"action"`
+	MemberId  int           `json:"memberId"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// FieldChange is the value of a member field before and after a change.
+// Before is missing for fields that were not set, After for fields that were
+// cleared.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// AuditQuery filters the audit log. From and To are an inclusive range of
+// dates in YYYY-MM-DD format.
+type AuditQuery struct {
+	Limit    int    `form:"limit"`
+	Cursor   string `form:"cursor"`
+	MemberId int    `form:"memberId"`
+	Actor    string `form:"actor"`
+	Action   string `form:"action"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+}
+
+// AuditPage holds audit entries newest first.
+type AuditPage struct {
+	Items      []AuditEntry `json:"items"`
+	Total      int64        `json:"total"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Statuses of the application and of its dependencies.
+const (
+	HealthOk          = "ok"
+	HealthUnavailable = "unavailable"
+	HealthDraining    = "draining"
+	DependencyUp      = "up"
+	DependencyDown    = "down"
+)
+
+// HealthReport is the body of the liveness and readiness endpoints. Checks is
+// keyed by dependency name and only reported for readiness.
+type HealthReport struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyHealth `json:"checks,omitempty"`
+}
+
+// DependencyHealth is the outcome of checking one dependency.
+type DependencyHealth struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+}
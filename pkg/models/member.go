@@ -1,16 +1,122 @@
 package models
 
+// Membership statuses are derived from a member's expiry date whenever the
+// member is returned. Members past their expiry date are also marked as
+// lapsed by a background job, see LapsedOn.
+const (
+	MembershipActive       = "active"
+	MembershipExpiringSoon = "expiring_soon"
+	MembershipLapsed       = "lapsed"
+)
+
+// Lifecycle statuses of a member. New members start out pending, members
+// stored before statuses existed count as active.
+const (
+	MemberPending   = "pending"
+	MemberActive    = "active"
+	MemberSuspended = "suspended"
+	MemberCancelled = "cancelled"
+)
+
+// Transitions between lifecycle statuses, see the service for which
+// statuses each one is allowed from.
+const (
+	TransitionActivate   = "activate"
+	TransitionSuspend    = "suspend"
+	TransitionReactivate = "reactivate"
+	TransitionCancel     = "cancel"
+)
+
 type Member struct {
-	ID          int    `json:"id"`
-	FirstName   string `json:"firstName" binding:"required"`
-	LastName    string `json:"lastName" binding:"required"`
-	Email       string `json:"email" binding:"required"`
-	DateOfBirth string `json:"dateOfBirth" binding:"required"`
+	ID               int            `json:"id"`
+	FirstName        string         `json:"firstName"`
+	LastName         string         `json:"lastName"`
+	Email            string         `json:"email"`
+	DateOfBirth      string         `json:"dateOfBirth"`
+	PlanId           int            `json:"planId,omitempty"`
+	StartDate        string         `json:"startDate,omitempty"`
+	ExpiryDate       string         `json:"expiryDate,omitempty"`
+	LapsedOn         string         `json:"lapsedOn,omitempty"`
+	MembershipStatus string         `json:"membershipStatus,omitempty" bson:"-"`
+	Renewals         []Renewal      `json:"renewals,omitempty"`
+	Status           string         `json:"status,omitempty"`
+	StatusChanges    []StatusChange `json:"statusChanges,omitempty"`
+	DeletedAt        string         `json:"deletedAt,omitempty"`
+	Version          int            `json:"version"`
+}
+
+// Renewal records a membership being extended by another plan term.
+type Renewal struct {
+	RenewedOn          string `json:"renewedOn"`
+	PlanId             int    `json:"planId"`
+	PreviousExpiryDate string `json:"previousExpiryDate"`
+	ExpiryDate         string `json:"expiryDate"`
+}
+
+// StatusChange records a member moving from one lifecycle status to another.
+type StatusChange struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Reason    string `json:"reason"`
+	ChangedAt string `json:"changedAt"`
+}
+
+// StatusChangeRequest is the body of the lifecycle transition endpoints.
+type StatusChangeRequest struct {
+	Reason string `json:"reason" binding:"required"`
 }
 
+// UpdateMember holds the fields of a member that clients can set. POST
+// creates a member from them, PUT replaces all of them, PATCH documents are
+// applied to them. The expiry date follows from the plan and start date and
+// the lapse date is set by the lapse job, so clients cannot set either.
 type UpdateMember struct {
 	FirstName   string `json:"firstName"`
 	LastName    string `json:"lastName"`
 	Email       string `json:"email"`
 	DateOfBirth string `json:"dateOfBirth"`
+	PlanId      int    `json:"planId,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	ExpiryDate  string `json:"-"`
+	LapsedOn    string `json:"-"`
+}
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JsonPatchContentType  = "application/json-patch+json"
+)
+
+// MemberPatch is a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902)
+// document, told apart by its content type.
+type MemberPatch struct {
+	ContentType string
+	Document    []byte
+}
+
+type MemberQuery struct {
+	Limit           int    `form:"limit"`
+	Cursor          string `form:"cursor"`
+	SortBy          string `form:"sort"`
+	SortOrder       string `form:"order"`
+	LastName        string `form:"lastName"`
+	EmailDomain     string `form:"emailDomain"`
+	DateOfBirthFrom string `form:"dateOfBirthFrom"`
+	DateOfBirthTo   string `form:"dateOfBirthTo"`
+	// Deleted lists soft deleted members instead of current ones. It is only
+	// set by the admin endpoints, never from query parameters.
+	Deleted bool `form:"-"`
+}
+
+// MemberSearchQuery finds members by their names and email address. Q is
+// free text, matched by prefix and despite typos and diacritics.
+type MemberSearchQuery struct {
+	Q      string `form:"q"`
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
+
+type MemberPage struct {
+	Items      []Member `json:"items"`
+	Total      int64    `json:"total"`
+	NextCursor string   `json:"nextCursor,omitempty"`
 }
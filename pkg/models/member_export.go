@@ -0,0 +1,24 @@
+package models
+
+import "io"
+
+// MemberExportQuery selects the members to export with the filters and sort
+// order of MemberQuery. Columns is a comma separated list of member fields,
+// every exportable field when empty.
+type MemberExportQuery struct {
+	SortBy          string `form:"sort"`
+	SortOrder       string `form:"order"`
+	LastName        string `form:"lastName"`
+	EmailDomain     string `form:"emailDomain"`
+	DateOfBirthFrom string `form:"dateOfBirthFrom"`
+	DateOfBirthTo   string `form:"dateOfBirthTo"`
+	Columns         string `form:"columns"`
+	Format          string `form:"-"`
+}
+
+// MemberExport is the body of a successful export. Write streams the members
+// to the response in Format as they are read.
+type MemberExport struct {
+	Format string
+	Write  func(w io.Writer) error
+}
@@ -0,0 +1,38 @@
+package models
+
+// MemberImport is an uploaded file of members to create. Rows[0] holds the
+// column headers. Columns maps member fields, such as firstName, to the
+// header of the column holding them when it is not the field name itself.
+type MemberImport struct {
+	Rows    [][]string
+	Columns map[string]string
+	DryRun  bool
+}
+
+// Outcomes of importing a row.
+const (
+	ImportRowImported = "imported"
+	ImportRowValid    = "valid"
+	ImportRowFailed   = "failed"
+)
+
+// ImportReport tells how every row of an import fared. On a dry run nothing
+// is created and rows that would be imported are reported as valid.
+type ImportReport struct {
+	DryRun   bool        `json:"dryRun"`
+	Total    int         `json:"total"`
+	Imported int         `json:"imported"`
+	Failed   int         `json:"failed"`
+	Rows     []ImportRow `json:"rows"`
+}
+
+// ImportRow is the outcome of one row. Row counts from 1 for the header row,
+// like spreadsheet programs do. A failed row has field Errors, or an Error
+// when the row itself was fine but could not be written.
+type ImportRow struct {
+	Row      int          `json:"row"`
+	Status   string       `json:"status"`
+	MemberId int          `json:"memberId,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// Plan is a membership tier that members can hold. A membership on the plan
+// runs for DurationMonths from the member's start date.
+type Plan struct {
+	ID             int      `json:"id"`
+	Name           string   `json:"name"`
+	DurationMonths int      `json:"durationMonths"`
+	PriceCents     int64    `json:"priceCents"`
+	Benefits       []string `json:"benefits"`
+}
+
+// UpdatePlan holds the fields of a plan that clients can change. PUT replaces
+// all of them.
+type UpdatePlan struct {
+	Name           string   `json:"name"`
+	DurationMonths int      `json:"durationMonths"`
+	PriceCents     int64    `json:"priceCents"`
+	Benefits       []string `json:"benefits"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// ProblemContentType is the media type of Problem responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Every error the API returns
+// has this shape. Fields lists the invalid fields of a request that failed
+// validation.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Fields   []FieldError `json:"fields,omitempty"`
+}
@@ -5,8 +5,19 @@ type Response struct {
 	Body       any
 }
 
+// ErrorMessage is the body of a failed service response. Handlers render it
+// as a Problem.
 type ErrorMessage struct {
-	Error string `json:"error" binding:"required"`
+	Error  string       `json:"error" binding:"required"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError describes a problem with one field of a request body. Code is
+// stable for clients to act on, Message is meant for people.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 type SuccessMessage struct {
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+const (
+	apiKeyNameIndex = "name_unique"
+	apiKeyHashIndex = "hash_unique"
+)
+
+var ErrDuplicateApiKey = errors.New("api key already exists")
+
+type ApiKeyRepositoryI interface {
+	CreateApiKey(ctx context.Context, apiKey *models.ApiKey) error
+	GetApiKeyByHash(ctx context.Context, hash string) (*models.ApiKey, error)
+}
+
+type ApiKeyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewApiKeyRepository(collection *mongo.Collection) ApiKeyRepositoryI {
+	return &ApiKeyRepository{
+		collection: collection,
+	}
+}
+
+// CreateApiKey stores the key, rejecting a name or hash that is already
+// taken with ErrDuplicateApiKey.
+func (a *ApiKeyRepository) CreateApiKey(ctx context.Context, apiKey *models.ApiKey) error {
+	_, err := a.collection.InsertOne(ctx, apiKey)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateApiKey
+	}
+	return err
+}
+
+func (a *ApiKeyRepository) GetApiKeyByHash(ctx context.Context, hash string) (*models.ApiKey, error) {
+	var apiKey models.ApiKey
+	err := a.collection.FindOne(ctx, bson.M{"hash": hash}).Decode(&apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// CreateApiKeyIndexes creates the indexes the apikeys collection relies on.
+// It is safe to call on every startup.
+func CreateApiKeyIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetName(apiKeyNameIndex).SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetName(apiKeyHashIndex).SetUnique(true),
+		},
+	})
+	return err
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+// runApiKeyRepositoryConformance checks the behaviour every ApiKeyRepositoryI
+// implementation must share. newRepository must return an empty repository.
+func runApiKeyRepositoryConformance(t *testing.T, newRepository func(t *testing.T) ApiKeyRepositoryI) {
+	newApiKey := func(name string, hash string) *models.ApiKey {
+		return &models.ApiKey{Name: name, Hash: hash, CreatedAt: "2024-06-01T09:30:00Z"}
+	}
+
+	t.Run("Create and get api key by hash", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateApiKey(ctx, newApiKey("ci", "3a7bd3e2")))
+
+		apiKey, err := repo.GetApiKeyByHash(ctx, "3a7bd3e2")
+		require.NoError(t, err)
+		assert.Equal(t, newApiKey("ci", "3a7bd3e2"), apiKey)
+
+		_, err = repo.GetApiKeyByHash(ctx, "unknown")
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+	})
+
+	t.Run("Names and hashes are unique", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateApiKey(ctx, newApiKey("ci", "3a7bd3e2")))
+
+		err := repo.CreateApiKey(ctx, newApiKey("ci", "9f86d081"))
+		assert.True(t, errors.Is(err, ErrDuplicateApiKey))
+		err = repo.CreateApiKey(ctx, newApiKey("backoffice", "3a7bd3e2"))
+		assert.True(t, errors.Is(err, ErrDuplicateApiKey))
+	})
+}
+
+func TestMemoryApiKeyRepositoryConformance(t *testing.T) {
+	t.Parallel()
+
+	runApiKeyRepositoryConformance(t, func(t *testing.T) ApiKeyRepositoryI {
+		return NewMemoryApiKeyRepository()
+	})
+}
+
+// TestMongoApiKeyRepositoryConformance runs against a real MongoDB server when
+// MONGODB_TEST_URI is set, like TestMongoMemberRepositoryConformance.
+func TestMongoApiKeyRepositoryConformance(t *testing.T) {
+	mongoUri := os.Getenv("MONGODB_TEST_URI")
+	if mongoUri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoUri))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Disconnect(context.Background())
+	})
+
+	runApiKeyRepositoryConformance(t, func(t *testing.T) ApiKeyRepositoryI {
+		mongoDb := client.Database(fmt.Sprintf("membership_test_%d", time.Now().UnixNano()))
+		t.Cleanup(func() {
+			mongoDb.Drop(context.Background())
+		})
+		require.NoError(t, CreateApiKeyIndexes(context.Background(), mongoDb.Collection("apikeys")))
+		return NewApiKeyRepository(mongoDb.Collection("apikeys"))
+	})
+}
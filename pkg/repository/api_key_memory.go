@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/models"
+)
+
+// MemoryApiKeyRepository keeps API keys in process memory, mirroring
+// ApiKeyRepository.
+type MemoryApiKeyRepository struct {
+	mu      sync.RWMutex
+	apiKeys map[string]models.ApiKey
+}
+
+func NewMemoryApiKeyRepository() ApiKeyRepositoryI {
+	return &MemoryApiKeyRepository{
+		apiKeys: make(map[string]models.ApiKey),
+	}
+}
+
+func (a *MemoryApiKeyRepository) CreateApiKey(ctx context.Context, apiKey *models.ApiKey) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, existing := range a.apiKeys {
+		if existing.Name == apiKey.Name || existing.Hash == apiKey.Hash {
+			return ErrDuplicateApiKey
+		}
+	}
+	a.apiKeys[apiKey.Hash] = *apiKey
+	return nil
+}
+
+func (a *MemoryApiKeyRepository) GetApiKeyByHash(ctx context.Context, hash string) (*models.ApiKey, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	apiKey, exists := a.apiKeys[hash]
+	if !exists {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &apiKey, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+const (
+	DefaultAuditPageSize = 20
+	MaxAuditPageSize     = 100
+)
+
+const (
+	auditIdIndex     = "id_unique"
+	auditMemberIndex = "memberid_id"
+)
+
+// AuditRepositoryI is append only, audit entries cannot be changed or
+// removed once they are written.
+type AuditRepositoryI interface {
+	AppendAuditEntry(ctx context.Context, entry *models.AuditEntry) error
+	GetAuditEntries(ctx context.Context, query models.AuditQuery) (*models.AuditPage, error)
+}
+
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuditRepository(collection *mongo.Collection) AuditRepositoryI {
+	return &AuditRepository{
+		collection: collection,
+	}
+}
+
+// AppendAuditEntry stores the entry under a new ID, which it also sets on
+// entry.
+func (a *AuditRepository) AppendAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	entry.ID = newAuditEntryId()
+	_, err := a.collection.InsertOne(ctx, entry)
+	return err
+}
+
+func (a *AuditRepository) GetAuditEntries(ctx context.Context, query models.AuditQuery) (*models.AuditPage, error) {
+	query = withAuditQueryDefaults(query)
+	filter := auditQueryFilter(query)
+
+	total, err := a.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Cursor != "" {
+		if !isAuditEntryId(query.Cursor) {
+			return nil, ErrInvalidCursor
+		}
+		filter = append(filter, bson.E{Key: "id", Value: bson.D{{Key: "$lt", Value: query.Cursor}}})
+	}
+
+	// One extra entry is fetched to find out whether there is a next page.
+	opts := options.Find().SetSort(bson.D{{Key: "id", Value: -1}}).SetLimit(int64(query.Limit + 1))
+	result, err := a.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.AuditEntry, 0, query.Limit+1)
+	if err := result.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return newAuditPage(entries, total, query), nil
+}
+
+// CreateAuditIndexes creates the indexes the audit collection relies on. It
+// is safe to call on every startup.
+func CreateAuditIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetName(auditIdIndex).SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "memberid", Value: 1}, {Key: "id", Value: -1}},
+			Options: options.Index().SetName(auditMemberIndex),
+		},
+	})
+	return err
+}
+
+// Audit entry IDs are object IDs in hex. They start with the time they were
+// created, so ordering entries by ID orders them by when they were appended.
+func newAuditEntryId() string {
+	return primitive.NewObjectID().Hex()
+}
+
+func isAuditEntryId(id string) bool {
+	_, err := primitive.ObjectIDFromHex(id)
+	return err == nil
+}
+
+func withAuditQueryDefaults(query models.AuditQuery) models.AuditQuery {
+	if query.Limit == 0 {
+		query.Limit = DefaultAuditPageSize
+	}
+	return query
+}
+
+// newAuditPage trims entries, fetched newest first with one extra beyond the
+// limit, down to a page and sets the next cursor when more entries follow.
+func newAuditPage(entries []models.AuditEntry, total int64, query models.AuditQuery) *models.AuditPage {
+	page := &models.AuditPage{
+		Items: entries,
+		Total: total,
+	}
+	if len(entries) > query.Limit {
+		page.Items = entries[:query.Limit]
+		page.NextCursor = page.Items[query.Limit-1].ID
+	}
+	return page
+}
+
+// auditDayAfter returns the day after date, the exclusive upper bound of
+// timestamps on or before date. Timestamps are RFC 3339 in UTC, so they
+// compare with dates as strings.
+func auditDayAfter(date string) string {
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return date
+	}
+	return day.AddDate(0, 0, 1).Format(time.DateOnly)
+}
+
+// auditQueryFilter builds the Mongo filter for the query's filters. The
+// cursor is not included so the result can also be used to count matches.
+func auditQueryFilter(query models.AuditQuery) bson.D {
+	filter := bson.D{}
+	if query.MemberId != 0 {
+		filter = append(filter, bson.E{Key: "memberid", Value: query.MemberId})
+	}
+	if query.Actor != "" {
+		filter = append(filter, bson.E{Key: "actor", Value: query.Actor})
+	}
+	if query.Action != "" {
+		filter = append(filter, bson.E{Key: "action", Value: query.Action})
+	}
+	timestamp := bson.D{}
+	if query.From != "" {
+		timestamp = append(timestamp, bson.E{Key: "$gte", Value: query.From})
+	}
+	if query.To != "" {
+		timestamp = append(timestamp, bson.E{Key: "$lt", Value: auditDayAfter(query.To)})
+	}
+	if len(timestamp) > 0 {
+		filter = append(filter, bson.E{Key: "timestamp", Value: timestamp})
+	}
+	return filter
+}
+
+// auditEntryMatchesQuery applies auditQueryFilter to an entry held in
+// memory.
+func auditEntryMatchesQuery(entry *models.AuditEntry, query models.AuditQuery) bool {
+	if query.MemberId != 0 && entry.MemberId != query.MemberId {
+		return false
+	}
+	if query.Actor != "" && entry.Actor != query.Actor {
+		return false
+	}
+	if query.Action != "" && entry.Action != query.Action {
+		return false
+	}
+	if query.From != "" && entry.Timestamp < query.From {
+		return false
+	}
+	if query.To != "" && entry.Timestamp >= auditDayAfter(query.To) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+// runAuditRepositoryConformance checks the behaviour every AuditRepositoryI
+// implementation must share. newRepository must return an empty repository.
+func runAuditRepositoryConformance(t *testing.T, newRepository func(t *testing.T) AuditRepositoryI) {
+	newEntry := func(actor string, timestamp string, action string, memberId int) *models.AuditEntry {
+		return &models.AuditEntry{
+			Actor:     actor,
+			Timestamp: timestamp,
+			Action:    action,
+			MemberId:  memberId,
+			Changes:   []models.FieldChange{{Field: "email", Before: "rafael.nadal@gmail.com", After: "rafael.nadal@tennis.com"}},
+		}
+	}
+
+	appendEntries := func(t *testing.T, repo AuditRepositoryI) []*models.AuditEntry {
+		entries := []*models.AuditEntry{
+			newEntry("jwt:jane.smith", "2024-05-31T17:00:00Z", models.AuditCreate, 100001),
+			newEntry("jwt:jane.smith", "2024-06-01T09:30:00Z", models.AuditUpdate, 100001),
+			newEntry("api_key:backoffice", "2024-06-01T10:00:00Z", models.AuditCreate, 100002),
+			newEntry("jwt:john.doe", "2024-06-02T08:00:00Z", models.AuditDelete, 100001),
+		}
+		for _, entry := range entries {
+			require.NoError(t, repo.AppendAuditEntry(context.Background(), entry))
+		}
+		return entries
+	}
+
+	t.Run("Append and list audit entries newest first", func(t *testing.T) {
+		repo := newRepository(t)
+		entries := appendEntries(t, repo)
+		for _, entry := range entries {
+			assert.NotEmpty(t, entry.ID)
+		}
+
+		page, err := repo.GetAuditEntries(context.Background(), models.AuditQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), page.Total)
+		assert.Empty(t, page.NextCursor)
+		assert.Equal(t, []models.AuditEntry{*entries[3], *entries[2], *entries[1], *entries[0]}, page.Items)
+	})
+
+	t.Run("Page through audit entries", func(t *testing.T) {
+		repo := newRepository(t)
+		entries := appendEntries(t, repo)
+		ctx := context.Background()
+
+		page, err := repo.GetAuditEntries(ctx, models.AuditQuery{Limit: 3})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 3)
+		assert.Equal(t, entries[1].ID, page.NextCursor)
+
+		page, err = repo.GetAuditEntries(ctx, models.AuditQuery{Limit: 3, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), page.Total)
+		assert.Equal(t, []models.AuditEntry{*entries[0]}, page.Items)
+		assert.Empty(t, page.NextCursor)
+
+		_, err = repo.GetAuditEntries(ctx, models.AuditQuery{Cursor: "not-a-cursor"})
+		assert.True(t, errors.Is(err, ErrInvalidCursor))
+	})
+
+	t.Run("Filter audit entries", func(t *testing.T) {
+		repo := newRepository(t)
+		entries := appendEntries(t, repo)
+
+		testCases := []struct {
+			name     string
+			query    models.AuditQuery
+			expected []models.AuditEntry
+		}{
+			{name: "By member", query: models.AuditQuery{MemberId: 100001}, expected: []models.AuditEntry{*entries[3], *entries[1], *entries[0]}},
+			{name: "By actor", query: models.AuditQuery{Actor: "jwt:jane.smith"}, expected: []models.AuditEntry{*entries[1], *entries[0]}},
+			{name: "By action", query: models.AuditQuery{Action: models.AuditCreate}, expected: []models.AuditEntry{*entries[2], *entries[0]}},
+			{name: "By inclusive date range", query: models.AuditQuery{From: "2024-06-01", To: "2024-06-01"}, expected: []models.AuditEntry{*entries[2], *entries[1]}},
+			{name: "Nothing matches", query: models.AuditQuery{MemberId: 100001, Action: models.AuditRenew}, expected: []models.AuditEntry{}},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				page, err := repo.GetAuditEntries(context.Background(), tc.query)
+
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, page.Items)
+				assert.Equal(t, int64(len(tc.expected)), page.Total)
+			})
+		}
+	})
+}
+
+func TestMemoryAuditRepositoryConformance(t *testing.T) {
+	t.Parallel()
+
+	runAuditRepositoryConformance(t, func(t *testing.T) AuditRepositoryI {
+		return NewMemoryAuditRepository()
+	})
+}
+
+// TestMongoAuditRepositoryConformance runs against a real MongoDB server when
+// MONGODB_TEST_URI is set, like TestMongoMemberRepositoryConformance.
+func TestMongoAuditRepositoryConformance(t *testing.T) {
+	mongoUri := os.Getenv("MONGODB_TEST_URI")
+	if mongoUri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoUri))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Disconnect(context.Background())
+	})
+
+	runAuditRepositoryConformance(t, func(t *testing.T) AuditRepositoryI {
+		mongoDb := client.Database(fmt.Sprintf("membership_test_%d", time.Now().UnixNano()))
+		t.Cleanup(func() {
+			mongoDb.Drop(context.Background())
+		})
+		require.NoError(t, CreateAuditIndexes(context.Background(), mongoDb.Collection("audit")))
+		return NewAuditRepository(mongoDb.Collection("audit"))
+	})
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"members.com/membership/pkg/models"
+)
+
+// MemoryAuditRepository keeps the audit log in process memory, mirroring
+// AuditRepository.
+type MemoryAuditRepository struct {
+	mu      sync.RWMutex
+	entries []models.AuditEntry
+}
+
+func NewMemoryAuditRepository() AuditRepositoryI {
+	return &MemoryAuditRepository{}
+}
+
+func (a *MemoryAuditRepository) AppendAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry.ID = newAuditEntryId()
+	a.entries = append(a.entries, copyAuditEntry(entry))
+	return nil
+}
+
+func (a *MemoryAuditRepository) GetAuditEntries(ctx context.Context, query models.AuditQuery) (*models.AuditPage, error) {
+	query = withAuditQueryDefaults(query)
+	if query.Cursor != "" && !isAuditEntryId(query.Cursor) {
+		return nil, ErrInvalidCursor
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var total int64
+	entries := make([]models.AuditEntry, 0)
+	// Entries are held in the order they were appended, so walking them
+	// backwards lists them newest first.
+	for i := len(a.entries) - 1; i >= 0; i-- {
+		entry := &a.entries[i]
+		if !auditEntryMatchesQuery(entry, query) {
+			continue
+		}
+		total++
+		if (query.Cursor == "" || entry.ID < query.Cursor) && len(entries) <= query.Limit {
+			entries = append(entries, copyAuditEntry(entry))
+		}
+	}
+	return newAuditPage(entries, total, query), nil
+}
+
+// copyAuditEntry copies the changes too, so that stored entries cannot be
+// changed through the caller's copy.
+func copyAuditEntry(entry *models.AuditEntry) models.AuditEntry {
+	copied := *entry
+	copied.Changes = slices.Clone(entry.Changes)
+	return copied
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+const grantSubjectIndex = "subject_unique"
+
+type GrantRepositoryI interface {
+	SaveGrant(ctx context.Context, grant *models.Grant) error
+	GetGrantBySubject(ctx context.Context, subject string) (*models.Grant, error)
+	GetAllGrants(ctx context.Context) ([]models.Grant, error)
+	DeleteGrantBySubject(ctx context.Context, subject string) error
+	CountGrantsWithRole(ctx context.Context, role string) (int64, error)
+}
+
+type GrantRepository struct {
+	collection *mongo.Collection
+}
+
+func NewGrantRepository(collection *mongo.Collection) GrantRepositoryI {
+	return &GrantRepository{
+		collection: collection,
+	}
+}
+
+// SaveGrant creates the grant or replaces the one for the same subject.
+func (g *GrantRepository) SaveGrant(ctx context.Context, grant *models.Grant) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := g.collection.ReplaceOne(ctx, bson.M{"subject": grant.Subject}, grant, opts)
+	return err
+}
+
+func (g *GrantRepository) GetGrantBySubject(ctx context.Context, subject string) (*models.Grant, error) {
+	var grant models.Grant
+	err := g.collection.FindOne(ctx, bson.M{"subject": subject}).Decode(&grant)
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// GetAllGrants returns every grant ordered by subject.
+func (g *GrantRepository) GetAllGrants(ctx context.Context) ([]models.Grant, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "subject", Value: 1}})
+	result, err := g.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	grants := make([]models.Grant, 0)
+	if err := result.All(ctx, &grants); err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+func (g *GrantRepository) DeleteGrantBySubject(ctx context.Context, subject string) error {
+	result, err := g.collection.DeleteOne(ctx, bson.M{"subject": subject})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// CountGrantsWithRole counts the grants giving the role, so that roles still
+// in use are not deleted.
+func (g *GrantRepository) CountGrantsWithRole(ctx context.Context, role string) (int64, error) {
+	return g.collection.CountDocuments(ctx, bson.M{"roles": role})
+}
+
+// CreateGrantIndexes creates the indexes the grants collection relies on. It
+// is safe to call on every startup.
+func CreateGrantIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "subject", Value: 1}},
+		Options: options.Index().SetName(grantSubjectIndex).SetUnique(true),
+	})
+	return err
+}
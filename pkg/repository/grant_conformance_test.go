@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+// runGrantRepositoryConformance checks the behaviour every GrantRepositoryI
+// implementation must share. newRepository must return an empty repository.
+func runGrantRepositoryConformance(t *testing.T, newRepository func(t *testing.T) GrantRepositoryI) {
+	t.Run("Save, get and replace grant", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		_, err := repo.GetGrantBySubject(ctx, "jwt:jane.smith")
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+
+		grant := &models.Grant{Subject: "jwt:jane.smith", Roles: []string{"front-desk"}}
+		require.NoError(t, repo.SaveGrant(ctx, grant))
+		grant.Roles = []string{"admin"}
+		require.NoError(t, repo.SaveGrant(ctx, grant))
+
+		fetched, err := repo.GetGrantBySubject(ctx, "jwt:jane.smith")
+		require.NoError(t, err)
+		assert.Equal(t, grant, fetched)
+	})
+
+	t.Run("Get all grants and count those with a role", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		grants, err := repo.GetAllGrants(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, grants)
+
+		require.NoError(t, repo.SaveGrant(ctx, &models.Grant{Subject: "jwt:jane.smith", Roles: []string{"front-desk"}}))
+		require.NoError(t, repo.SaveGrant(ctx, &models.Grant{Subject: "api_key:backoffice", Roles: []string{"admin", "front-desk"}}))
+
+		grants, err = repo.GetAllGrants(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Grant{
+			{Subject: "api_key:backoffice", Roles: []string{"admin", "front-desk"}},
+			{Subject: "jwt:jane.smith", Roles: []string{"front-desk"}},
+		}, grants)
+
+		count, err := repo.CountGrantsWithRole(ctx, "front-desk")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		count, err = repo.CountGrantsWithRole(ctx, "auditor")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Delete grant by subject", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.SaveGrant(ctx, &models.Grant{Subject: "jwt:jane.smith", Roles: []string{"front-desk"}}))
+		require.NoError(t, repo.DeleteGrantBySubject(ctx, "jwt:jane.smith"))
+
+		_, err := repo.GetGrantBySubject(ctx, "jwt:jane.smith")
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+		err = repo.DeleteGrantBySubject(ctx, "jwt:jane.smith")
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+	})
+}
+
+func TestMemoryGrantRepositoryConformance(t *testing.T) {
+	t.Parallel()
+
+	runGrantRepositoryConformance(t, func(t *testing.T) GrantRepositoryI {
+		return NewMemoryGrantRepository()
+	})
+}
+
+// TestMongoGrantRepositoryConformance runs against a real MongoDB server when
+// MONGODB_TEST_URI is set, like TestMongoMemberRepositoryConformance.
+func TestMongoGrantRepositoryConformance(t *testing.T) {
+	mongoUri := os.Getenv("MONGODB_TEST_URI")
+	if mongoUri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoUri))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Disconnect(context.Background())
+	})
+
+	runGrantRepositoryConformance(t, func(t *testing.T) GrantRepositoryI {
+		mongoDb := client.Database(fmt.Sprintf("membership_test_%d", time.Now().UnixNano()))
+		t.Cleanup(func() {
+			mongoDb.Drop(context.Background())
+		})
+		require.NoError(t, CreateGrantIndexes(context.Background(), mongoDb.Collection("grants")))
+		return NewGrantRepository(mongoDb.Collection("grants"))
+	})
+}
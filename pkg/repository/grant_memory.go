@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/models"
+)
+
+// MemoryGrantRepository keeps grants in process memory, mirroring
+// GrantRepository.
+type MemoryGrantRepository struct {
+	mu     sync.RWMutex
+	grants map[string]models.Grant
+}
+
+func NewMemoryGrantRepository() GrantRepositoryI {
+	return &MemoryGrantRepository{
+		grants: make(map[string]models.Grant),
+	}
+}
+
+func (g *MemoryGrantRepository) SaveGrant(ctx context.Context, grant *models.Grant) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.grants[grant.Subject] = copyGrant(*grant)
+	return nil
+}
+
+func (g *MemoryGrantRepository) GetGrantBySubject(ctx context.Context, subject string) (*models.Grant, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	grant, exists := g.grants[subject]
+	if !exists {
+		return nil, mongo.ErrNoDocuments
+	}
+	grant = copyGrant(grant)
+	return &grant, nil
+}
+
+func (g *MemoryGrantRepository) GetAllGrants(ctx context.Context) ([]models.Grant, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	grants := make([]models.Grant, 0, len(g.grants))
+	for _, grant := range g.grants {
+		grants = append(grants, copyGrant(grant))
+	}
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].Subject < grants[j].Subject
+	})
+	return grants, nil
+}
+
+func (g *MemoryGrantRepository) DeleteGrantBySubject(ctx context.Context, subject string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.grants[subject]; !exists {
+		return mongo.ErrNoDocuments
+	}
+	delete(g.grants, subject)
+	return nil
+}
+
+func (g *MemoryGrantRepository) CountGrantsWithRole(ctx context.Context, role string) (int64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var count int64
+	for _, grant := range g.grants {
+		if slices.Contains(grant.Roles, role) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// copyGrant stops callers from sharing the stored roles slice.
+func copyGrant(grant models.Grant) models.Grant {
+	if grant.Roles != nil {
+		grant.Roles = append([]string{}, grant.Roles...)
+	}
+	return grant
+}
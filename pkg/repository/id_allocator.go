@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IdSequence names a counter and the offset its IDs start after.
+type IdSequence struct {
+	Name   string
+	Offset int
+}
+
+var (
+	// Member IDs are handed out on top of an offset so that they stay six
+	// digits long and easy to read out over the phone.
+	MemberIdSequence = IdSequence{Name: "memberId", Offset: 100000}
+	PlanIdSequence   = IdSequence{Name: "planId", Offset: 0}
+)
+
+type IdAllocatorI interface {
+	NextId(ctx context.Context) (int, error)
+	NextIds(ctx context.Context, n int) ([]int, error)
+}
+
+type MongoIdAllocator struct {
+	collection *mongo.Collection
+	sequence   IdSequence
+}
+
+func NewMongoIdAllocator(collection *mongo.Collection, sequence IdSequence) IdAllocatorI {
+	return &MongoIdAllocator{
+		collection: collection,
+		sequence:   sequence,
+	}
+}
+
+type counter struct {
+	Seq int `bson:"seq"`
+}
+
+// NextId atomically increments the sequence's counter document and returns
+// the new value. The counter document is created on first use.
+func (m *MongoIdAllocator) NextId(ctx context.Context) (int, error) {
+	return m.reserve(ctx, 1)
+}
+
+// NextIds reserves n consecutive IDs with a single increment of the
+// sequence's counter document, for callers that need many at once.
+func (m *MongoIdAllocator) NextIds(ctx context.Context, n int) ([]int, error) {
+	last, err := m.reserve(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	return idRange(last, n), nil
+}
+
+// reserve increments the counter by n and returns the last ID reserved.
+func (m *MongoIdAllocator) reserve(ctx context.Context, n int) (int, error) {
+	filter := bson.M{"_id": m.sequence.Name}
+	update := bson.M{"$inc": bson.M{"seq": n}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var seq counter
+	err := m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&seq)
+	if mongo.IsDuplicateKeyError(err) {
+		// Two concurrent upserts raced to create the counter document, the
+		// loser can simply increment the one that now exists.
+		err = m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&seq)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return m.sequence.Offset + seq.Seq, nil
+}
+
+type MemoryIdAllocator struct {
+	sequence IdSequence
+	seq      atomic.Int64
+}
+
+func NewMemoryIdAllocator(sequence IdSequence) IdAllocatorI {
+	return &MemoryIdAllocator{
+		sequence: sequence,
+	}
+}
+
+func (m *MemoryIdAllocator) NextId(ctx context.Context) (int, error) {
+	return m.sequence.Offset + int(m.seq.Add(1)), nil
+}
+
+func (m *MemoryIdAllocator) NextIds(ctx context.Context, n int) ([]int, error) {
+	return idRange(m.sequence.Offset+int(m.seq.Add(int64(n))), n), nil
+}
+
+// idRange returns the n IDs up to and including last.
+func idRange(last int, n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = last - n + 1 + i
+	}
+	return ids
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoIdAllocatorNextId(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	testCases := []struct {
+		name        string
+		mongoDbMock func(mt *mtest.T)
+		expectedId  int
+		wantErr     bool
+	}{
+		{
+			name: "Success allocating next id",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(bson.D{
+					{Key: "ok", Value: 1},
+					{Key: "value", Value: bson.D{
+						{Key: "_id", Value: MemberIdSequence.Name},
+						{Key: "seq", Value: 42},
+					}},
+				})
+			},
+			expectedId: 100042,
+			wantErr:    false,
+		},
+		{
+			name: "Retry when counter creation races",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+					Index:   0,
+					Code:    11000,
+					Message: "duplicate key error",
+				}), bson.D{
+					{Key: "ok", Value: 1},
+					{Key: "value", Value: bson.D{
+						{Key: "_id", Value: MemberIdSequence.Name},
+						{Key: "seq", Value: 2},
+					}},
+				})
+			},
+			expectedId: 100002,
+			wantErr:    false,
+		},
+		{
+			name: "Error allocating next id",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    2,
+					Message: "counter update failed",
+				}))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			allocator := NewMongoIdAllocator(mt.Coll, MemberIdSequence)
+			id, err := allocator.NextId(context.Background())
+
+			if tc.wantErr {
+				assert.Errorf(t, err, "Want error but got: %v", err)
+			} else {
+				assert.NoErrorf(t, err, "Not expecting error")
+				assert.Equal(t, tc.expectedId, id)
+			}
+		})
+	}
+}
+
+func TestMongoIdAllocatorNextIds(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	mt.Run("Success reserving ids with one increment", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: MemberIdSequence.Name},
+				{Key: "seq", Value: 45},
+			}},
+		})
+		allocator := NewMongoIdAllocator(mt.Coll, MemberIdSequence)
+		ids, err := allocator.NextIds(context.Background(), 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{100043, 100044, 100045}, ids)
+		events := mt.GetAllStartedEvents()
+		assert.Len(t, events, 1)
+		assert.Equal(t, int32(3), events[0].Command.Lookup("update", "$inc", "seq").Int32())
+	})
+
+	mt.Run("Error reserving ids", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    2,
+			Message: "counter update failed",
+		}))
+		allocator := NewMongoIdAllocator(mt.Coll, MemberIdSequence)
+		ids, err := allocator.NextIds(context.Background(), 3)
+
+		assert.Error(t, err)
+		assert.Nil(t, ids)
+	})
+}
+
+func TestMemoryIdAllocatorNextIds(t *testing.T) {
+	t.Parallel()
+
+	allocator := NewMemoryIdAllocator(MemberIdSequence)
+	id, err := allocator.NextId(context.Background())
+	assert.NoError(t, err)
+	ids, err := allocator.NextIds(context.Background(), 3)
+	assert.NoError(t, err)
+	next, err := allocator.NextId(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, 100001, id)
+	assert.Equal(t, []int{100002, 100003, 100004}, ids)
+	assert.Equal(t, 100005, next)
+}
+
+func TestMemoryIdAllocatorNextIdConcurrently(t *testing.T) {
+	t.Parallel()
+
+	allocator := NewMemoryIdAllocator(MemberIdSequence)
+
+	const allocations = 1000
+	ids := make(chan int, allocations)
+	var wg sync.WaitGroup
+	for i := 0; i < allocations; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := allocator.NextId(context.Background())
+			assert.NoError(t, err)
+			ids <- id
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int]bool)
+	for id := range ids {
+		assert.Falsef(t, seen[id], "id %d allocated twice", id)
+		assert.Greater(t, id, MemberIdSequence.Offset)
+		seen[id] = true
+	}
+	assert.Len(t, seen, allocations)
+}
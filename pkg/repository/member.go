@@ -2,87 +2,615 @@ package repository
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/utils"
+)
+
+const (
+	memberIdIndex     = "id_unique"
+	memberEmailIndex  = "email_unique"
+	memberSearchIndex = "search_text"
+)
+
+var (
+	ErrDuplicateMemberId = errors.New("member id already exists")
+	ErrDuplicateEmail    = errors.New("email already registered")
+	ErrVersionConflict   = errors.New("member version does not match")
+	ErrSharedEmails      = errors.New("members share emails, ignoring case")
 )
 
 type MemberRepositoryI interface {
 	CreateMember(ctx context.Context, member *models.Member) error
+	CreateMembers(ctx context.Context, members []models.Member) (map[int]error, error)
+	GetRegisteredEmails(ctx context.Context, emails []string) ([]string, error)
 	GetMemberById(ctx context.Context, memberId int) (*models.Member, error)
-	GetAllMembers(ctx context.Context) ([]models.Member, error)
-	UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int) error
-	DeleteMemberById(ctx context.Context, memberId int) error
+	GetAllMembers(ctx context.Context, query models.MemberQuery) (*models.MemberPage, error)
+	StreamMembers(ctx context.Context, query models.MemberQuery) (MemberIterator, error)
+	SearchMembers(ctx context.Context, query models.MemberSearchQuery) (*models.MemberPage, error)
+	UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int, version int) error
+	DeleteMemberById(ctx context.Context, memberId int, version int, deletedAt string) error
+	RestoreMemberById(ctx context.Context, memberId int) (*models.Member, error)
+	PurgeDeletedMembers(ctx context.Context, deletedBefore string) ([]int, error)
+	CountMembersWithPlan(ctx context.Context, planId int) (int64, error)
+	RenewMember(ctx context.Context, memberId int, version int, renewal models.Renewal) error
+	LapseExpiredMembers(ctx context.Context, today string) ([]int, error)
+	ChangeMemberStatus(ctx context.Context, memberId int, version int, change models.StatusChange) error
 }
 
 type MemberRepository struct {
-	mongoDb *mongo.Database
+	collection *mongo.Collection
 }
 
-func NewMembershipRepository(mongo *mongo.Database) MemberRepositoryI {
+func NewMembershipRepository(collection *mongo.Collection) MemberRepositoryI {
 	return &MemberRepository{
-		mongoDb: mongo,
+		collection: collection,
 	}
 }
 
 func (m *MemberRepository) CreateMember(ctx context.Context, member *models.Member) error {
-	_, err := m.mongoDb.Collection("members").InsertOne(ctx, member)
+	_, err := m.collection.InsertOne(ctx, newMemberDocument(member))
+	return translateWriteError(err)
+}
+
+// CreateMembers inserts the members in one bulk write. Members whose ID or
+// email is taken do not stop the others from being inserted, their errors
+// are returned by position.
+func (m *MemberRepository) CreateMembers(ctx context.Context, members []models.Member) (map[int]error, error) {
+	documents := make([]any, len(members))
+	for i := range members {
+		documents[i] = newMemberDocument(&members[i])
+	}
+
+	failed := make(map[int]error)
+	_, err := m.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			failed[writeErr.Index] = translateWriteError(mongo.WriteException{WriteErrors: mongo.WriteErrors{writeErr.WriteError}})
+		}
+		return failed, nil
+	}
+	return failed, err
+}
+
+// GetRegisteredEmails returns those of emails that belong to a member,
+// ignoring case. The emails of deleted members stay registered until they
+// are purged.
+func (m *MemberRepository) GetRegisteredEmails(ctx context.Context, emails []string) ([]string, error) {
+	opts := options.Find().
+		SetProjection(bson.M{"email": 1}).
+		SetCollation(&options.Collation{Locale: "en", Strength: 2})
+	result, err := m.collection.Find(ctx, bson.M{"email": bson.M{"$in": emails}}, opts)
 	if err != nil {
-		log.Println("error")
+		return nil, err
 	}
 
-	return err
+	var registered []struct {
+		Email string `bson:"email"`
+	}
+	if err := result.All(ctx, &registered); err != nil {
+		return nil, err
+	}
+	found := make([]string, len(registered))
+	for i, member := range registered {
+		found[i] = strings.ToLower(member.Email)
+	}
+	return found, nil
 }
 
 func (m *MemberRepository) GetMemberById(ctx context.Context, memberId int) (*models.Member, error) {
 	var member models.Member
-	filter := bson.D{bson.E{Key: "id", Value: memberId}}
-	err := m.mongoDb.Collection("members").FindOne(ctx, filter).Decode(&member)
+	filter := bson.D{bson.E{Key: "id", Value: memberId}, bson.E{Key: "deletedat", Value: deletedFilter(false)}}
+	err := m.collection.FindOne(ctx, filter).Decode(&member)
 	return &member, err
 }
 
-func (m *MemberRepository) GetAllMembers(ctx context.Context) ([]models.Member, error) {
-	query, err := m.mongoDb.Collection("members").Find(ctx, bson.D{})
+func (m *MemberRepository) GetAllMembers(ctx context.Context, query models.MemberQuery) (*models.MemberPage, error) {
+	query = withMemberQueryDefaults(query)
+	filter := memberQueryFilter(query)
+
+	total, err := m.collection.CountDocuments(ctx, filter)
 	if err != nil {
-		return []models.Member{}, err
+		return nil, err
 	}
-	defer query.Close(ctx)
 
-	membersList := make([]models.Member, 0)
-	for query.Next(ctx) {
-		var row models.Member
-		err := query.Decode(&row)
+	if query.Cursor != "" {
+		cursor, err := decodeMemberCursor(query.Cursor)
 		if err != nil {
-			log.Println("error decoding member:", err)
+			return nil, err
 		}
-		membersList = append(membersList, row)
+		filter = append(filter, memberCursorFilter(query, cursor))
+	}
+
+	// One extra member is fetched to find out whether there is a next page.
+	opts := options.Find().SetSort(memberQuerySort(query)).SetLimit(int64(query.Limit + 1))
+	result, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	membersList, err := collectMembers(ctx, newMongoMemberIterator(result), query.Limit+1)
+	if err != nil {
+		return nil, err
 	}
-	return membersList, nil
+	return newMemberPage(membersList, total, query), nil
 }
 
-func (m *MemberRepository) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int) error {
-	filter := bson.M{"id": memberId}
+// StreamMembers returns every member matching the query, in the query's
+// order and after its cursor, read from the Mongo cursor in batches. The
+// query's limit is ignored.
+func (m *MemberRepository) StreamMembers(ctx context.Context, query models.MemberQuery) (MemberIterator, error) {
+	query = withMemberQueryDefaults(query)
+	filter := memberQueryFilter(query)
+	if query.Cursor != "" {
+		cursor, err := decodeMemberCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter = append(filter, memberCursorFilter(query, cursor))
+	}
+
+	result, err := m.collection.Find(ctx, filter, options.Find().SetSort(memberQuerySort(query)))
+	if err != nil {
+		return nil, err
+	}
+	return newMongoMemberIterator(result), nil
+}
+
+// SearchMembers finds current members through the search text index, most
+// relevant first.
+func (m *MemberRepository) SearchMembers(ctx context.Context, query models.MemberSearchQuery) (*models.MemberPage, error) {
+	query = withMemberSearchDefaults(query)
+	offset, err := decodeSearchCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.D{
+		{Key: "$text", Value: bson.D{{Key: "$search", Value: newSearchTerms(query.Q).textSearch()}}},
+		{Key: "deletedat", Value: deletedFilter(false)},
+	}
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	score := bson.D{{Key: "$meta", Value: "textScore"}}
+	opts := options.Find().
+		SetProjection(bson.D{{Key: "score", Value: score}}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(query.Limit + 1))
+	result, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]models.Member, 0, query.Limit+1)
+	if err := result.All(ctx, &members); err != nil {
+		return nil, err
+	}
+	return newMemberSearchPage(members, total, query, offset), nil
+}
+
+// UpdateMemberById only updates the member while it is still at version and
+// bumps the version. ErrVersionConflict is returned if someone else has
+// changed the member in the meantime.
+func (m *MemberRepository) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int, version int) error {
+	filter := bson.M{"id": memberId, "version": versionFilter(version), "deletedat": deletedFilter(false)}
+	words := memberSearchWords(member.FirstName, member.LastName, member.Email)
 	update := bson.M{
 		"$set": bson.M{
 			"firstname":   member.FirstName,
 			"lastname":    member.LastName,
 			"email":       member.Email,
 			"dateofbirth": member.DateOfBirth,
+			"planid":      member.PlanId,
+			"startdate":   member.StartDate,
+			"expirydate":  member.ExpiryDate,
+			"lapsedon":    member.LapsedOn,
+			"searchwords": words,
+			"searchgrams": searchGrams(words),
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
-	_, err := m.mongoDb.Collection("members").UpdateOne(ctx, filter, update)
-	return err
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return translateWriteError(err)
+	}
+	if result.MatchedCount == 0 {
+		return m.missingOrConflict(ctx, memberId)
+	}
+	return nil
+}
+
+// DeleteMemberById soft deletes the member by setting deletedAt, provided the
+// member is still at version. Deleted members are hidden from everything but
+// the admin endpoints until they are restored or purged.
+func (m *MemberRepository) DeleteMemberById(ctx context.Context, memberId int, version int, deletedAt string) error {
+	filter := bson.M{"id": memberId, "version": versionFilter(version), "deletedat": deletedFilter(false)}
+	update := bson.M{
+		"$set": bson.M{"deletedat": deletedAt},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return m.missingOrConflict(ctx, memberId)
+	}
+	return nil
+}
+
+// RestoreMemberById undoes a soft delete and returns the member as it was
+// while deleted. mongo.ErrNoDocuments is returned if there is no deleted
+// member with the id.
+func (m *MemberRepository) RestoreMemberById(ctx context.Context, memberId int) (*models.Member, error) {
+	filter := bson.M{"id": memberId, "deletedat": deletedFilter(true)}
+	update := bson.M{
+		"$set": bson.M{"deletedat": ""},
+		"$inc": bson.M{"version": 1},
+	}
+
+	var member models.Member
+	err := m.collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.Before)).Decode(&member)
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// PurgeDeletedMembers permanently removes members deleted before
+// deletedBefore and returns their ids. On error, the ids of the members
+// already removed are returned with it.
+func (m *MemberRepository) PurgeDeletedMembers(ctx context.Context, deletedBefore string) ([]int, error) {
+	filter := bson.M{"deletedat": bson.M{"$gt": "", "$lt": deletedBefore}}
+	return m.forEachMatchingMember(ctx, filter, func(filter bson.M) (bool, error) {
+		result, err := m.collection.DeleteOne(ctx, filter)
+		if err != nil {
+			return false, err
+		}
+		return result.DeletedCount == 1, nil
+	})
+}
+
+// forEachMatchingMember calls change with a filter for each member matching
+// filter, narrowed down to the member, and returns the ids of those it
+// changed. Members are changed one at a time, rather than all at once, so
+// that the ids of exactly the members changed are known, even if some stop
+// matching in the meantime. On error, the ids of the members already changed
+// are returned with it.
+func (m *MemberRepository) forEachMatchingMember(ctx context.Context, filter bson.M, change func(filter bson.M) (bool, error)) ([]int, error) {
+	values, err := m.collection.Distinct(ctx, "id", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]int, 0, len(values))
+	for _, value := range values {
+		memberId, ok := memberIdValue(value)
+		if !ok {
+			continue
+		}
+		memberFilter := bson.M{"id": memberId}
+		for key, condition := range filter {
+			memberFilter[key] = condition
+		}
+		done, err := change(memberFilter)
+		if err != nil {
+			return changed, err
+		}
+		if done {
+			changed = append(changed, memberId)
+		}
+	}
+	return changed, nil
+}
+
+// memberIdValue converts a member id read without a type, as Distinct reads
+// them, to an int.
+func memberIdValue(value any) (int, bool) {
+	switch id := value.(type) {
+	case int32:
+		return int(id), true
+	case int64:
+		return int(id), true
+	default:
+		return 0, false
+	}
+}
+
+func (m *MemberRepository) CountMembersWithPlan(ctx context.Context, planId int) (int64, error) {
+	return m.collection.CountDocuments(ctx, bson.M{"planid": planId})
+}
+
+// RenewMember moves the member's expiry date to the renewal's, clears any
+// lapse and appends the renewal to the member's history, provided the member
+// is still at version.
+func (m *MemberRepository) RenewMember(ctx context.Context, memberId int, version int, renewal models.Renewal) error {
+	filter := bson.M{"id": memberId, "version": versionFilter(version), "deletedat": deletedFilter(false)}
+	update := bson.M{
+		"$set":  bson.M{"expirydate": renewal.ExpiryDate, "lapsedon": ""},
+		"$push": bson.M{"renewals": renewal},
+		"$inc":  bson.M{"version": 1},
+	}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return m.missingOrConflict(ctx, memberId)
+	}
+	return nil
 }
 
-func (m *MemberRepository) DeleteMemberById(ctx context.Context, memberId int) error {
-	filter := bson.M{"id": memberId}
-	_, err := m.mongoDb.Collection("members").DeleteOne(ctx, filter)
+// LapseExpiredMembers marks members whose membership expired on or before
+// today as lapsed and returns their ids. Members that are already lapsed are
+// left alone, so running it again changes nothing. On error, the ids of the
+// members already lapsed are returned with it.
+func (m *MemberRepository) LapseExpiredMembers(ctx context.Context, today string) ([]int, error) {
+	filter := bson.M{
+		"expirydate": bson.M{"$gt": "", "$lte": today},
+		"lapsedon":   bson.M{"$in": bson.A{"", nil}},
+		"deletedat":  deletedFilter(false),
+	}
+	update := bson.M{
+		"$set": bson.M{"lapsedon": today},
+		"$inc": bson.M{"version": 1},
+	}
+
+	return m.forEachMatchingMember(ctx, filter, func(filter bson.M) (bool, error) {
+		result, err := m.collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return false, err
+		}
+		return result.ModifiedCount == 1, nil
+	})
+}
+
+// ChangeMemberStatus moves the member to the change's status and appends the
+// change to the member's history, provided the member is still at version.
+// Checking the version also guarantees the member is still in change.From.
+func (m *MemberRepository) ChangeMemberStatus(ctx context.Context, memberId int, version int, change models.StatusChange) error {
+	filter := bson.M{"id": memberId, "version": versionFilter(version), "deletedat": deletedFilter(false)}
+	update := bson.M{
+		"$set":  bson.M{"status": change.To},
+		"$push": bson.M{"statuschanges": change},
+		"$inc":  bson.M{"version": 1},
+	}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		log.Println("error deleting member:", err)
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return m.missingOrConflict(ctx, memberId)
+	}
 	return nil
 }
+
+// missingOrConflict works out why a conditional write matched no member.
+func (m *MemberRepository) missingOrConflict(ctx context.Context, memberId int) error {
+	count, err := m.collection.CountDocuments(ctx, bson.M{"id": memberId, "deletedat": deletedFilter(false)})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return ErrVersionConflict
+}
+
+// versionFilter matches members at version. Members stored before versions
+// were introduced have no version field and count as version 0.
+func versionFilter(version int) any {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// deletedFilter matches soft deleted members, or members that are not
+// deleted, which includes those stored before soft deletes existed.
+func deletedFilter(deleted bool) bson.M {
+	if deleted {
+		return bson.M{"$nin": bson.A{"", nil}}
+	}
+	return bson.M{"$in": bson.A{"", nil}}
+}
+
+// maxIdReassignmentAttempts bounds how many IDs are drawn for a member whose
+// ID is shared before giving up. Drawn IDs can be taken by members given
+// random IDs before the counter existed.
+const maxIdReassignmentAttempts = 10
+
+// MemberIdReassignment records a member given a new ID because another member
+// already had its old one.
+type MemberIdReassignment struct {
+	OldId int
+	NewId int
+}
+
+// ReassignDuplicateMemberIds gives members that share an ID, which the random
+// IDs handed out before the counter could cause, new IDs from allocator. The
+// member stored first keeps the ID. It must run before CreateMemberIndexes,
+// whose unique ID index cannot be built while IDs are shared, and is safe to
+// call on every startup.
+func ReassignDuplicateMemberIds(ctx context.Context, members *mongo.Collection, allocator IdAllocatorI) ([]MemberIdReassignment, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$id"}, {Key: "documents", Value: bson.M{"$push": "$_id"}}}}},
+		{{Key: "$match", Value: bson.M{"documents.1": bson.M{"$exists": true}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+	cursor, err := members.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var duplicates []struct {
+		Id        int   `bson:"_id"`
+		Documents []any `bson:"documents"`
+	}
+	if err := cursor.All(ctx, &duplicates); err != nil {
+		return nil, err
+	}
+
+	reassigned := make([]MemberIdReassignment, 0)
+	for _, duplicate := range duplicates {
+		for _, document := range duplicate.Documents[1:] {
+			newId, err := freeMemberId(ctx, members, allocator)
+			if err != nil {
+				return reassigned, err
+			}
+			if _, err := members.UpdateOne(ctx, bson.M{"_id": document}, bson.M{"$set": bson.M{"id": newId}}); err != nil {
+				return reassigned, err
+			}
+			reassigned = append(reassigned, MemberIdReassignment{OldId: duplicate.Id, NewId: newId})
+		}
+	}
+	return reassigned, nil
+}
+
+// freeMemberId draws IDs from allocator until it finds one no member has.
+func freeMemberId(ctx context.Context, members *mongo.Collection, allocator IdAllocatorI) (int, error) {
+	for attempt := 0; attempt < maxIdReassignmentAttempts; attempt++ {
+		memberId, err := allocator.NextId(ctx)
+		if err != nil {
+			return 0, err
+		}
+		count, err := members.CountDocuments(ctx, bson.M{"id": memberId})
+		if err != nil {
+			return 0, err
+		}
+		if count == 0 {
+			return memberId, nil
+		}
+	}
+	return 0, ErrDuplicateMemberId
+}
+
+// NormalizeMemberEmails stores the emails of members registered before
+// emails were normalized in lower case, returning how many it updated. If
+// members share an email, ignoring case, no email is changed and
+// ErrSharedEmails is returned listing them, as the unique email index cannot
+// be built until they are told apart. It must run before
+// CreateMemberIndexes, and is safe to call on every startup.
+func NormalizeMemberEmails(ctx context.Context, members *mongo.Collection) (int64, error) {
+	if err := checkSharedEmails(ctx, members); err != nil {
+		return 0, err
+	}
+
+	result, err := members.Find(ctx, bson.M{"email": bson.M{"$regex": `^\s|\s$|\p{Lu}`}})
+	if err != nil {
+		return 0, err
+	}
+	defer result.Close(ctx)
+
+	var updated int64
+	for result.Next(ctx) {
+		var member struct {
+			ObjectId any    `bson:"_id"`
+			Email    string `bson:"email"`
+		}
+		if err := result.Decode(&member); err != nil {
+			return updated, err
+		}
+		update := bson.M{"$set": bson.M{"email": utils.NormalizeEmail(member.Email)}}
+		if _, err := members.UpdateOne(ctx, bson.M{"_id": member.ObjectId}, update); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, result.Err()
+}
+
+// checkSharedEmails returns ErrSharedEmails listing the emails members share
+// once normalized, with the IDs of the members sharing each.
+func checkSharedEmails(ctx context.Context, members *mongo.Collection) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"email": bson.M{"$type": "string"}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$email"}}}},
+			{Key: "memberIds", Value: bson.M{"$push": "$id"}},
+		}}},
+		{{Key: "$match", Value: bson.M{"memberIds.1": bson.M{"$exists": true}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+	cursor, err := members.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	var shared []struct {
+		Email     string `bson:"_id"`
+		MemberIds []int  `bson:"memberIds"`
+	}
+	if err := cursor.All(ctx, &shared); err != nil {
+		return err
+	}
+	if len(shared) == 0 {
+		return nil
+	}
+
+	emails := make([]string, len(shared))
+	for i, email := range shared {
+		memberIds := make([]string, len(email.MemberIds))
+		for j, memberId := range email.MemberIds {
+			memberIds[j] = strconv.Itoa(memberId)
+		}
+		emails[i] = fmt.Sprintf("%s (members %s)", email.Email, strings.Join(memberIds, ", "))
+	}
+	return fmt.Errorf("%w: %s", ErrSharedEmails, strings.Join(emails, "; "))
+}
+
+// CreateMemberIndexes creates the indexes the members collection relies on.
+// It is safe to call on every startup.
+func CreateMemberIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetName(memberIdIndex).SetUnique(true),
+		},
+		{
+			// Word matches outweigh gram matches, see member_search.go. No
+			// language is set so that grams are not dropped as stop words.
+			Keys: bson.D{{Key: "searchwords", Value: "text"}, {Key: "searchgrams", Value: "text"}},
+			Options: options.Index().SetName(memberSearchIndex).SetDefaultLanguage("none").
+				SetWeights(bson.D{{Key: "searchwords", Value: searchWordWeight}, {Key: "searchgrams", Value: 1}}),
+		},
+		{
+			// Emails are stored lower case, the case-insensitive collation
+			// also covers members registered before that was the case.
+			Keys: bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName(memberEmailIndex).SetUnique(true).
+				SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+		},
+	})
+	return err
+}
+
+// translateWriteError maps duplicate key errors on the member indexes to the
+// repository's own errors so callers don't depend on Mongo error messages.
+func translateWriteError(err error) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	switch {
+	case strings.Contains(err.Error(), memberIdIndex):
+		return ErrDuplicateMemberId
+	case strings.Contains(err.Error(), memberEmailIndex):
+		return ErrDuplicateEmail
+	}
+	return err
+}
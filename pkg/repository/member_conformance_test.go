@@ -0,0 +1,628 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+// runMemberRepositoryConformance checks the behaviour every MemberRepositoryI
+// implementation must share. newRepository must return an empty repository.
+func runMemberRepositoryConformance(t *testing.T, newRepository func(t *testing.T) MemberRepositoryI) {
+	newMember := func(id int) *models.Member {
+		return &models.Member{
+			ID:          id,
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       fmt.Sprintf("john.doe.%d@gmail.com", id),
+			DateOfBirth: "1990-01-01",
+			Version:     1,
+		}
+	}
+
+	t.Run("Create and get member by id", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+
+		member, err := repo.GetMemberById(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, newMember(1), member)
+	})
+
+	t.Run("Get unknown member returns no documents", func(t *testing.T) {
+		repo := newRepository(t)
+
+		_, err := repo.GetMemberById(context.Background(), 1)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+	})
+
+	t.Run("Create member with existing id", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		err := repo.CreateMember(ctx, newMember(1))
+		assert.True(t, errors.Is(err, ErrDuplicateMemberId))
+	})
+
+	t.Run("Create member with registered email", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		member := newMember(2)
+		member.Email = "JOHN.DOE.1@gmail.com"
+		err := repo.CreateMember(ctx, member)
+		assert.True(t, errors.Is(err, ErrDuplicateEmail))
+	})
+
+	t.Run("Create members in bulk", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		taken := newMember(3)
+		taken.Email = newMember(1).Email
+		twice := newMember(5)
+		twice.Email = newMember(4).Email
+
+		failed, err := repo.CreateMembers(ctx, []models.Member{*newMember(2), *taken, *newMember(4), *twice})
+		require.NoError(t, err)
+		assert.Len(t, failed, 2)
+		assert.True(t, errors.Is(failed[1], ErrDuplicateEmail))
+		assert.True(t, errors.Is(failed[3], ErrDuplicateEmail))
+
+		for _, id := range []int{2, 4} {
+			member, err := repo.GetMemberById(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, newMember(id), member)
+		}
+		_, err = repo.GetMemberById(ctx, 3)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+	})
+
+	t.Run("Get registered emails", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		require.NoError(t, repo.CreateMember(ctx, newMember(2)))
+		require.NoError(t, repo.DeleteMemberById(ctx, 2, 1, "2024-06-01T09:30:00Z"))
+
+		registered, err := repo.GetRegisteredEmails(ctx, []string{"JOHN.DOE.1@gmail.com", newMember(2).Email, "jane.smith@gmail.com"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{newMember(1).Email, newMember(2).Email}, registered)
+	})
+
+	t.Run("Update member to registered email", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		require.NoError(t, repo.CreateMember(ctx, newMember(2)))
+		err := repo.UpdateMemberById(ctx, &models.UpdateMember{
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       "john.doe.1@gmail.com",
+			DateOfBirth: "1990-01-01",
+		}, 2, 1)
+		assert.True(t, errors.Is(err, ErrDuplicateEmail))
+
+		err = repo.UpdateMemberById(ctx, &models.UpdateMember{
+			FirstName:   "Johnny",
+			LastName:    "Doe",
+			Email:       "john.doe.2@gmail.com",
+			DateOfBirth: "1990-01-01",
+		}, 2, 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Get all members", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		members, err := repo.GetAllMembers(ctx, models.MemberQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, members.Items)
+		assert.Equal(t, int64(0), members.Total)
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		require.NoError(t, repo.CreateMember(ctx, newMember(2)))
+
+		members, err = repo.GetAllMembers(ctx, models.MemberQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, &models.MemberPage{
+			Items: []models.Member{*newMember(1), *newMember(2)},
+			Total: 2,
+		}, members)
+	})
+
+	t.Run("Page through members", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		lastNames := []string{"Smith", "Doe", "Brown", "Doe", "Adams"}
+		for i, lastName := range lastNames {
+			member := newMember(i + 1)
+			member.LastName = lastName
+			require.NoError(t, repo.CreateMember(ctx, member))
+		}
+
+		query := models.MemberQuery{Limit: 2, SortBy: "lastName", SortOrder: SortDescending}
+		var ids []int
+		for pages := 0; pages < 5; pages++ {
+			members, err := repo.GetAllMembers(ctx, query)
+			require.NoError(t, err)
+			assert.Equal(t, int64(5), members.Total)
+			for _, member := range members.Items {
+				ids = append(ids, member.ID)
+			}
+			if members.NextCursor == "" {
+				break
+			}
+			query.Cursor = members.NextCursor
+		}
+		assert.Equal(t, []int{1, 4, 2, 3, 5}, ids)
+	})
+
+	t.Run("Filter members", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		members := []*models.Member{newMember(1), newMember(2), newMember(3), newMember(4)}
+		members[0].LastName, members[0].Email, members[0].DateOfBirth = "Nadal", "rafa@tennis.com", "1986-06-03"
+		members[1].LastName, members[1].Email, members[1].DateOfBirth = "nadal", "maria@gmail.com", "1990-02-01"
+		members[2].LastName, members[2].Email, members[2].DateOfBirth = "Federer", "roger@Tennis.com", "1981-08-08"
+		members[3].LastName, members[3].Email, members[3].DateOfBirth = "Nadalson", "nadalson@tennis.com.au", "1986-01-01"
+		for _, member := range members {
+			require.NoError(t, repo.CreateMember(ctx, member))
+		}
+
+		testCases := []struct {
+			name        string
+			query       models.MemberQuery
+			expectedIds []int
+		}{
+			{"by last name ignoring case", models.MemberQuery{LastName: "NADAL"}, []int{1, 2}},
+			{"by email domain", models.MemberQuery{EmailDomain: "tennis.com"}, []int{1, 3}},
+			{"by date of birth range", models.MemberQuery{DateOfBirthFrom: "1986-01-01", DateOfBirthTo: "1986-12-31"}, []int{1, 4}},
+			{"by combined filters", models.MemberQuery{LastName: "nadal", EmailDomain: "gmail.com"}, []int{2}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				page, err := repo.GetAllMembers(ctx, tc.query)
+				require.NoError(t, err)
+				ids := []int{}
+				for _, member := range page.Items {
+					ids = append(ids, member.ID)
+				}
+				assert.Equal(t, tc.expectedIds, ids)
+				assert.Equal(t, int64(len(tc.expectedIds)), page.Total)
+			})
+		}
+	})
+
+	t.Run("Invalid cursor", func(t *testing.T) {
+		repo := newRepository(t)
+
+		_, err := repo.GetAllMembers(context.Background(), models.MemberQuery{Cursor: "not a cursor"})
+		assert.True(t, errors.Is(err, ErrInvalidCursor))
+	})
+
+	t.Run("Stream members", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		lastNames := []string{"Smith", "Doe", "Brown", "Doe", "Adams"}
+		for i, lastName := range lastNames {
+			member := newMember(i + 1)
+			member.LastName = lastName
+			require.NoError(t, repo.CreateMember(ctx, member))
+		}
+		require.NoError(t, repo.DeleteMemberById(ctx, 3, 1, "2024-06-01T09:30:00Z"))
+
+		members, err := repo.StreamMembers(ctx, models.MemberQuery{Limit: 1, SortBy: "lastName", SortOrder: SortDescending})
+		require.NoError(t, err)
+		var ids []int
+		for members.Next(ctx) {
+			ids = append(ids, members.Member().ID)
+		}
+		require.NoError(t, members.Err())
+		require.NoError(t, members.Close(ctx))
+		assert.Equal(t, []int{1, 4, 2, 5}, ids)
+
+		_, err = repo.StreamMembers(ctx, models.MemberQuery{Cursor: "not a cursor"})
+		assert.True(t, errors.Is(err, ErrInvalidCursor))
+	})
+
+	t.Run("Stop streaming members when the context is done", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		for id := 1; id <= 3; id++ {
+			require.NoError(t, repo.CreateMember(ctx, newMember(id)))
+		}
+
+		members, err := repo.StreamMembers(ctx, models.MemberQuery{})
+		require.NoError(t, err)
+		defer members.Close(context.Background())
+		require.True(t, members.Next(ctx))
+		cancel()
+		assert.False(t, members.Next(ctx))
+		assert.True(t, errors.Is(members.Err(), context.Canceled))
+	})
+
+	t.Run("Search members", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		people := []struct{ firstName, lastName, email string }{
+			{"Rafael", "Nadal", "rafael.nadal@gmail.com"},
+			{"Roger", "Federer", "roger.federer@gmail.com"},
+			{"Rafferty", "Smith", "r.smith@yahoo.com"},
+			{"José", "Müller", "jose.muller@gmail.com"},
+			{"Rafael", "Deleted", "rafael.deleted@gmail.com"},
+		}
+		for i, person := range people {
+			member := newMember(i + 1)
+			member.FirstName, member.LastName, member.Email = person.firstName, person.lastName, person.email
+			require.NoError(t, repo.CreateMember(ctx, member))
+		}
+		require.NoError(t, repo.DeleteMemberById(ctx, 5, 1, "2024-06-01T09:30:00Z"))
+
+		testCases := []struct {
+			name       string
+			q          string
+			expectedId int
+		}{
+			{name: "Full name", q: "Rafa Nadal", expectedId: 1},
+			{name: "Prefix", q: "fede", expectedId: 2},
+			{name: "Typo", q: "Nadl", expectedId: 1},
+			{name: "Without diacritics", q: "Jose Muller", expectedId: 4},
+			{name: "With diacritics", q: "müller", expectedId: 4},
+			{name: "Email", q: "r.smith@yahoo.com", expectedId: 3},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				page, err := repo.SearchMembers(ctx, models.MemberSearchQuery{Q: tc.q})
+
+				require.NoError(t, err)
+				require.NotEmpty(t, page.Items)
+				assert.Equal(t, tc.expectedId, page.Items[0].ID)
+				for _, member := range page.Items {
+					assert.NotEqual(t, 5, member.ID, "deleted members are not found")
+				}
+			})
+		}
+
+		renamed := &models.UpdateMember{FirstName: "Novak", LastName: "Djokovic", Email: "novak.djokovic@gmail.com", DateOfBirth: "1990-01-01"}
+		require.NoError(t, repo.UpdateMemberById(ctx, renamed, 2, 1))
+		page, err := repo.SearchMembers(ctx, models.MemberSearchQuery{Q: "djoko"})
+		require.NoError(t, err)
+		require.NotEmpty(t, page.Items)
+		assert.Equal(t, 2, page.Items[0].ID)
+
+		page, err = repo.SearchMembers(ctx, models.MemberSearchQuery{Q: "zzz"})
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+		assert.Equal(t, int64(0), page.Total)
+	})
+
+	t.Run("Page through search results", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		for id := 1; id <= 3; id++ {
+			require.NoError(t, repo.CreateMember(ctx, newMember(id)))
+		}
+
+		page, err := repo.SearchMembers(ctx, models.MemberSearchQuery{Q: "John Doe", Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), page.Total)
+		require.Len(t, page.Items, 2)
+		assert.NotEmpty(t, page.NextCursor)
+
+		next, err := repo.SearchMembers(ctx, models.MemberSearchQuery{Q: "John Doe", Limit: 2, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, next.Items, 1)
+		assert.Empty(t, next.NextCursor)
+		assert.ElementsMatch(t, []int{1, 2, 3}, []int{page.Items[0].ID, page.Items[1].ID, next.Items[0].ID})
+
+		_, err = repo.SearchMembers(ctx, models.MemberSearchQuery{Q: "John", Cursor: "not a cursor"})
+		assert.True(t, errors.Is(err, ErrInvalidCursor))
+	})
+
+	t.Run("Update member by id", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		err := repo.UpdateMemberById(ctx, &models.UpdateMember{
+			FirstName:   "Jane",
+			LastName:    "Smith",
+			Email:       "jane.smith@gmail.com",
+			DateOfBirth: "1985-05-05",
+		}, 1, 1)
+		require.NoError(t, err)
+
+		member, err := repo.GetMemberById(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &models.Member{
+			ID:          1,
+			FirstName:   "Jane",
+			LastName:    "Smith",
+			Email:       "jane.smith@gmail.com",
+			DateOfBirth: "1985-05-05",
+			Version:     2,
+		}, member)
+	})
+
+	t.Run("Update member plan", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		require.NoError(t, repo.CreateMember(ctx, newMember(2)))
+		err := repo.UpdateMemberById(ctx, &models.UpdateMember{
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       "john.doe.1@gmail.com",
+			DateOfBirth: "1990-01-01",
+			PlanId:      7,
+			StartDate:   "2024-01-01",
+			ExpiryDate:  "2025-01-01",
+		}, 1, 1)
+		require.NoError(t, err)
+
+		member, err := repo.GetMemberById(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 7, member.PlanId)
+		assert.Equal(t, "2024-01-01", member.StartDate)
+		assert.Equal(t, "2025-01-01", member.ExpiryDate)
+
+		count, err := repo.CountMembersWithPlan(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Renew member", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		member := newMember(1)
+		member.PlanId, member.StartDate, member.ExpiryDate, member.LapsedOn = 7, "2023-01-01", "2024-01-01", "2024-01-01"
+		require.NoError(t, repo.CreateMember(ctx, member))
+
+		renewal := models.Renewal{RenewedOn: "2024-02-01", PlanId: 7, PreviousExpiryDate: "2024-01-01", ExpiryDate: "2025-02-01"}
+		require.NoError(t, repo.RenewMember(ctx, 1, 1, renewal))
+		err := repo.RenewMember(ctx, 1, 1, renewal)
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+		err = repo.RenewMember(ctx, 2, 1, renewal)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+
+		renewed, err := repo.GetMemberById(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "2025-02-01", renewed.ExpiryDate)
+		assert.Empty(t, renewed.LapsedOn)
+		assert.Equal(t, []models.Renewal{renewal}, renewed.Renewals)
+		assert.Equal(t, 2, renewed.Version)
+	})
+
+	t.Run("Lapse expired members", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		expiryDates := []string{"", "2024-05-31", "2024-06-01", "2024-06-02"}
+		for i, expiryDate := range expiryDates {
+			member := newMember(i + 1)
+			if expiryDate != "" {
+				member.PlanId, member.StartDate, member.ExpiryDate = 7, "2023-06-01", expiryDate
+			}
+			require.NoError(t, repo.CreateMember(ctx, member))
+		}
+
+		lapsed, err := repo.LapseExpiredMembers(ctx, "2024-06-01")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int{2, 3}, lapsed)
+
+		lapsed, err = repo.LapseExpiredMembers(ctx, "2024-06-01")
+		require.NoError(t, err)
+		assert.Empty(t, lapsed)
+
+		for i, expectedLapsedOn := range []string{"", "2024-06-01", "2024-06-01", ""} {
+			member, err := repo.GetMemberById(ctx, i+1)
+			require.NoError(t, err)
+			assert.Equal(t, expectedLapsedOn, member.LapsedOn)
+		}
+	})
+
+	t.Run("Change member status", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		member := newMember(1)
+		member.Status = models.MemberPending
+		require.NoError(t, repo.CreateMember(ctx, member))
+
+		change := models.StatusChange{From: models.MemberPending, To: models.MemberActive, Reason: "Payment received", ChangedAt: "2024-06-01T09:30:00Z"}
+		require.NoError(t, repo.ChangeMemberStatus(ctx, 1, 1, change))
+		err := repo.ChangeMemberStatus(ctx, 1, 1, change)
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+		err = repo.ChangeMemberStatus(ctx, 2, 1, change)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+
+		changed, err := repo.GetMemberById(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, models.MemberActive, changed.Status)
+		assert.Equal(t, []models.StatusChange{change}, changed.StatusChanges)
+		assert.Equal(t, 2, changed.Version)
+	})
+
+	t.Run("Update member at stale version", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		update := &models.UpdateMember{
+			FirstName:   "Jane",
+			LastName:    "Smith",
+			Email:       "jane.smith@gmail.com",
+			DateOfBirth: "1985-05-05",
+		}
+		require.NoError(t, repo.UpdateMemberById(ctx, update, 1, 1))
+		err := repo.UpdateMemberById(ctx, update, 1, 1)
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+	})
+
+	t.Run("Update unknown member", func(t *testing.T) {
+		repo := newRepository(t)
+
+		err := repo.UpdateMemberById(context.Background(), &models.UpdateMember{FirstName: "Jane"}, 1, 1)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+	})
+
+	t.Run("Delete member by id", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		require.NoError(t, repo.CreateMember(ctx, newMember(2)))
+		require.NoError(t, repo.DeleteMemberById(ctx, 1, 1, "2024-06-01T09:30:00Z"))
+
+		_, err := repo.GetMemberById(ctx, 1)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+		err = repo.DeleteMemberById(ctx, 1, 2, "2024-06-01T09:30:00Z")
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+		err = repo.UpdateMemberById(ctx, &models.UpdateMember{FirstName: "Jane"}, 1, 2)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+
+		members, err := repo.GetAllMembers(ctx, models.MemberQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, []models.Member{*newMember(2)}, members.Items)
+		assert.Equal(t, int64(1), members.Total)
+
+		deleted, err := repo.GetAllMembers(ctx, models.MemberQuery{Deleted: true})
+		require.NoError(t, err)
+		require.Len(t, deleted.Items, 1)
+		assert.Equal(t, "2024-06-01T09:30:00Z", deleted.Items[0].DeletedAt)
+		assert.Equal(t, int64(1), deleted.Total)
+	})
+
+	t.Run("Delete member at stale version", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		err := repo.DeleteMemberById(ctx, 1, 2, "2024-06-01T09:30:00Z")
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+	})
+
+	t.Run("Restore deleted member", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreateMember(ctx, newMember(1)))
+		_, err := repo.RestoreMemberById(ctx, 1)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+
+		require.NoError(t, repo.DeleteMemberById(ctx, 1, 1, "2024-06-01T09:30:00Z"))
+		deleted, err := repo.RestoreMemberById(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-06-01T09:30:00Z", deleted.DeletedAt)
+		assert.Equal(t, 2, deleted.Version)
+
+		member, err := repo.GetMemberById(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, member.DeletedAt)
+		assert.Equal(t, 3, member.Version)
+	})
+
+	t.Run("Purge deleted members", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		for id := 1; id <= 3; id++ {
+			require.NoError(t, repo.CreateMember(ctx, newMember(id)))
+		}
+		require.NoError(t, repo.DeleteMemberById(ctx, 1, 1, "2024-05-01T09:30:00Z"))
+		require.NoError(t, repo.DeleteMemberById(ctx, 2, 1, "2024-06-01T09:30:00Z"))
+
+		purged, err := repo.PurgeDeletedMembers(ctx, "2024-05-15T00:00:00Z")
+		require.NoError(t, err)
+		assert.Equal(t, []int{1}, purged)
+
+		deleted, err := repo.GetAllMembers(ctx, models.MemberQuery{Deleted: true})
+		require.NoError(t, err)
+		require.Len(t, deleted.Items, 1)
+		assert.Equal(t, 2, deleted.Items[0].ID)
+		_, err = repo.RestoreMemberById(ctx, 1)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+		_, err = repo.GetMemberById(ctx, 3)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Concurrent creates", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		var wg sync.WaitGroup
+		for id := 1; id <= 20; id++ {
+			wg.Add(1)
+			go func(id int) {
+				defer wg.Done()
+				assert.NoError(t, repo.CreateMember(ctx, newMember(id)))
+			}(id)
+		}
+		wg.Wait()
+
+		members, err := repo.GetAllMembers(ctx, models.MemberQuery{Limit: MaxMemberPageSize})
+		require.NoError(t, err)
+		assert.Len(t, members.Items, 20)
+	})
+}
+
+func TestMemoryMemberRepositoryConformance(t *testing.T) {
+	t.Parallel()
+
+	runMemberRepositoryConformance(t, func(t *testing.T) MemberRepositoryI {
+		return NewMemoryMemberRepository()
+	})
+}
+
+// TestMongoMemberRepositoryConformance runs against a real MongoDB server
+// when MONGODB_TEST_URI is set, each subtest using its own throwaway database.
+func TestMongoMemberRepositoryConformance(t *testing.T) {
+	mongoUri := os.Getenv("MONGODB_TEST_URI")
+	if mongoUri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoUri))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Disconnect(context.Background())
+	})
+
+	runMemberRepositoryConformance(t, func(t *testing.T) MemberRepositoryI {
+		mongoDb := client.Database(fmt.Sprintf("membership_test_%d", time.Now().UnixNano()))
+		t.Cleanup(func() {
+			mongoDb.Drop(context.Background())
+		})
+		require.NoError(t, CreateMemberIndexes(context.Background(), mongoDb.Collection("members")))
+		return NewMembershipRepository(mongoDb.Collection("members"))
+	})
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/models"
+)
+
+// MemberIterator reads members one at a time, so that any number of them can
+// be processed in bounded memory. Next advances to the next member and
+// returns false once there are none left, the context is done or a member
+// could not be read, Err then tells which. Close must always be called.
+type MemberIterator interface {
+	Next(ctx context.Context) bool
+	Member() *models.Member
+	Err() error
+	Close(ctx context.Context) error
+}
+
+// mongoMemberIterator decodes members from a Mongo cursor as it fetches
+// them in batches.
+type mongoMemberIterator struct {
+	cursor *mongo.Cursor
+	member models.Member
+	err    error
+}
+
+func newMongoMemberIterator(cursor *mongo.Cursor) *mongoMemberIterator {
+	return &mongoMemberIterator{cursor: cursor}
+}
+
+func (i *mongoMemberIterator) Next(ctx context.Context) bool {
+	if i.err != nil {
+		return false
+	}
+	if !i.cursor.Next(ctx) {
+		i.err = i.cursor.Err()
+		return false
+	}
+	i.member = models.Member{}
+	if err := i.cursor.Decode(&i.member); err != nil {
+		i.err = fmt.Errorf("decoding member: %w", err)
+		return false
+	}
+	return true
+}
+
+func (i *mongoMemberIterator) Member() *models.Member {
+	return &i.member
+}
+
+func (i *mongoMemberIterator) Err() error {
+	return i.err
+}
+
+func (i *mongoMemberIterator) Close(ctx context.Context) error {
+	return i.cursor.Close(ctx)
+}
+
+// sliceMemberIterator iterates over members already in memory.
+type sliceMemberIterator struct {
+	members []models.Member
+	next    int
+	err     error
+}
+
+func (i *sliceMemberIterator) Next(ctx context.Context) bool {
+	if i.err != nil || i.next >= len(i.members) {
+		return false
+	}
+	if i.err = ctx.Err(); i.err != nil {
+		return false
+	}
+	i.next++
+	return true
+}
+
+func (i *sliceMemberIterator) Member() *models.Member {
+	return &i.members[i.next-1]
+}
+
+func (i *sliceMemberIterator) Err() error {
+	return i.err
+}
+
+func (i *sliceMemberIterator) Close(ctx context.Context) error {
+	return nil
+}
+
+// collectMembers reads the members left in the iterator into a slice.
+func collectMembers(ctx context.Context, members MemberIterator, capacity int) ([]models.Member, error) {
+	defer members.Close(ctx)
+
+	collected := make([]models.Member, 0, capacity)
+	for members.Next(ctx) {
+		collected = append(collected, *members.Member())
+	}
+	return collected, members.Err()
+}
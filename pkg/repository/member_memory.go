@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/models"
+)
+
+// MemoryMemberRepository keeps members in process memory. It mirrors the
+// behaviour of MemberRepository, including returning mongo.ErrNoDocuments for
+// unknown members, so it can stand in for MongoDB in local development.
+type MemoryMemberRepository struct {
+	mu      sync.RWMutex
+	members map[int]models.Member
+}
+
+func NewMemoryMemberRepository() MemberRepositoryI {
+	return &MemoryMemberRepository{
+		members: make(map[int]models.Member),
+	}
+}
+
+func (m *MemoryMemberRepository) CreateMember(ctx context.Context, member *models.Member) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.members[member.ID]; exists {
+		return ErrDuplicateMemberId
+	}
+	if m.emailTaken(member.Email, member.ID) {
+		return ErrDuplicateEmail
+	}
+	m.members[member.ID] = *member
+	return nil
+}
+
+func (m *MemoryMemberRepository) CreateMembers(ctx context.Context, members []models.Member) (map[int]error, error) {
+	failed := make(map[int]error)
+	for i := range members {
+		if err := m.CreateMember(ctx, &members[i]); err != nil {
+			failed[i] = err
+		}
+	}
+	return failed, nil
+}
+
+func (m *MemoryMemberRepository) GetRegisteredEmails(ctx context.Context, emails []string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	found := make([]string, 0)
+	for _, email := range emails {
+		if m.emailTaken(email, 0) {
+			found = append(found, strings.ToLower(email))
+		}
+	}
+	return found, nil
+}
+
+func (m *MemoryMemberRepository) GetMemberById(ctx context.Context, memberId int) (*models.Member, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	member, exists := m.currentMember(memberId)
+	if !exists {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &member, nil
+}
+
+func (m *MemoryMemberRepository) GetAllMembers(ctx context.Context, query models.MemberQuery) (*models.MemberPage, error) {
+	query = withMemberQueryDefaults(query)
+	var cursor *memberCursor
+	if query.Cursor != "" {
+		var err error
+		cursor, err = decodeMemberCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var total int64
+	membersList := make([]models.Member, 0)
+	for _, member := range m.members {
+		if !memberMatchesQuery(&member, query) {
+			continue
+		}
+		total++
+		if cursor == nil || memberIsAfterCursor(&member, query, cursor) {
+			membersList = append(membersList, member)
+		}
+	}
+	sort.Slice(membersList, func(i, j int) bool {
+		return compareMembers(&membersList[i], &membersList[j], query) < 0
+	})
+	if len(membersList) > query.Limit+1 {
+		membersList = membersList[:query.Limit+1]
+	}
+	return newMemberPage(membersList, total, query), nil
+}
+
+// StreamMembers iterates over the members matching the query as they were
+// when it was called.
+func (m *MemoryMemberRepository) StreamMembers(ctx context.Context, query models.MemberQuery) (MemberIterator, error) {
+	query = withMemberQueryDefaults(query)
+	var cursor *memberCursor
+	if query.Cursor != "" {
+		var err error
+		cursor, err = decodeMemberCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	membersList := make([]models.Member, 0)
+	for _, member := range m.members {
+		if memberMatchesQuery(&member, query) && (cursor == nil || memberIsAfterCursor(&member, query, cursor)) {
+			membersList = append(membersList, member)
+		}
+	}
+	sort.Slice(membersList, func(i, j int) bool {
+		return compareMembers(&membersList[i], &membersList[j], query) < 0
+	})
+	return &sliceMemberIterator{members: membersList}, nil
+}
+
+func (m *MemoryMemberRepository) SearchMembers(ctx context.Context, query models.MemberSearchQuery) (*models.MemberPage, error) {
+	query = withMemberSearchDefaults(query)
+	offset, err := decodeSearchCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	terms := newSearchTerms(query.Q)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	scores := make(map[int]int)
+	membersList := make([]models.Member, 0)
+	for _, member := range m.members {
+		if member.DeletedAt != "" {
+			continue
+		}
+		if score := terms.score(&member); score > 0 {
+			scores[member.ID] = score
+			membersList = append(membersList, member)
+		}
+	}
+	rankMembers(membersList, scores)
+
+	total := int64(len(membersList))
+	membersList = membersList[min(offset, len(membersList)):]
+	if len(membersList) > query.Limit+1 {
+		membersList = membersList[:query.Limit+1]
+	}
+	return newMemberSearchPage(membersList, total, query, offset), nil
+}
+
+func (m *MemoryMemberRepository) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int, version int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, exists := m.currentMember(memberId)
+	if !exists {
+		return mongo.ErrNoDocuments
+	}
+	if existing.Version != version {
+		return ErrVersionConflict
+	}
+	if m.emailTaken(member.Email, memberId) {
+		return ErrDuplicateEmail
+	}
+	existing.FirstName = member.FirstName
+	existing.LastName = member.LastName
+	existing.Email = member.Email
+	existing.DateOfBirth = member.DateOfBirth
+	existing.PlanId = member.PlanId
+	existing.StartDate = member.StartDate
+	existing.ExpiryDate = member.ExpiryDate
+	existing.LapsedOn = member.LapsedOn
+	existing.Version++
+	m.members[memberId] = existing
+	return nil
+}
+
+func (m *MemoryMemberRepository) DeleteMemberById(ctx context.Context, memberId int, version int, deletedAt string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, exists := m.currentMember(memberId)
+	if !exists {
+		return mongo.ErrNoDocuments
+	}
+	if existing.Version != version {
+		return ErrVersionConflict
+	}
+	existing.DeletedAt = deletedAt
+	existing.Version++
+	m.members[memberId] = existing
+	return nil
+}
+
+func (m *MemoryMemberRepository) RestoreMemberById(ctx context.Context, memberId int) (*models.Member, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, exists := m.members[memberId]
+	if !exists || existing.DeletedAt == "" {
+		return nil, mongo.ErrNoDocuments
+	}
+	deleted := existing
+	existing.DeletedAt = ""
+	existing.Version++
+	m.members[memberId] = existing
+	return &deleted, nil
+}
+
+func (m *MemoryMemberRepository) PurgeDeletedMembers(ctx context.Context, deletedBefore string) ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	purged := make([]int, 0)
+	for id, member := range m.members {
+		if member.DeletedAt != "" && member.DeletedAt < deletedBefore {
+			delete(m.members, id)
+			purged = append(purged, id)
+		}
+	}
+	sort.Ints(purged)
+	return purged, nil
+}
+
+func (m *MemoryMemberRepository) CountMembersWithPlan(ctx context.Context, planId int) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int64
+	for _, member := range m.members {
+		if member.PlanId == planId {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MemoryMemberRepository) RenewMember(ctx context.Context, memberId int, version int, renewal models.Renewal) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, exists := m.currentMember(memberId)
+	if !exists {
+		return mongo.ErrNoDocuments
+	}
+	if existing.Version != version {
+		return ErrVersionConflict
+	}
+	existing.ExpiryDate = renewal.ExpiryDate
+	existing.LapsedOn = ""
+	existing.Renewals = append(append([]models.Renewal{}, existing.Renewals...), renewal)
+	existing.Version++
+	m.members[memberId] = existing
+	return nil
+}
+
+func (m *MemoryMemberRepository) LapseExpiredMembers(ctx context.Context, today string) ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lapsed := make([]int, 0)
+	for id, member := range m.members {
+		if member.ExpiryDate == "" || member.ExpiryDate > today || member.LapsedOn != "" || member.DeletedAt != "" {
+			continue
+		}
+		member.LapsedOn = today
+		member.Version++
+		m.members[id] = member
+		lapsed = append(lapsed, id)
+	}
+	sort.Ints(lapsed)
+	return lapsed, nil
+}
+
+func (m *MemoryMemberRepository) ChangeMemberStatus(ctx context.Context, memberId int, version int, change models.StatusChange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, exists := m.currentMember(memberId)
+	if !exists {
+		return mongo.ErrNoDocuments
+	}
+	if existing.Version != version {
+		return ErrVersionConflict
+	}
+	existing.Status = change.To
+	existing.StatusChanges = append(append([]models.StatusChange{}, existing.StatusChanges...), change)
+	existing.Version++
+	m.members[memberId] = existing
+	return nil
+}
+
+// currentMember returns the member unless it doesn't exist or is soft
+// deleted. Callers must hold the lock.
+func (m *MemoryMemberRepository) currentMember(memberId int) (models.Member, bool) {
+	member, exists := m.members[memberId]
+	if !exists || member.DeletedAt != "" {
+		return models.Member{}, false
+	}
+	return member, true
+}
+
+// emailTaken reports whether a member other than memberId already uses the
+// email, ignoring case like the unique email index in MongoDB.
+func (m *MemoryMemberRepository) emailTaken(email string, memberId int) bool {
+	for id, member := range m.members {
+		if id != memberId && strings.EqualFold(member.Email, email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"members.com/membership/pkg/models"
+)
+
+const (
+	DefaultMemberPageSize = 20
+	MaxMemberPageSize     = 100
+	SortAscending         = "asc"
+	SortDescending        = "desc"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// memberSortFields maps the JSON field names clients sort by to the field
+// names members are stored under.
+var memberSortFields = map[string]string{
+	"id":          "id",
+	"firstName":   "firstname",
+	"lastName":    "lastname",
+	"email":       "email",
+	"dateOfBirth": "dateofbirth",
+}
+
+func IsMemberSortField(field string) bool {
+	_, ok := memberSortFields[field]
+	return ok
+}
+
+func withMemberQueryDefaults(query models.MemberQuery) models.MemberQuery {
+	if query.Limit == 0 {
+		query.Limit = DefaultMemberPageSize
+	}
+	if query.SortBy == "" {
+		query.SortBy = "id"
+	}
+	if query.SortOrder == "" {
+		query.SortOrder = SortAscending
+	}
+	return query
+}
+
+// newMemberPage trims members, fetched with one extra beyond the limit, down
+// to a page and sets the next cursor when more members follow.
+func newMemberPage(members []models.Member, total int64, query models.MemberQuery) *models.MemberPage {
+	page := &models.MemberPage{
+		Items: members,
+		Total: total,
+	}
+	if len(members) > query.Limit {
+		page.Items = members[:query.Limit]
+		page.NextCursor = encodeMemberCursor(&page.Items[query.Limit-1], query.SortBy)
+	}
+	return page
+}
+
+// memberCursor marks the last member of a page. Pages are ordered by the sort
+// field and then by id, so the pair identifies a unique position.
+type memberCursor struct {
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id"`
+}
+
+func encodeMemberCursor(member *models.Member, sortBy string) string {
+	cursor := memberCursor{ID: member.ID}
+	if sortBy != "id" {
+		cursor.Value = memberSortValue(member, sortBy)
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeMemberCursor(encoded string) (*memberCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor memberCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+func memberSortValue(member *models.Member, sortBy string) string {
+	switch sortBy {
+	case "firstName":
+		return member.FirstName
+	case "lastName":
+		return member.LastName
+	case "email":
+		return member.Email
+	case "dateOfBirth":
+		return member.DateOfBirth
+	}
+	return ""
+}
+
+// memberQueryFilter builds the Mongo filter for the query's filters. The
+// cursor is not included so the result can also be used to count matches.
+func memberQueryFilter(query models.MemberQuery) bson.D {
+	filter := bson.D{{Key: "deletedat", Value: deletedFilter(query.Deleted)}}
+	if query.LastName != "" {
+		filter = append(filter, bson.E{Key: "lastname", Value: primitive.Regex{
+			Pattern: "^" + regexp.QuoteMeta(query.LastName) + "$",
+			Options: "i",
+		}})
+	}
+	if query.EmailDomain != "" {
+		filter = append(filter, bson.E{Key: "email", Value: primitive.Regex{
+			Pattern: "@" + regexp.QuoteMeta(query.EmailDomain) + "$",
+			Options: "i",
+		}})
+	}
+	dateOfBirth := bson.D{}
+	if query.DateOfBirthFrom != "" {
+		dateOfBirth = append(dateOfBirth, bson.E{Key: "$gte", Value: query.DateOfBirthFrom})
+	}
+	if query.DateOfBirthTo != "" {
+		dateOfBirth = append(dateOfBirth, bson.E{Key: "$lte", Value: query.DateOfBirthTo})
+	}
+	if len(dateOfBirth) > 0 {
+		filter = append(filter, bson.E{Key: "dateofbirth", Value: dateOfBirth})
+	}
+	return filter
+}
+
+// memberCursorFilter restricts results to members after the cursor position.
+func memberCursorFilter(query models.MemberQuery, cursor *memberCursor) bson.E {
+	op := "$gt"
+	if query.SortOrder == SortDescending {
+		op = "$lt"
+	}
+	if query.SortBy == "id" {
+		return bson.E{Key: "id", Value: bson.D{{Key: op, Value: cursor.ID}}}
+	}
+	field := memberSortFields[query.SortBy]
+	return bson.E{Key: "$or", Value: bson.A{
+		bson.D{{Key: field, Value: bson.D{{Key: op, Value: cursor.Value}}}},
+		bson.D{{Key: field, Value: cursor.Value}, {Key: "id", Value: bson.D{{Key: op, Value: cursor.ID}}}},
+	}}
+}
+
+func memberQuerySort(query models.MemberQuery) bson.D {
+	direction := 1
+	if query.SortOrder == SortDescending {
+		direction = -1
+	}
+	if query.SortBy == "id" {
+		return bson.D{{Key: "id", Value: direction}}
+	}
+	return bson.D{{Key: memberSortFields[query.SortBy], Value: direction}, {Key: "id", Value: direction}}
+}
+
+// The functions below apply the same query semantics to members held in
+// memory.
+
+func memberMatchesQuery(member *models.Member, query models.MemberQuery) bool {
+	if (member.DeletedAt != "") != query.Deleted {
+		return false
+	}
+	if query.LastName != "" && !strings.EqualFold(member.LastName, query.LastName) {
+		return false
+	}
+	if query.EmailDomain != "" && !strings.HasSuffix(strings.ToLower(member.Email), "@"+strings.ToLower(query.EmailDomain)) {
+		return false
+	}
+	if query.DateOfBirthFrom != "" && member.DateOfBirth < query.DateOfBirthFrom {
+		return false
+	}
+	if query.DateOfBirthTo != "" && member.DateOfBirth > query.DateOfBirthTo {
+		return false
+	}
+	return true
+}
+
+// compareMembers orders two members by the query's sort field and then by id,
+// returning a negative number when a sorts before b.
+func compareMembers(a *models.Member, b *models.Member, query models.MemberQuery) int {
+	result := strings.Compare(memberSortValue(a, query.SortBy), memberSortValue(b, query.SortBy))
+	if result == 0 {
+		result = a.ID - b.ID
+	}
+	if query.SortOrder == SortDescending {
+		return -result
+	}
+	return result
+}
+
+func memberIsAfterCursor(member *models.Member, query models.MemberQuery, cursor *memberCursor) bool {
+	position := &models.Member{ID: cursor.ID}
+	switch query.SortBy {
+	case "firstName":
+		position.FirstName = cursor.Value
+	case "lastName":
+		position.LastName = cursor.Value
+	case "email":
+		position.Email = cursor.Value
+	case "dateOfBirth":
+		position.DateOfBirth = cursor.Value
+	}
+	return compareMembers(member, position, query) > 0
+}
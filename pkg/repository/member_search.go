@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"members.com/membership/pkg/models"
+)
+
+// A search matching a whole word counts this many times as much as one
+// matching a single gram.
+const searchWordWeight = 3
+
+// Members are searched by the words of their names and email address and by
+// the grams of those words: the first two letters and every run of three.
+// Grams shared with the search terms find members by a prefix or despite a
+// typo, "Rafa Nadl" still shares "ra", "raf", "afa" and "nad" with "Rafael
+// Nadal". Words and grams are folded to lower case without diacritics, so
+// "Müller" and "Muller" match too.
+
+// memberDocument is a member as stored in Mongo, together with the search
+// words and grams the text index covers.
+type memberDocument struct {
+	models.Member `bson:",inline"`
+	SearchWords   []string `bson:"searchwords"`
+	SearchGrams   []string `bson:"searchgrams"`
+}
+
+func newMemberDocument(member *models.Member) *memberDocument {
+	words := memberSearchWords(member.FirstName, member.LastName, member.Email)
+	return &memberDocument{
+		Member:      *member,
+		SearchWords: words,
+		SearchGrams: searchGrams(words),
+	}
+}
+
+// searchFold lower cases text and strips its diacritics.
+var searchFold = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// searchWords splits text into distinct folded words of letters and digits.
+func searchWords(text string) []string {
+	folded, _, err := transform.String(searchFold, strings.ToLower(text))
+	if err != nil {
+		folded = strings.ToLower(text)
+	}
+	words := strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	slices.Sort(words)
+	return slices.Compact(words)
+}
+
+func memberSearchWords(firstName string, lastName string, email string) []string {
+	return searchWords(firstName + " " + lastName + " " + email)
+}
+
+// searchGrams returns the distinct grams of words. Words of up to two letters
+// are their own gram.
+func searchGrams(words []string) []string {
+	grams := make([]string, 0)
+	for _, word := range words {
+		letters := []rune(word)
+		if len(letters) <= 2 {
+			grams = append(grams, word)
+			continue
+		}
+		grams = append(grams, string(letters[:2]))
+		for i := 0; i+3 <= len(letters); i++ {
+			grams = append(grams, string(letters[i:i+3]))
+		}
+	}
+	slices.Sort(grams)
+	return slices.Compact(grams)
+}
+
+// searchTerms are the words and grams of a search.
+type searchTerms struct {
+	words []string
+	grams []string
+}
+
+func newSearchTerms(text string) searchTerms {
+	words := searchWords(text)
+	return searchTerms{words: words, grams: searchGrams(words)}
+}
+
+// textSearch is the $text search string, any of the terms matches.
+func (s searchTerms) textSearch() string {
+	terms := append(slices.Clone(s.words), s.grams...)
+	slices.Sort(terms)
+	return strings.Join(slices.Compact(terms), " ")
+}
+
+// score ranks a member held in memory the way the weights of the text index
+// rank members in Mongo. A score of 0 means no match.
+func (s searchTerms) score(member *models.Member) int {
+	words := memberSearchWords(member.FirstName, member.LastName, member.Email)
+	grams := searchGrams(words)
+	score := 0
+	for _, word := range s.words {
+		if _, found := slices.BinarySearch(words, word); found {
+			score += searchWordWeight
+		}
+	}
+	for _, gram := range s.grams {
+		if _, found := slices.BinarySearch(grams, gram); found {
+			score++
+		}
+	}
+	return score
+}
+
+func withMemberSearchDefaults(query models.MemberSearchQuery) models.MemberSearchQuery {
+	if query.Limit == 0 {
+		query.Limit = DefaultMemberPageSize
+	}
+	return query
+}
+
+// Search results are ordered by relevance, which cannot be compared with a
+// cursor value, so search cursors hold the offset of the next page.
+type searchCursor struct {
+	Offset int `json:"o"`
+}
+
+func encodeSearchCursor(offset int) string {
+	data, _ := json.Marshal(searchCursor{Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSearchCursor returns the offset the cursor points at, 0 without one.
+func decodeSearchCursor(encoded string) (int, error) {
+	if encoded == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	var cursor searchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return cursor.Offset, nil
+}
+
+// newMemberSearchPage trims members, fetched from offset with one extra
+// beyond the limit, down to a page and sets the next cursor when more members
+// follow.
+func newMemberSearchPage(members []models.Member, total int64, query models.MemberSearchQuery, offset int) *models.MemberPage {
+	page := &models.MemberPage{
+		Items: members,
+		Total: total,
+	}
+	if len(members) > query.Limit {
+		page.Items = members[:query.Limit]
+		page.NextCursor = encodeSearchCursor(offset + query.Limit)
+	}
+	return page
+}
+
+// rankMembers orders scored members by descending score and then by id.
+func rankMembers(members []models.Member, scores map[int]int) {
+	sort.Slice(members, func(i, j int) bool {
+		if scores[members[i].ID] != scores[members[j].ID] {
+			return scores[members[i].ID] > scores[members[j].ID]
+		}
+		return members[i].ID < members[j].ID
+	})
+}
+
+// IndexMemberSearchTerms adds search words and grams to members stored
+// before search existed, returning how many it updated. It is safe to call
+// on every startup.
+func IndexMemberSearchTerms(ctx context.Context, members *mongo.Collection) (int64, error) {
+	result, err := members.Find(ctx, bson.M{"searchwords": bson.M{"$exists": false}})
+	if err != nil {
+		return 0, err
+	}
+	defer result.Close(ctx)
+
+	var updated int64
+	for result.Next(ctx) {
+		var member models.Member
+		if err := result.Decode(&member); err != nil {
+			return updated, err
+		}
+		document := newMemberDocument(&member)
+		update := bson.M{"$set": bson.M{"searchwords": document.SearchWords, "searchgrams": document.SearchGrams}}
+		if _, err := members.UpdateOne(ctx, bson.M{"id": member.ID}, update); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, result.Err()
+}
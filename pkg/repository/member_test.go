@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 	"members.com/membership/pkg/models"
 )
@@ -19,6 +22,7 @@ func TestCreateMember(t *testing.T) {
 		name        string
 		mongoDbMock func(mt *mtest.T)
 		wantErr     bool
+		expectedErr error
 	}{
 		{
 			name: "Success creating new member",
@@ -38,12 +42,36 @@ func TestCreateMember(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Error creating member with existing id",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+					Index:   0,
+					Code:    11000,
+					Message: "E11000 duplicate key error collection: membership.members index: id_unique dup key: { id: 1 }",
+				}))
+			},
+			wantErr:     true,
+			expectedErr: ErrDuplicateMemberId,
+		},
+		{
+			name: "Error creating member with registered email",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+					Index:   0,
+					Code:    11000,
+					Message: "E11000 duplicate key error collection: membership.members index: email_unique dup key: { email: \"john.doe@gmail.com\" }",
+				}))
+			},
+			wantErr:     true,
+			expectedErr: ErrDuplicateEmail,
+		},
 	}
 
 	for _, tc := range testCases {
 		mt.Run(tc.name, func(mt *mtest.T) {
 			tc.mongoDbMock(mt)
-			repo := NewMembershipRepository(mt.DB)
+			repo := NewMembershipRepository(mt.Coll)
 			member := &models.Member{
 				ID:          1,
 				FirstName:   "John",
@@ -55,6 +83,9 @@ func TestCreateMember(t *testing.T) {
 
 			if tc.wantErr {
 				assert.Errorf(t, err, "Want error but got: %v", err)
+				if tc.expectedErr != nil {
+					assert.True(t, errors.Is(err, tc.expectedErr))
+				}
 			} else {
 				assert.NoErrorf(t, err, "Not expecting error")
 			}
@@ -62,6 +93,68 @@ func TestCreateMember(t *testing.T) {
 	}
 }
 
+func TestCreateMembers(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	members := []models.Member{
+		{ID: 1, FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com", DateOfBirth: "1990-01-01"},
+		{ID: 2, FirstName: "Jane", LastName: "Smith", Email: "jane.smith@gmail.com", DateOfBirth: "1985-05-05"},
+	}
+
+	testCases := []struct {
+		name           string
+		mongoDbMock    func(mt *mtest.T)
+		expectedFailed map[int]error
+		wantErr        bool
+	}{
+		{
+			name: "Success creating members",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse())
+			},
+			expectedFailed: map[int]error{},
+		},
+		{
+			name: "Members with taken emails fail alone",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+					Index:   1,
+					Code:    11000,
+					Message: "E11000 duplicate key error collection: members index: email_unique dup key",
+				}))
+			},
+			expectedFailed: map[int]error{1: ErrDuplicateEmail},
+		},
+		{
+			name: "Error creating members",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    91,
+					Message: "shutdown in progress",
+				}))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			repo := NewMembershipRepository(mt.Coll)
+			failed, err := repo.CreateMembers(context.Background(), members)
+
+			if tc.wantErr {
+				assert.Errorf(t, err, "Want error but got: %v", err)
+				return
+			}
+			assert.NoErrorf(t, err, "Not expecting error")
+			assert.Equal(t, tc.expectedFailed, failed)
+		})
+	}
+}
+
 func TestGetMemberById(t *testing.T) {
 	t.Parallel()
 
@@ -105,7 +198,7 @@ func TestGetMemberById(t *testing.T) {
 	for _, tc := range testCases {
 		mt.Run(tc.name, func(mt *mtest.T) {
 			tc.mongoDbMock(mt)
-			repo := NewMembershipRepository(mt.DB)
+			repo := NewMembershipRepository(mt.Coll)
 			member, err := repo.GetMemberById(context.Background(), member.ID)
 
 			if tc.wantErr {
@@ -127,32 +220,79 @@ func TestGetAllMembers(t *testing.T) {
 
 	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
 
+	john := bson.D{
+		{Key: "id", Value: 1},
+		{Key: "firstName", Value: "John"},
+		{Key: "lastName", Value: "Doe"},
+		{Key: "email", Value: "John.Doe@gmail.com"},
+		{Key: "dateOfBirth", Value: "1990-01-01"},
+	}
+	jane := bson.D{
+		{Key: "id", Value: 2},
+		{Key: "firstName", Value: "Jane"},
+		{Key: "lastName", Value: "Smith"},
+		{Key: "email", Value: "Jane.Smith@gmail.com"},
+		{Key: "dateOfBirth", Value: "1985-05-05"},
+	}
+
 	testCases := []struct {
-		name        string
-		mongoDbMock func(mt *mtest.T)
-		wantErr     bool
+		name               string
+		query              models.MemberQuery
+		mongoDbMock        func(mt *mtest.T)
+		expectedLen        int
+		expectedNextCursor bool
+		wantErr            bool
 	}{
 		{
-			name: "Success getting all members",
+			name:  "Success getting all members",
+			query: models.MemberQuery{},
 			mongoDbMock: func(mt *mtest.T) {
-				mt.AddMockResponses(mtest.CreateCursorResponse(2, "membership.members", mtest.FirstBatch, bson.D{
-					{Key: "id", Value: 1},
-					{Key: "firstName", Value: "John"},
-					{Key: "lastName", Value: "Doe"},
-					{Key: "email", Value: "John.Doe@gmail.com"},
-					{Key: "dateOfBirth", Value: "1990-01-01"},
-				}, bson.D{
-					{Key: "id", Value: 2},
-					{Key: "firstName", Value: "Jane"},
-					{Key: "lastName", Value: "Smith"},
-					{Key: "email", Value: "Jane.Smith@gmail.com"},
-					{Key: "dateOfBirth", Value: "1985-05-05"},
-				}))
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, john, jane),
+				)
 			},
-			wantErr: false,
+			expectedLen:        2,
+			expectedNextCursor: false,
+			wantErr:            false,
+		},
+		{
+			name:  "Success getting first page of members",
+			query: models.MemberQuery{Limit: 1},
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, john, jane),
+				)
+			},
+			expectedLen:        1,
+			expectedNextCursor: true,
+			wantErr:            false,
+		},
+		{
+			name:  "Error decoding members",
+			query: models.MemberQuery{},
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, john, bson.D{{Key: "id", Value: "two"}}),
+				)
+			},
+			wantErr: true,
+		},
+		{
+			name:  "Invalid cursor",
+			query: models.MemberQuery{Cursor: "%%%"},
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+				)
+			},
+			wantErr: true,
 		},
 		{
-			name: "error getting all members",
+			name:  "error getting all members",
+			query: models.MemberQuery{},
 			mongoDbMock: func(mt *mtest.T) {
 				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
 					Code:    11000,
@@ -166,30 +306,211 @@ func TestGetAllMembers(t *testing.T) {
 	for _, tc := range testCases {
 		mt.Run(tc.name, func(mt *mtest.T) {
 			tc.mongoDbMock(mt)
-			repo := NewMembershipRepository(mt.DB)
-			members, err := repo.GetAllMembers(context.Background())
+			repo := NewMembershipRepository(mt.Coll)
+			members, err := repo.GetAllMembers(context.Background(), tc.query)
 
 			if tc.wantErr {
 				assert.Errorf(t, err, "Want error but got: %v", err)
-				assert.Len(t, members, 0)
+				assert.Nil(t, members)
 			} else {
 				assert.NoErrorf(t, err, "Not expecting error")
-				assert.Len(t, members, 2)
+				assert.Len(t, members.Items, tc.expectedLen)
+				assert.Equal(t, int64(2), members.Total)
+				assert.Equal(t, tc.expectedNextCursor, members.NextCursor != "")
 
-				member1 := members[0]
+				member1 := members.Items[0]
 				assert.Equal(t, 1, member1.ID)
 				assert.Equal(t, "John", member1.FirstName)
 				assert.Equal(t, "Doe", member1.LastName)
 				assert.Equal(t, "John.Doe@gmail.com", member1.Email)
 				assert.Equal(t, "1990-01-01", member1.DateOfBirth)
 
-				member2 := members[1]
-				assert.Equal(t, 2, member2.ID)
-				assert.Equal(t, "Jane", member2.FirstName)
-				assert.Equal(t, "Smith", member2.LastName)
-				assert.Equal(t, "Jane.Smith@gmail.com", member2.Email)
-				assert.Equal(t, "1985-05-05", member2.DateOfBirth)
+				if tc.expectedLen > 1 {
+					member2 := members.Items[1]
+					assert.Equal(t, 2, member2.ID)
+					assert.Equal(t, "Jane", member2.FirstName)
+					assert.Equal(t, "Smith", member2.LastName)
+					assert.Equal(t, "Jane.Smith@gmail.com", member2.Email)
+					assert.Equal(t, "1985-05-05", member2.DateOfBirth)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamMembers(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	john := bson.D{{Key: "id", Value: 1}, {Key: "firstName", Value: "John"}, {Key: "lastName", Value: "Doe"}}
+	jane := bson.D{{Key: "id", Value: 2}, {Key: "firstName", Value: "Jane"}, {Key: "lastName", Value: "Smith"}}
+	corrupt := bson.D{{Key: "id", Value: "two"}, {Key: "firstName", Value: "Jane"}}
+
+	testCases := []struct {
+		name          string
+		mongoDbMock   func(mt *mtest.T)
+		expectedIds   []int
+		wantStreamErr bool
+		wantErr       bool
+	}{
+		{
+			name: "Success streaming members across batches",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(1, "membership.members", mtest.FirstBatch, john),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.NextBatch, jane),
+				)
+			},
+			expectedIds: []int{1, 2},
+		},
+		{
+			name: "Decode error stops streaming",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, john, corrupt, jane))
+			},
+			expectedIds: []int{1},
+			wantErr:     true,
+		},
+		{
+			name: "Error fetching next batch stops streaming",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(1, "membership.members", mtest.FirstBatch, john),
+					mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 43, Message: "cursor not found"}),
+				)
+			},
+			expectedIds: []int{1},
+			wantErr:     true,
+		},
+		{
+			name: "Error finding members",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    91,
+					Message: "shutdown in progress",
+				}))
+			},
+			wantStreamErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			repo := NewMembershipRepository(mt.Coll)
+			ctx := context.Background()
+			members, err := repo.StreamMembers(ctx, models.MemberQuery{})
+			if tc.wantStreamErr {
+				assert.Errorf(t, err, "Want error but got: %v", err)
+				return
+			}
+			assert.NoErrorf(t, err, "Not expecting error")
+			defer members.Close(ctx)
+
+			var ids []int
+			for members.Next(ctx) {
+				ids = append(ids, members.Member().ID)
+			}
+			if tc.wantErr {
+				assert.Errorf(t, members.Err(), "Want error but got: %v", members.Err())
+			} else {
+				assert.NoErrorf(t, members.Err(), "Not expecting error")
+			}
+			assert.Equal(t, tc.expectedIds, ids)
+		})
+	}
+}
+
+func TestSearchMembers(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	rafael := bson.D{
+		{Key: "id", Value: 1},
+		{Key: "firstname", Value: "Rafael"},
+		{Key: "lastname", Value: "Nadal"},
+		{Key: "email", Value: "rafael.nadal@gmail.com"},
+		{Key: "score", Value: 12.5},
+	}
+	rafferty := bson.D{
+		{Key: "id", Value: 3},
+		{Key: "firstname", Value: "Rafferty"},
+		{Key: "lastname", Value: "Smith"},
+		{Key: "email", Value: "r.smith@yahoo.com"},
+		{Key: "score", Value: 2.0},
+	}
+
+	testCases := []struct {
+		name               string
+		query              models.MemberSearchQuery
+		mongoDbMock        func(mt *mtest.T)
+		expectedIds        []int
+		expectedNextCursor bool
+		wantErr            bool
+	}{
+		{
+			name:  "Success searching members",
+			query: models.MemberSearchQuery{Q: "Rafa Nadal"},
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, rafael, rafferty),
+				)
+			},
+			expectedIds: []int{1, 3},
+		},
+		{
+			name:  "Success getting first page of search results",
+			query: models.MemberSearchQuery{Q: "Rafa Nadal", Limit: 1},
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, rafael, rafferty),
+				)
+			},
+			expectedIds:        []int{1},
+			expectedNextCursor: true,
+		},
+		{
+			name:        "Invalid cursor",
+			query:       models.MemberSearchQuery{Q: "Rafa", Cursor: "%%%"},
+			mongoDbMock: func(mt *mtest.T) {},
+			wantErr:     true,
+		},
+		{
+			name:  "Error searching members",
+			query: models.MemberSearchQuery{Q: "Rafa"},
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    27,
+					Message: "text index required for $text query",
+				}))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			repo := NewMembershipRepository(mt.Coll)
+			members, err := repo.SearchMembers(context.Background(), tc.query)
+
+			if tc.wantErr {
+				assert.Errorf(t, err, "Want error but got: %v", err)
+				assert.Nil(t, members)
+				return
+			}
+			assert.NoErrorf(t, err, "Not expecting error")
+			assert.Equal(t, int64(2), members.Total)
+			assert.Equal(t, tc.expectedNextCursor, members.NextCursor != "")
+			ids := make([]int, 0, len(members.Items))
+			for _, member := range members.Items {
+				ids = append(ids, member.ID)
 			}
+			assert.Equal(t, tc.expectedIds, ids)
 		})
 	}
 }
@@ -211,14 +532,37 @@ func TestUpdateMemberById(t *testing.T) {
 		name        string
 		mongoDbMock func(mt *mtest.T)
 		wantErr     bool
+		expectedErr error
 	}{
 		{
 			name: "Success updating existing member",
 			mongoDbMock: func(mt *mtest.T) {
-				mt.AddMockResponses(mtest.CreateSuccessResponse())
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
 			},
 			wantErr: false,
 		},
+		{
+			name: "Member changed since it was read",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+				)
+			},
+			wantErr:     true,
+			expectedErr: ErrVersionConflict,
+		},
+		{
+			name: "Member deleted since it was read",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch),
+				)
+			},
+			wantErr:     true,
+			expectedErr: mongo.ErrNoDocuments,
+		},
 		{
 			name: "Update non-existing member",
 			mongoDbMock: func(mt *mtest.T) {
@@ -234,11 +578,14 @@ func TestUpdateMemberById(t *testing.T) {
 	for _, tc := range testCases {
 		mt.Run(tc.name, func(mt *mtest.T) {
 			tc.mongoDbMock(mt)
-			repo := NewMembershipRepository(mt.DB)
-			err := repo.UpdateMemberById(context.Background(), &member, memberId)
+			repo := NewMembershipRepository(mt.Coll)
+			err := repo.UpdateMemberById(context.Background(), &member, memberId, 1)
 
 			if tc.wantErr {
 				assert.Errorf(t, err, "Want error but got: %v", err)
+				if tc.expectedErr != nil {
+					assert.True(t, errors.Is(err, tc.expectedErr))
+				}
 			} else {
 				assert.NoErrorf(t, err, "Not expecting error")
 			}
@@ -255,14 +602,26 @@ func TestDeleteMemberByID(t *testing.T) {
 		name        string
 		mongoDbMock func(mt *mtest.T)
 		wantErr     bool
+		expectedErr error
 	}{
 		{
 			name: "Success deleting existing member",
 			mongoDbMock: func(mt *mtest.T) {
-				mt.AddMockResponses(mtest.CreateSuccessResponse())
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
 			},
 			wantErr: false,
 		},
+		{
+			name: "Member changed since it was read",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+				)
+			},
+			wantErr:     true,
+			expectedErr: ErrVersionConflict,
+		},
 		{
 			name: "Delete non-existing member",
 			mongoDbMock: func(mt *mtest.T) {
@@ -280,14 +639,372 @@ func TestDeleteMemberByID(t *testing.T) {
 		mt.Run(tc.name, func(mt *mtest.T) {
 			memberId := 123
 			tc.mongoDbMock(mt)
-			repo := NewMembershipRepository(mt.DB)
-			err := repo.DeleteMemberById(context.Background(), memberId)
+			repo := NewMembershipRepository(mt.Coll)
+			err := repo.DeleteMemberById(context.Background(), memberId, 1, "2024-06-01T09:30:00Z")
 
 			if tc.wantErr {
 				assert.Errorf(t, err, "Want error but got: %v", err)
+				if tc.expectedErr != nil {
+					assert.True(t, errors.Is(err, tc.expectedErr))
+				}
 			} else {
 				assert.NoErrorf(t, err, "Not expecting error")
 			}
 		})
 	}
 }
+
+func TestCreateMemberIndexes(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	mt.Run("Success creating member indexes", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		err := CreateMemberIndexes(context.Background(), mt.Coll)
+		assert.NoError(t, err)
+	})
+
+	mt.Run("Error creating member indexes", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    11000,
+			Message: "index build failed",
+		}))
+		err := CreateMemberIndexes(context.Background(), mt.Coll)
+		assert.Error(t, err)
+	})
+}
+
+func TestReassignDuplicateMemberIds(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	duplicates := mtest.CreateCursorResponse(0, "members.members", mtest.FirstBatch, bson.D{
+		{Key: "_id", Value: 100042},
+		{Key: "documents", Value: bson.A{"first", "second", "third"}},
+	})
+	countResponse := func(count int) bson.D {
+		return mtest.CreateCursorResponse(0, "members.members", mtest.FirstBatch, bson.D{{Key: "n", Value: count}})
+	}
+	updated := bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}}
+
+	testCases := []struct {
+		name               string
+		mongoDbMock        func(mt *mtest.T)
+		expectedReassigned []MemberIdReassignment
+		wantErr            bool
+	}{
+		{
+			name: "Success giving every member but the first a new id",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(duplicates, countResponse(0), updated, countResponse(0), updated)
+			},
+			expectedReassigned: []MemberIdReassignment{{OldId: 100042, NewId: 100001}, {OldId: 100042, NewId: 100002}},
+		},
+		{
+			name: "Skip ids already taken by members with random ids",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(duplicates, countResponse(1), countResponse(0), updated, countResponse(0), updated)
+			},
+			expectedReassigned: []MemberIdReassignment{{OldId: 100042, NewId: 100002}, {OldId: 100042, NewId: 100003}},
+		},
+		{
+			name: "Success with no shared ids",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "members.members", mtest.FirstBatch))
+			},
+			expectedReassigned: []MemberIdReassignment{},
+		},
+		{
+			name: "Error updating a member returns those already reassigned",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(duplicates, countResponse(0), updated, countResponse(0), mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    2,
+					Message: "update failed",
+				}))
+			},
+			expectedReassigned: []MemberIdReassignment{{OldId: 100042, NewId: 100001}},
+			wantErr:            true,
+		},
+		{
+			name: "Error finding shared ids",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    2,
+					Message: "aggregate failed",
+				}))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			reassigned, err := ReassignDuplicateMemberIds(context.Background(), mt.Coll, NewMemoryIdAllocator(MemberIdSequence))
+
+			if tc.wantErr {
+				assert.Errorf(t, err, "Want error but got: %v", err)
+			} else {
+				assert.NoErrorf(t, err, "Not expecting error")
+			}
+			assert.Equal(t, tc.expectedReassigned, reassigned)
+		})
+	}
+}
+
+func TestNormalizeMemberEmails(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	noSharedEmails := mtest.CreateCursorResponse(0, "members.members", mtest.FirstBatch)
+	updated := bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}}
+
+	mt.Run("Success lower casing emails", func(mt *mtest.T) {
+		mt.AddMockResponses(noSharedEmails, mtest.CreateCursorResponse(0, "members.members", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "first"}, {Key: "email", Value: "Rafael.Nadal@gmail.com"}},
+			bson.D{{Key: "_id", Value: "second"}, {Key: "email", Value: " roger.federer@gmail.com"}},
+		), updated, updated)
+		updatedCount, err := NormalizeMemberEmails(context.Background(), mt.Coll)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), updatedCount)
+		events := mt.GetAllStartedEvents()
+		require.Len(t, events, 4)
+		assert.Equal(t, "rafael.nadal@gmail.com", events[2].Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set", "email").StringValue())
+		assert.Equal(t, "roger.federer@gmail.com", events[3].Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set", "email").StringValue())
+	})
+
+	mt.Run("Error listing emails members share", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "members.members", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "rafael.nadal@gmail.com"}, {Key: "memberIds", Value: bson.A{100002, 100007}}},
+			bson.D{{Key: "_id", Value: "roger.federer@gmail.com"}, {Key: "memberIds", Value: bson.A{100003, 100004, 100005}}},
+		))
+		updatedCount, err := NormalizeMemberEmails(context.Background(), mt.Coll)
+
+		assert.ErrorIs(t, err, ErrSharedEmails)
+		assert.EqualError(t, err, "members share emails, ignoring case: rafael.nadal@gmail.com (members 100002, 100007); "+
+			"roger.federer@gmail.com (members 100003, 100004, 100005)")
+		assert.Zero(t, updatedCount)
+		assert.Len(t, mt.GetAllStartedEvents(), 1)
+	})
+
+	mt.Run("Error updating an email", func(mt *mtest.T) {
+		mt.AddMockResponses(noSharedEmails, mtest.CreateCursorResponse(0, "members.members", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "first"}, {Key: "email", Value: "Rafael.Nadal@gmail.com"}},
+		), mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "update failed"}))
+		updatedCount, err := NormalizeMemberEmails(context.Background(), mt.Coll)
+
+		assert.Error(t, err)
+		assert.Zero(t, updatedCount)
+	})
+}
+
+func TestCountMembersWithPlan(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	mt.Run("Success counting members with plan", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, bson.D{{Key: "n", Value: 3}}))
+		repo := NewMembershipRepository(mt.Coll)
+		count, err := repo.CountMembersWithPlan(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+}
+
+func TestRenewMember(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	renewal := models.Renewal{RenewedOn: "2024-06-01", PlanId: 1, PreviousExpiryDate: "2024-06-20", ExpiryDate: "2025-06-20"}
+
+	testCases := []struct {
+		name        string
+		mongoDbMock func(mt *mtest.T)
+		expectedErr error
+	}{
+		{
+			name: "Success renewing member",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+			},
+		},
+		{
+			name: "Member changed since it was read",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+				)
+			},
+			expectedErr: ErrVersionConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			repo := NewMembershipRepository(mt.Coll)
+			err := repo.RenewMember(context.Background(), 1, 2, renewal)
+
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			} else {
+				assert.NoErrorf(t, err, "Not expecting error")
+			}
+		})
+	}
+}
+
+func TestLapseExpiredMembers(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	mt.Run("Success lapsing expired members", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{int32(1), int32(2), int32(3)}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+		repo := NewMembershipRepository(mt.Coll)
+		lapsed, err := repo.LapseExpiredMembers(context.Background(), "2024-06-01")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 3}, lapsed)
+	})
+
+	mt.Run("Error lapsing expired members", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{int32(1), int32(2)}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code:    2,
+				Message: "update failed",
+			}),
+		)
+		repo := NewMembershipRepository(mt.Coll)
+		lapsed, err := repo.LapseExpiredMembers(context.Background(), "2024-06-01")
+
+		assert.Error(t, err)
+		assert.Equal(t, []int{1}, lapsed)
+	})
+}
+
+func TestChangeMemberStatus(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	change := models.StatusChange{From: models.MemberActive, To: models.MemberSuspended, Reason: "Unpaid fees", ChangedAt: "2024-06-01T09:30:00Z"}
+
+	testCases := []struct {
+		name        string
+		mongoDbMock func(mt *mtest.T)
+		expectedErr error
+	}{
+		{
+			name: "Success changing member status",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+			},
+		},
+		{
+			name: "Member deleted since it was read",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+					mtest.CreateCursorResponse(0, "membership.members", mtest.FirstBatch),
+				)
+			},
+			expectedErr: mongo.ErrNoDocuments,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			repo := NewMembershipRepository(mt.Coll)
+			err := repo.ChangeMemberStatus(context.Background(), 1, 2, change)
+
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			} else {
+				assert.NoErrorf(t, err, "Not expecting error")
+			}
+		})
+	}
+}
+
+func TestRestoreMemberById(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	testCases := []struct {
+		name           string
+		mongoDbMock    func(mt *mtest.T)
+		expectedMember *models.Member
+		expectedErr    error
+	}{
+		{
+			name: "Success restoring deleted member",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+					{Key: "id", Value: 1},
+					{Key: "deletedat", Value: "2024-06-01T09:30:00Z"},
+					{Key: "version", Value: 2},
+				}}))
+			},
+			expectedMember: &models.Member{ID: 1, DeletedAt: "2024-06-01T09:30:00Z", Version: 2},
+		},
+		{
+			name: "No deleted member with the id",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+			},
+			expectedErr: mongo.ErrNoDocuments,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			repo := NewMembershipRepository(mt.Coll)
+			member, err := repo.RestoreMemberById(context.Background(), 1)
+
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			} else {
+				assert.NoErrorf(t, err, "Not expecting error")
+				assert.Equal(t, tc.expectedMember, member)
+			}
+		})
+	}
+}
+
+func TestPurgeDeletedMembers(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	mt.Run("Success purging deleted members", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{int32(4), int32(5)}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+		repo := NewMembershipRepository(mt.Coll)
+		purged, err := repo.PurgeDeletedMembers(context.Background(), "2024-05-01T00:00:00Z")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{4, 5}, purged)
+	})
+}
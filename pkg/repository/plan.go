@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+const planIdIndex = "id_unique"
+
+var ErrDuplicatePlanId = errors.New("plan id already exists")
+
+type PlanRepositoryI interface {
+	CreatePlan(ctx context.Context, plan *models.Plan) error
+	GetPlanById(ctx context.Context, planId int) (*models.Plan, error)
+	GetAllPlans(ctx context.Context) ([]models.Plan, error)
+	UpdatePlanById(ctx context.Context, plan *models.UpdatePlan, planId int) error
+	DeletePlanById(ctx context.Context, planId int) (*models.Plan, error)
+}
+
+type PlanRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPlanRepository(collection *mongo.Collection) PlanRepositoryI {
+	return &PlanRepository{
+		collection: collection,
+	}
+}
+
+func (p *PlanRepository) CreatePlan(ctx context.Context, plan *models.Plan) error {
+	_, err := p.collection.InsertOne(ctx, plan)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicatePlanId
+	}
+	return err
+}
+
+func (p *PlanRepository) GetPlanById(ctx context.Context, planId int) (*models.Plan, error) {
+	var plan models.Plan
+	err := p.collection.FindOne(ctx, bson.M{"id": planId}).Decode(&plan)
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// GetAllPlans returns every plan ordered by id. There are only ever a handful
+// of plans, so they are not paginated.
+func (p *PlanRepository) GetAllPlans(ctx context.Context) ([]models.Plan, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "id", Value: 1}})
+	result, err := p.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]models.Plan, 0)
+	if err := result.All(ctx, &plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (p *PlanRepository) UpdatePlanById(ctx context.Context, plan *models.UpdatePlan, planId int) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":           plan.Name,
+			"durationmonths": plan.DurationMonths,
+			"pricecents":     plan.PriceCents,
+			"benefits":       plan.Benefits,
+		},
+	}
+	result, err := p.collection.UpdateOne(ctx, bson.M{"id": planId}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeletePlanById deletes the plan and returns it as it was, so that it can be
+// created again if the delete has to be undone.
+func (p *PlanRepository) DeletePlanById(ctx context.Context, planId int) (*models.Plan, error) {
+	var plan models.Plan
+	err := p.collection.FindOneAndDelete(ctx, bson.M{"id": planId}).Decode(&plan)
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// CreatePlanIndexes creates the indexes the plans collection relies on. It is
+// safe to call on every startup.
+func CreatePlanIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetName(planIdIndex).SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+// runPlanRepositoryConformance checks the behaviour every PlanRepositoryI
+// implementation must share. newRepository must return an empty repository.
+func runPlanRepositoryConformance(t *testing.T, newRepository func(t *testing.T) PlanRepositoryI) {
+	newPlan := func(id int) *models.Plan {
+		return &models.Plan{
+			ID:             id,
+			Name:           fmt.Sprintf("Plan %d", id),
+			DurationMonths: 12,
+			PriceCents:     12000,
+			Benefits:       []string{"Sauna", "Towel service"},
+		}
+	}
+
+	t.Run("Create and get plan by id", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreatePlan(ctx, newPlan(1)))
+
+		plan, err := repo.GetPlanById(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, newPlan(1), plan)
+
+		err = repo.CreatePlan(ctx, newPlan(1))
+		assert.True(t, errors.Is(err, ErrDuplicatePlanId))
+	})
+
+	t.Run("Get all plans", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		plans, err := repo.GetAllPlans(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, plans)
+
+		require.NoError(t, repo.CreatePlan(ctx, newPlan(2)))
+		require.NoError(t, repo.CreatePlan(ctx, newPlan(1)))
+
+		plans, err = repo.GetAllPlans(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Plan{*newPlan(1), *newPlan(2)}, plans)
+	})
+
+	t.Run("Update plan by id", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreatePlan(ctx, newPlan(1)))
+		update := &models.UpdatePlan{Name: "Platinum", DurationMonths: 24, PriceCents: 20000, Benefits: []string{"Parking"}}
+		require.NoError(t, repo.UpdatePlanById(ctx, update, 1))
+
+		plan, err := repo.GetPlanById(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &models.Plan{ID: 1, Name: "Platinum", DurationMonths: 24, PriceCents: 20000, Benefits: []string{"Parking"}}, plan)
+
+		err = repo.UpdatePlanById(ctx, update, 2)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+	})
+
+	t.Run("Delete plan by id", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.CreatePlan(ctx, newPlan(1)))
+		deleted, err := repo.DeletePlanById(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, newPlan(1), deleted)
+
+		_, err = repo.GetPlanById(ctx, 1)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+		_, err = repo.DeletePlanById(ctx, 1)
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+	})
+}
+
+func TestMemoryPlanRepositoryConformance(t *testing.T) {
+	t.Parallel()
+
+	runPlanRepositoryConformance(t, func(t *testing.T) PlanRepositoryI {
+		return NewMemoryPlanRepository()
+	})
+}
+
+// TestMongoPlanRepositoryConformance runs against a real MongoDB server when
+// MONGODB_TEST_URI is set, like TestMongoMemberRepositoryConformance.
+func TestMongoPlanRepositoryConformance(t *testing.T) {
+	mongoUri := os.Getenv("MONGODB_TEST_URI")
+	if mongoUri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoUri))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Disconnect(context.Background())
+	})
+
+	runPlanRepositoryConformance(t, func(t *testing.T) PlanRepositoryI {
+		mongoDb := client.Database(fmt.Sprintf("membership_test_%d", time.Now().UnixNano()))
+		t.Cleanup(func() {
+			mongoDb.Drop(context.Background())
+		})
+		require.NoError(t, CreatePlanIndexes(context.Background(), mongoDb.Collection("plans")))
+		return NewPlanRepository(mongoDb.Collection("plans"))
+	})
+}
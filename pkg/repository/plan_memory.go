@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/models"
+)
+
+// MemoryPlanRepository keeps plans in process memory, mirroring PlanRepository
+// the same way MemoryMemberRepository mirrors MemberRepository.
+type MemoryPlanRepository struct {
+	mu    sync.RWMutex
+	plans map[int]models.Plan
+}
+
+func NewMemoryPlanRepository() PlanRepositoryI {
+	return &MemoryPlanRepository{
+		plans: make(map[int]models.Plan),
+	}
+}
+
+func (p *MemoryPlanRepository) CreatePlan(ctx context.Context, plan *models.Plan) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, exists := p.plans[plan.ID]; exists {
+		return ErrDuplicatePlanId
+	}
+	p.plans[plan.ID] = copyPlan(*plan)
+	return nil
+}
+
+func (p *MemoryPlanRepository) GetPlanById(ctx context.Context, planId int) (*models.Plan, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	plan, exists := p.plans[planId]
+	if !exists {
+		return nil, mongo.ErrNoDocuments
+	}
+	plan = copyPlan(plan)
+	return &plan, nil
+}
+
+func (p *MemoryPlanRepository) GetAllPlans(ctx context.Context) ([]models.Plan, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	plans := make([]models.Plan, 0, len(p.plans))
+	for _, plan := range p.plans {
+		plans = append(plans, copyPlan(plan))
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].ID < plans[j].ID
+	})
+	return plans, nil
+}
+
+func (p *MemoryPlanRepository) UpdatePlanById(ctx context.Context, plan *models.UpdatePlan, planId int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, exists := p.plans[planId]; !exists {
+		return mongo.ErrNoDocuments
+	}
+	p.plans[planId] = copyPlan(models.Plan{
+		ID:             planId,
+		Name:           plan.Name,
+		DurationMonths: plan.DurationMonths,
+		PriceCents:     plan.PriceCents,
+		Benefits:       plan.Benefits,
+	})
+	return nil
+}
+
+func (p *MemoryPlanRepository) DeletePlanById(ctx context.Context, planId int) (*models.Plan, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	plan, exists := p.plans[planId]
+	if !exists {
+		return nil, mongo.ErrNoDocuments
+	}
+	delete(p.plans, planId)
+	deleted := copyPlan(plan)
+	return &deleted, nil
+}
+
+// copyPlan stops callers from sharing the stored benefits slice.
+func copyPlan(plan models.Plan) models.Plan {
+	if plan.Benefits != nil {
+		plan.Benefits = append([]string{}, plan.Benefits...)
+	}
+	return plan
+}
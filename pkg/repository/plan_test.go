@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"members.com/membership/pkg/models"
+)
+
+func TestCreatePlan(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	testCases := []struct {
+		name        string
+		mongoDbMock func(mt *mtest.T)
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name: "Success creating new plan",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse())
+			},
+			wantErr: false,
+		},
+		{
+			name: "Error creating plan with existing id",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+					Index:   0,
+					Code:    11000,
+					Message: "E11000 duplicate key error collection: membership.plans index: id_unique dup key: { id: 1 }",
+				}))
+			},
+			wantErr:     true,
+			expectedErr: ErrDuplicatePlanId,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			repo := NewPlanRepository(mt.Coll)
+			err := repo.CreatePlan(context.Background(), &models.Plan{ID: 1, Name: "Gold", DurationMonths: 12})
+
+			if tc.wantErr {
+				assert.Errorf(t, err, "Want error but got: %v", err)
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			} else {
+				assert.NoErrorf(t, err, "Not expecting error")
+			}
+		})
+	}
+}
+
+func TestGetPlanById(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	mt.Run("Success getting plan by id", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "membership.plans", mtest.FirstBatch, bson.D{
+			{Key: "id", Value: 1},
+			{Key: "name", Value: "Gold"},
+			{Key: "durationmonths", Value: 12},
+			{Key: "pricecents", Value: int64(12000)},
+			{Key: "benefits", Value: bson.A{"Sauna"}},
+		}))
+		repo := NewPlanRepository(mt.Coll)
+		plan, err := repo.GetPlanById(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &models.Plan{ID: 1, Name: "Gold", DurationMonths: 12, PriceCents: 12000, Benefits: []string{"Sauna"}}, plan)
+	})
+
+	mt.Run("Plan not found", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "membership.plans", mtest.FirstBatch))
+		repo := NewPlanRepository(mt.Coll)
+		_, err := repo.GetPlanById(context.Background(), 1)
+
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+	})
+}
+
+func TestGetAllPlans(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	mt.Run("Success getting all plans", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "membership.plans", mtest.FirstBatch,
+			bson.D{{Key: "id", Value: 1}, {Key: "name", Value: "Gold"}, {Key: "durationmonths", Value: 12}},
+			bson.D{{Key: "id", Value: 2}, {Key: "name", Value: "Trial"}, {Key: "durationmonths", Value: 1}},
+		))
+		repo := NewPlanRepository(mt.Coll)
+		plans, err := repo.GetAllPlans(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Plan{
+			{ID: 1, Name: "Gold", DurationMonths: 12},
+			{ID: 2, Name: "Trial", DurationMonths: 1},
+		}, plans)
+	})
+
+	mt.Run("Error getting all plans", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    2,
+			Message: "find failed",
+		}))
+		repo := NewPlanRepository(mt.Coll)
+		_, err := repo.GetAllPlans(context.Background())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdatePlanById(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	testCases := []struct {
+		name        string
+		mongoDbMock func(mt *mtest.T)
+		expectedErr error
+	}{
+		{
+			name: "Success updating existing plan",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+			},
+		},
+		{
+			name: "Update non-existing plan",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+			},
+			expectedErr: mongo.ErrNoDocuments,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			repo := NewPlanRepository(mt.Coll)
+			err := repo.UpdatePlanById(context.Background(), &models.UpdatePlan{Name: "Gold", DurationMonths: 12}, 1)
+
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			} else {
+				assert.NoErrorf(t, err, "Not expecting error")
+			}
+		})
+	}
+}
+
+func TestDeletePlanById(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("members").ClientType(mtest.Mock))
+
+	plan := &models.Plan{ID: 1, Name: "Gold", DurationMonths: 12, PriceCents: 12000, Benefits: []string{"Sauna"}}
+
+	testCases := []struct {
+		name         string
+		mongoDbMock  func(mt *mtest.T)
+		expectedPlan *models.Plan
+		expectedErr  error
+	}{
+		{
+			name: "Success deleting existing plan",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(bson.D{
+					{Key: "ok", Value: 1},
+					{Key: "value", Value: bson.D{
+						{Key: "id", Value: 1},
+						{Key: "name", Value: "Gold"},
+						{Key: "durationmonths", Value: 12},
+						{Key: "pricecents", Value: int64(12000)},
+						{Key: "benefits", Value: bson.A{"Sauna"}},
+					}},
+				})
+			},
+			expectedPlan: plan,
+		},
+		{
+			name: "Delete non-existing plan",
+			mongoDbMock: func(mt *mtest.T) {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+			},
+			expectedErr: mongo.ErrNoDocuments,
+		},
+	}
+
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			tc.mongoDbMock(mt)
+			repo := NewPlanRepository(mt.Coll)
+			deleted, err := repo.DeletePlanById(context.Background(), 1)
+
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			} else {
+				assert.NoErrorf(t, err, "Not expecting error")
+			}
+			assert.Equal(t, tc.expectedPlan, deleted)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+const roleNameIndex = "name_unique"
+
+type RoleRepositoryI interface {
+	SaveRole(ctx context.Context, role *models.Role) error
+	GetRoleByName(ctx context.Context, name string) (*models.Role, error)
+	GetAllRoles(ctx context.Context) ([]models.Role, error)
+	DeleteRoleByName(ctx context.Context, name string) error
+}
+
+type RoleRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRoleRepository(collection *mongo.Collection) RoleRepositoryI {
+	return &RoleRepository{
+		collection: collection,
+	}
+}
+
+// SaveRole creates the role or replaces the one with the same name.
+func (r *RoleRepository) SaveRole(ctx context.Context, role *models.Role) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"name": role.Name}, role, opts)
+	return err
+}
+
+func (r *RoleRepository) GetRoleByName(ctx context.Context, name string) (*models.Role, error) {
+	var role models.Role
+	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&role)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetAllRoles returns every role ordered by name.
+func (r *RoleRepository) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	result, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]models.Role, 0)
+	if err := result.All(ctx, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) DeleteRoleByName(ctx context.Context, name string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// CreateRoleIndexes creates the indexes the roles collection relies on. It is
+// safe to call on every startup.
+func CreateRoleIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetName(roleNameIndex).SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"members.com/membership/pkg/models"
+)
+
+// runRoleRepositoryConformance checks the behaviour every RoleRepositoryI
+// implementation must share. newRepository must return an empty repository.
+func runRoleRepositoryConformance(t *testing.T, newRepository func(t *testing.T) RoleRepositoryI) {
+	t.Run("Save, get and replace role", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		_, err := repo.GetRoleByName(ctx, "front-desk")
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+
+		frontDesk := &models.Role{Name: "front-desk", Permissions: []string{"member:read"}}
+		require.NoError(t, repo.SaveRole(ctx, frontDesk))
+		frontDesk.Permissions = append(frontDesk.Permissions, "member:write")
+		require.NoError(t, repo.SaveRole(ctx, frontDesk))
+
+		role, err := repo.GetRoleByName(ctx, "front-desk")
+		require.NoError(t, err)
+		assert.Equal(t, frontDesk, role)
+	})
+
+	t.Run("Get all roles", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		roles, err := repo.GetAllRoles(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, roles)
+
+		require.NoError(t, repo.SaveRole(ctx, &models.Role{Name: "front-desk", Permissions: []string{"member:read"}}))
+		require.NoError(t, repo.SaveRole(ctx, &models.Role{Name: "admin", Permissions: []string{"member:purge"}}))
+
+		roles, err = repo.GetAllRoles(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Role{
+			{Name: "admin", Permissions: []string{"member:purge"}},
+			{Name: "front-desk", Permissions: []string{"member:read"}},
+		}, roles)
+	})
+
+	t.Run("Delete role by name", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.SaveRole(ctx, &models.Role{Name: "front-desk", Permissions: []string{"member:read"}}))
+		require.NoError(t, repo.DeleteRoleByName(ctx, "front-desk"))
+
+		_, err := repo.GetRoleByName(ctx, "front-desk")
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+		err = repo.DeleteRoleByName(ctx, "front-desk")
+		assert.True(t, errors.Is(err, mongo.ErrNoDocuments))
+	})
+}
+
+func TestMemoryRoleRepositoryConformance(t *testing.T) {
+	t.Parallel()
+
+	runRoleRepositoryConformance(t, func(t *testing.T) RoleRepositoryI {
+		return NewMemoryRoleRepository()
+	})
+}
+
+// TestMongoRoleRepositoryConformance runs against a real MongoDB server when
+// MONGODB_TEST_URI is set, like TestMongoMemberRepositoryConformance.
+func TestMongoRoleRepositoryConformance(t *testing.T) {
+	mongoUri := os.Getenv("MONGODB_TEST_URI")
+	if mongoUri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoUri))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Disconnect(context.Background())
+	})
+
+	runRoleRepositoryConformance(t, func(t *testing.T) RoleRepositoryI {
+		mongoDb := client.Database(fmt.Sprintf("membership_test_%d", time.Now().UnixNano()))
+		t.Cleanup(func() {
+			mongoDb.Drop(context.Background())
+		})
+		require.NoError(t, CreateRoleIndexes(context.Background(), mongoDb.Collection("roles")))
+		return NewRoleRepository(mongoDb.Collection("roles"))
+	})
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/models"
+)
+
+// MemoryRoleRepository keeps roles in process memory, mirroring
+// RoleRepository.
+type MemoryRoleRepository struct {
+	mu    sync.RWMutex
+	roles map[string]models.Role
+}
+
+func NewMemoryRoleRepository() RoleRepositoryI {
+	return &MemoryRoleRepository{
+		roles: make(map[string]models.Role),
+	}
+}
+
+func (r *MemoryRoleRepository) SaveRole(ctx context.Context, role *models.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.roles[role.Name] = copyRole(*role)
+	return nil
+}
+
+func (r *MemoryRoleRepository) GetRoleByName(ctx context.Context, name string) (*models.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	role, exists := r.roles[name]
+	if !exists {
+		return nil, mongo.ErrNoDocuments
+	}
+	role = copyRole(role)
+	return &role, nil
+}
+
+func (r *MemoryRoleRepository) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	roles := make([]models.Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		roles = append(roles, copyRole(role))
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+	return roles, nil
+}
+
+func (r *MemoryRoleRepository) DeleteRoleByName(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.roles[name]; !exists {
+		return mongo.ErrNoDocuments
+	}
+	delete(r.roles, name)
+	return nil
+}
+
+// copyRole stops callers from sharing the stored permissions slice.
+func copyRole(role models.Role) models.Role {
+	if role.Permissions != nil {
+		role.Permissions = append([]string{}, role.Permissions...)
+	}
+	return role
+}
@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/service"
+	"members.com/membership/pkg/utils"
+)
+
+// LapseJob marks members whose membership has expired as lapsed, recording
+// each one in the audit log.
+type LapseJob struct {
+	memberRepository repository.MemberRepositoryI
+	auditor          *service.MemberAuditor
+	logger           *slog.Logger
+}
+
+func NewLapseJob(memberRepository repository.MemberRepositoryI, auditor *service.MemberAuditor, logger *slog.Logger) Job {
+	return &LapseJob{
+		memberRepository: memberRepository,
+		auditor:          auditor,
+		logger:           logger,
+	}
+}
+
+func (l *LapseJob) Name() string {
+	return "lapse-expired-members"
+}
+
+func (l *LapseJob) Run(ctx context.Context, now time.Time) error {
+	today := utils.FormatDate(now)
+	lapsed, err := l.memberRepository.LapseExpiredMembers(ctx, today)
+	l.auditor.RecordLapses(ctx, lapsed, today)
+	if len(lapsed) > 0 {
+		l.logger.InfoContext(ctx, "lapsed expired members", "job", l.Name(), "lapsed", len(lapsed))
+	}
+	return err
+}
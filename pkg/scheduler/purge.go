@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/service"
+	"members.com/membership/pkg/utils"
+)
+
+// PurgeJob permanently removes members that were deleted longer ago than the
+// retention period, recording each one in the audit log.
+type PurgeJob struct {
+	memberRepository repository.MemberRepositoryI
+	auditor          *service.MemberAuditor
+	retention        time.Duration
+	logger           *slog.Logger
+}
+
+func NewPurgeJob(memberRepository repository.MemberRepositoryI, auditor *service.MemberAuditor, retention time.Duration, logger *slog.Logger) Job {
+	return &PurgeJob{
+		memberRepository: memberRepository,
+		auditor:          auditor,
+		retention:        retention,
+		logger:           logger,
+	}
+}
+
+func (p *PurgeJob) Name() string {
+	return "purge-deleted-members"
+}
+
+func (p *PurgeJob) Run(ctx context.Context, now time.Time) error {
+	purged, err := p.memberRepository.PurgeDeletedMembers(ctx, utils.FormatTimestamp(now.Add(-p.retention)))
+	p.auditor.RecordPurges(ctx, purged)
+	if len(purged) > 0 {
+		p.logger.InfoContext(ctx, "purged deleted members", "job", p.Name(), "purged", len(purged))
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"members.com/membership/pkg/utils"
+)
+
+// Job is a unit of background work. Jobs may run again after a failure or on
+// several instances at once, so running one twice for the same time must not
+// change anything the first run didn't.
+type Job interface {
+	Name() string
+	Run(ctx context.Context, now time.Time) error
+}
+
+// Scheduler runs its jobs one after the other, once on start and then every
+// interval, until its context is cancelled.
+type Scheduler struct {
+	clock    utils.Clock
+	interval time.Duration
+	jobs     []Job
+	logger   *slog.Logger
+	done     chan struct{}
+}
+
+func NewScheduler(clock utils.Clock, interval time.Duration, logger *slog.Logger, jobs ...Job) *Scheduler {
+	return &Scheduler{
+		clock:    clock,
+		interval: interval,
+		jobs:     jobs,
+		logger:   logger,
+	}
+}
+
+// Start runs the jobs in the background and returns straight away.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.RunOnce(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RunOnce(ctx)
+			}
+		}
+	}()
+}
+
+// Wait blocks until a started scheduler has stopped, which it does once its
+// context is cancelled and the jobs running at the time have finished.
+func (s *Scheduler) Wait() {
+	if s.done != nil {
+		<-s.done
+	}
+}
+
+// RunOnce runs every job with the clock's current time. A failing job is
+// logged and retried on the next run, it doesn't stop the others.
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	now := s.clock.Now()
+	for _, job := range s.jobs {
+		if err := job.Run(ctx, now); err != nil {
+			s.logger.ErrorContext(ctx, "scheduled job failed", "job", job.Name(), "error", err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/service"
+	"members.com/membership/pkg/utils"
+)
+
+type recordingJob struct {
+	mu   sync.Mutex
+	runs []time.Time
+	err  error
+}
+
+func (r *recordingJob) Name() string {
+	return "recording"
+}
+
+func (r *recordingJob) Run(ctx context.Context, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, now)
+	return r.err
+}
+
+func (r *recordingJob) runCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.runs)
+}
+
+func TestRunOnce(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, time.June, 1, 9, 30, 0, 0, time.UTC)
+	failing := &recordingJob{err: errors.New("job failed")}
+	succeeding := &recordingJob{}
+
+	NewScheduler(utils.FixedClock{Time: now}, time.Hour, logging.Discard(), failing, succeeding).RunOnce(context.Background())
+
+	assert.Equal(t, []time.Time{now}, failing.runs)
+	assert.Equal(t, []time.Time{now}, succeeding.runs)
+}
+
+func TestStart(t *testing.T) {
+	t.Parallel()
+
+	job := &recordingJob{}
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs := NewScheduler(utils.SystemClock{}, 10*time.Millisecond, logging.Discard(), job)
+	jobs.Start(ctx)
+
+	assert.Eventually(t, func() bool { return job.runCount() >= 3 }, time.Second, 5*time.Millisecond)
+	cancel()
+	jobs.Wait()
+	runs := job.runCount()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, runs, job.runCount())
+}
+
+func TestLapseJob(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	memberRepository := repository.NewMemoryMemberRepository()
+	for id, expiryDate := range map[int]string{1: "2024-05-31", 2: "2024-07-01"} {
+		require.NoError(t, memberRepository.CreateMember(ctx, &models.Member{
+			ID:          id,
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       fmt.Sprintf("john.doe.%d@gmail.com", id),
+			DateOfBirth: "1990-01-01",
+			PlanId:      1,
+			StartDate:   "2023-06-01",
+			ExpiryDate:  expiryDate,
+			Version:     1,
+		}))
+	}
+
+	auditRepository := repository.NewMemoryAuditRepository()
+	runAt := time.Date(2024, time.June, 1, 0, 5, 0, 0, time.UTC)
+	auditor := service.NewMemberAuditor(auditRepository, utils.FixedClock{Time: runAt}, logging.Discard())
+	job := NewLapseJob(memberRepository, auditor, logging.Discard())
+	require.NoError(t, job.Run(ctx, runAt))
+	// A second run for the same day finds nothing left to do.
+	require.NoError(t, job.Run(ctx, runAt))
+
+	expired, err := memberRepository.GetMemberById(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06-01", expired.LapsedOn)
+	assert.Equal(t, 2, expired.Version)
+
+	current, err := memberRepository.GetMemberById(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, current.LapsedOn)
+	assert.Equal(t, 1, current.Version)
+
+	entries, err := auditRepository.GetAuditEntries(ctx, models.AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, entries.Items, 1)
+	assert.Equal(t, models.AuditEntry{
+		ID:        entries.Items[0].ID,
+		Actor:     models.AuditSystemActor,
+		Timestamp: "2024-06-01T00:05:00Z",
+		Action:    models.AuditLapse,
+		MemberId:  1,
+		Changes:   []models.FieldChange{{Field: "lapsedOn", After: "2024-06-01"}},
+	}, entries.Items[0])
+}
+
+func TestPurgeJob(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	memberRepository := repository.NewMemoryMemberRepository()
+	for id, deletedAt := range map[int]string{1: "2024-04-30T23:00:00Z", 2: "2024-05-15T08:00:00Z"} {
+		require.NoError(t, memberRepository.CreateMember(ctx, &models.Member{
+			ID:          id,
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       fmt.Sprintf("john.doe.%d@gmail.com", id),
+			DateOfBirth: "1990-01-01",
+			Version:     1,
+		}))
+		require.NoError(t, memberRepository.DeleteMemberById(ctx, id, 1, deletedAt))
+	}
+
+	auditRepository := repository.NewMemoryAuditRepository()
+	runAt := time.Date(2024, time.June, 1, 0, 5, 0, 0, time.UTC)
+	auditor := service.NewMemberAuditor(auditRepository, utils.FixedClock{Time: runAt}, logging.Discard())
+	job := NewPurgeJob(memberRepository, auditor, 30*24*time.Hour, logging.Discard())
+	require.NoError(t, job.Run(ctx, runAt))
+
+	deleted, err := memberRepository.GetAllMembers(ctx, models.MemberQuery{Deleted: true})
+	require.NoError(t, err)
+	require.Len(t, deleted.Items, 1)
+	assert.Equal(t, 2, deleted.Items[0].ID)
+	entries, err := auditRepository.GetAuditEntries(ctx, models.AuditQuery{Action: models.AuditPurge})
+	require.NoError(t, err)
+	require.Len(t, entries.Items, 1)
+	assert.Equal(t, 1, entries.Items[0].MemberId)
+	assert.Empty(t, entries.Items[0].Changes)
+}
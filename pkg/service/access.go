@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/auth"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/validation"
+)
+
+// AccessServiceI manages the roles that bundle permissions and the grants
+// that give principals roles.
+type AccessServiceI interface {
+	GetAllRoles(ctx context.Context) models.Response
+	SaveRole(ctx context.Context, name string, role *models.UpdateRole) models.Response
+	DeleteRoleByName(ctx context.Context, name string) models.Response
+	GetAllGrants(ctx context.Context) models.Response
+	SaveGrant(ctx context.Context, subject string, grant *models.UpdateGrant) models.Response
+	DeleteGrantBySubject(ctx context.Context, subject string) models.Response
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+type AccessService struct {
+	roleRepository  repository.RoleRepositoryI
+	grantRepository repository.GrantRepositoryI
+	logger          *slog.Logger
+}
+
+func NewAccessService(roleRepository repository.RoleRepositoryI, grantRepository repository.GrantRepositoryI, logger *slog.Logger) AccessServiceI {
+	return &AccessService{
+		roleRepository:  roleRepository,
+		grantRepository: grantRepository,
+		logger:          logger,
+	}
+}
+
+func (a *AccessService) GetAllRoles(ctx context.Context) models.Response {
+	roles, err := a.roleRepository.GetAllRoles(ctx)
+	if err != nil {
+		return createInternalErrorResponse(ctx, a.logger, err, "Error fetching roles")
+	}
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       roles,
+	}
+}
+
+// SaveRole creates the role or replaces its permissions.
+func (a *AccessService) SaveRole(ctx context.Context, name string, role *models.UpdateRole) models.Response {
+	var fieldErrors []models.FieldError
+	if !roleNamePattern.MatchString(name) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "name", Code: validation.CodeInvalid, Message: "Role names are lower case letters, digits and dashes"})
+	}
+	for _, permission := range role.Permissions {
+		if !auth.IsPermission(permission) {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "permissions", Code: validation.CodeUnknownPermission, Message: fmt.Sprintf("Unknown permission %s", permission)})
+		}
+	}
+	if fieldErrors != nil {
+		return createValidationErrorResponse(fieldErrors)
+	}
+
+	savedRole := &models.Role{Name: name, Permissions: role.Permissions}
+	if err := a.roleRepository.SaveRole(ctx, savedRole); err != nil {
+		return createInternalErrorResponse(ctx, a.logger, err, "Error saving role")
+	}
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       savedRole,
+	}
+}
+
+// DeleteRoleByName refuses to delete a role that is still granted.
+func (a *AccessService) DeleteRoleByName(ctx context.Context, name string) models.Response {
+	grants, err := a.grantRepository.CountGrantsWithRole(ctx, name)
+	if err != nil {
+		return createInternalErrorResponse(ctx, a.logger, err, fmt.Sprintf("Could not delete role %s", name))
+	}
+	if grants > 0 {
+		return createErrorResponse(http.StatusConflict, fmt.Sprintf("Role %s cannot be deleted while %d grant(s) give it", name, grants))
+	}
+
+	err = a.roleRepository.DeleteRoleByName(ctx, name)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return createErrorResponse(http.StatusNotFound, fmt.Sprintf("Role %s not found", name))
+	}
+	if err != nil {
+		return createInternalErrorResponse(ctx, a.logger, err, fmt.Sprintf("Could not delete role %s", name))
+	}
+	return createSuccessResponse(http.StatusOK, fmt.Sprintf("Role %s deleted", name))
+}
+
+func (a *AccessService) GetAllGrants(ctx context.Context) models.Response {
+	grants, err := a.grantRepository.GetAllGrants(ctx)
+	if err != nil {
+		return createInternalErrorResponse(ctx, a.logger, err, "Error fetching grants")
+	}
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       grants,
+	}
+}
+
+// SaveGrant gives the subject, a principal ID such as jwt:jane.smith, exactly
+// the roles listed.
+func (a *AccessService) SaveGrant(ctx context.Context, subject string, grant *models.UpdateGrant) models.Response {
+	var fieldErrors []models.FieldError
+	method, name, _ := strings.Cut(subject, ":")
+	if (method != auth.MethodApiKey && method != auth.MethodToken) || name == "" {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "subject", Code: validation.CodeInvalid, Message: fmt.Sprintf("Subject must start with %s: or %s:", auth.MethodApiKey, auth.MethodToken)})
+	}
+	for _, roleName := range grant.Roles {
+		_, err := a.roleRepository.GetRoleByName(ctx, roleName)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "roles", Code: validation.CodeUnknownRole, Message: fmt.Sprintf("Role %s does not exist", roleName)})
+			continue
+		}
+		if err != nil {
+			return createInternalErrorResponse(ctx, a.logger, err, "Error fetching role")
+		}
+	}
+	if fieldErrors != nil {
+		return createValidationErrorResponse(fieldErrors)
+	}
+
+	savedGrant := &models.Grant{Subject: subject, Roles: grant.Roles}
+	if err := a.grantRepository.SaveGrant(ctx, savedGrant); err != nil {
+		return createInternalErrorResponse(ctx, a.logger, err, "Error saving grant")
+	}
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       savedGrant,
+	}
+}
+
+func (a *AccessService) DeleteGrantBySubject(ctx context.Context, subject string) models.Response {
+	err := a.grantRepository.DeleteGrantBySubject(ctx, subject)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return createErrorResponse(http.StatusNotFound, fmt.Sprintf("Grant for %s not found", subject))
+	}
+	if err != nil {
+		return createInternalErrorResponse(ctx, a.logger, err, fmt.Sprintf("Could not delete grant for %s", subject))
+	}
+	return createSuccessResponse(http.StatusOK, fmt.Sprintf("Grant for %s deleted", subject))
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/validation"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+type MockGrantRepository struct {
+	mock.Mock
+}
+
+func TestSaveRole(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		roleName           string
+		role               *models.UpdateRole
+		roleRepoMock       func(ctx context.Context, mockRoleRepo *MockRoleRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name:     "Success saving role",
+			roleName: "front-desk",
+			role:     &models.UpdateRole{Permissions: []string{"member:read", "member:write"}},
+			roleRepoMock: func(ctx context.Context, mockRoleRepo *MockRoleRepository) {
+				mockRoleRepo.On("SaveRole", ctx, &models.Role{Name: "front-desk", Permissions: []string{"member:read", "member:write"}}).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       &models.Role{Name: "front-desk", Permissions: []string{"member:read", "member:write"}},
+		},
+		{
+			name:               "Invalid name and unknown permission",
+			roleName:           "Front Desk",
+			role:               &models.UpdateRole{Permissions: []string{"member:read", "member:teleport"}},
+			roleRepoMock:       func(ctx context.Context, mockRoleRepo *MockRoleRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{
+				{Field: "name", Code: validation.CodeInvalid, Message: "Role names are lower case letters, digits and dashes"},
+				{Field: "permissions", Code: validation.CodeUnknownPermission, Message: "Unknown permission member:teleport"},
+			}},
+		},
+		{
+			name:     "Error saving role",
+			roleName: "front-desk",
+			role:     &models.UpdateRole{Permissions: []string{"member:read"}},
+			roleRepoMock: func(ctx context.Context, mockRoleRepo *MockRoleRepository) {
+				mockRoleRepo.On("SaveRole", ctx, mock.Anything).Return(errors.New("repository error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       models.ErrorMessage{Error: "Error saving role"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRoleRepo := new(MockRoleRepository)
+			tc.roleRepoMock(ctx, mockRoleRepo)
+
+			accessService := NewAccessService(mockRoleRepo, new(MockGrantRepository), logging.Discard())
+			response := accessService.SaveRole(ctx, tc.roleName, tc.role)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockRoleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteRoleByName(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		repoMock           func(ctx context.Context, mockRoleRepo *MockRoleRepository, mockGrantRepo *MockGrantRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name: "Success deleting role",
+			repoMock: func(ctx context.Context, mockRoleRepo *MockRoleRepository, mockGrantRepo *MockGrantRepository) {
+				mockGrantRepo.On("CountGrantsWithRole", ctx, "front-desk").Return(int64(0), nil)
+				mockRoleRepo.On("DeleteRoleByName", ctx, "front-desk").Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       models.SuccessMessage{Message: "Role front-desk deleted"},
+		},
+		{
+			name: "Role is still granted",
+			repoMock: func(ctx context.Context, mockRoleRepo *MockRoleRepository, mockGrantRepo *MockGrantRepository) {
+				mockGrantRepo.On("CountGrantsWithRole", ctx, "front-desk").Return(int64(2), nil)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       models.ErrorMessage{Error: "Role front-desk cannot be deleted while 2 grant(s) give it"},
+		},
+		{
+			name: "Role is not found",
+			repoMock: func(ctx context.Context, mockRoleRepo *MockRoleRepository, mockGrantRepo *MockGrantRepository) {
+				mockGrantRepo.On("CountGrantsWithRole", ctx, "front-desk").Return(int64(0), nil)
+				mockRoleRepo.On("DeleteRoleByName", ctx, "front-desk").Return(mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       models.ErrorMessage{Error: "Role front-desk not found"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRoleRepo := new(MockRoleRepository)
+			mockGrantRepo := new(MockGrantRepository)
+			tc.repoMock(ctx, mockRoleRepo, mockGrantRepo)
+
+			accessService := NewAccessService(mockRoleRepo, mockGrantRepo, logging.Discard())
+			response := accessService.DeleteRoleByName(ctx, "front-desk")
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockRoleRepo.AssertExpectations(t)
+			mockGrantRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSaveGrant(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		subject            string
+		grant              *models.UpdateGrant
+		repoMock           func(ctx context.Context, mockRoleRepo *MockRoleRepository, mockGrantRepo *MockGrantRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name:    "Success saving grant",
+			subject: "jwt:jane.smith",
+			grant:   &models.UpdateGrant{Roles: []string{"front-desk"}},
+			repoMock: func(ctx context.Context, mockRoleRepo *MockRoleRepository, mockGrantRepo *MockGrantRepository) {
+				mockRoleRepo.On("GetRoleByName", ctx, "front-desk").Return(&models.Role{Name: "front-desk"}, nil)
+				mockGrantRepo.On("SaveGrant", ctx, &models.Grant{Subject: "jwt:jane.smith", Roles: []string{"front-desk"}}).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       &models.Grant{Subject: "jwt:jane.smith", Roles: []string{"front-desk"}},
+		},
+		{
+			name:    "Unknown subject type and role",
+			subject: "jane.smith",
+			grant:   &models.UpdateGrant{Roles: []string{"auditor"}},
+			repoMock: func(ctx context.Context, mockRoleRepo *MockRoleRepository, mockGrantRepo *MockGrantRepository) {
+				mockRoleRepo.On("GetRoleByName", ctx, "auditor").Return(nil, mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{
+				{Field: "subject", Code: validation.CodeInvalid, Message: "Subject must start with api_key: or jwt:"},
+				{Field: "roles", Code: validation.CodeUnknownRole, Message: "Role auditor does not exist"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRoleRepo := new(MockRoleRepository)
+			mockGrantRepo := new(MockGrantRepository)
+			tc.repoMock(ctx, mockRoleRepo, mockGrantRepo)
+
+			accessService := NewAccessService(mockRoleRepo, mockGrantRepo, logging.Discard())
+			response := accessService.SaveGrant(ctx, tc.subject, tc.grant)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockRoleRepo.AssertExpectations(t)
+			mockGrantRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteGrantBySubject(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGrantRepo := new(MockGrantRepository)
+	mockGrantRepo.On("DeleteGrantBySubject", ctx, "jwt:jane.smith").Return(nil)
+	mockGrantRepo.On("DeleteGrantBySubject", ctx, "jwt:john.doe").Return(mongo.ErrNoDocuments)
+
+	accessService := NewAccessService(new(MockRoleRepository), mockGrantRepo, logging.Discard())
+
+	response := accessService.DeleteGrantBySubject(ctx, "jwt:jane.smith")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, models.SuccessMessage{Message: "Grant for jwt:jane.smith deleted"}, response.Body)
+
+	response = accessService.DeleteGrantBySubject(ctx, "jwt:john.doe")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, models.ErrorMessage{Error: "Grant for jwt:john.doe not found"}, response.Body)
+}
+
+func (m *MockRoleRepository) SaveRole(ctx context.Context, role *models.Role) error {
+	args := m.Called(ctx, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) GetRoleByName(ctx context.Context, name string) (*models.Role, error) {
+	args := m.Called(ctx, name)
+	role, ok := args.Get(0).(*models.Role)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return role, args.Error(1)
+}
+
+func (m *MockRoleRepository) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	args := m.Called(ctx)
+	roles, ok := args.Get(0).([]models.Role)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return roles, args.Error(1)
+}
+
+func (m *MockRoleRepository) DeleteRoleByName(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockGrantRepository) SaveGrant(ctx context.Context, grant *models.Grant) error {
+	args := m.Called(ctx, grant)
+	return args.Error(0)
+}
+
+func (m *MockGrantRepository) GetGrantBySubject(ctx context.Context, subject string) (*models.Grant, error) {
+	args := m.Called(ctx, subject)
+	grant, ok := args.Get(0).(*models.Grant)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return grant, args.Error(1)
+}
+
+func (m *MockGrantRepository) GetAllGrants(ctx context.Context) ([]models.Grant, error) {
+	args := m.Called(ctx)
+	grants, ok := args.Get(0).([]models.Grant)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return grants, args.Error(1)
+}
+
+func (m *MockGrantRepository) DeleteGrantBySubject(ctx context.Context, subject string) error {
+	args := m.Called(ctx, subject)
+	return args.Error(0)
+}
+
+func (m *MockGrantRepository) CountGrantsWithRole(ctx context.Context, role string) (int64, error) {
+	args := m.Called(ctx, role)
+	return args.Get(0).(int64), args.Error(1)
+}
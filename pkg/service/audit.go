@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/utils"
+)
+
+// AuditServiceI reads the audit log of member changes.
+type AuditServiceI interface {
+	GetMemberHistory(ctx context.Context, memberId int, query models.AuditQuery) models.Response
+	GetAuditEntries(ctx context.Context, query models.AuditQuery) models.Response
+}
+
+var auditActions = []string{
+	models.AuditCreate,
+	models.AuditUpdate,
+	models.AuditDelete,
+	models.AuditRestore,
+	models.AuditPurge,
+	models.AuditRenew,
+	models.AuditLapse,
+	models.AuditStatus,
+}
+
+type AuditService struct {
+	auditRepository repository.AuditRepositoryI
+	logger          *slog.Logger
+}
+
+func NewAuditService(auditRepository repository.AuditRepositoryI, logger *slog.Logger) AuditServiceI {
+	return &AuditService{
+		auditRepository: auditRepository,
+		logger:          logger,
+	}
+}
+
+// GetMemberHistory lists the audit entries of one member, newest first. The
+// history of deleted and purged members can still be read.
+func (a *AuditService) GetMemberHistory(ctx context.Context, memberId int, query models.AuditQuery) models.Response {
+	query.MemberId = memberId
+	return a.GetAuditEntries(ctx, query)
+}
+
+// GetAuditEntries lists the audit entries matching the query, newest first.
+func (a *AuditService) GetAuditEntries(ctx context.Context, query models.AuditQuery) models.Response {
+	if query.Limit < 0 || query.Limit > repository.MaxAuditPageSize {
+		return createErrorResponse(http.StatusBadRequest, fmt.Sprintf("Limit must be between 1 and %d", repository.MaxAuditPageSize))
+	}
+
+	if query.Action != "" && !slices.Contains(auditActions, query.Action) {
+		return createErrorResponse(http.StatusBadRequest, "Invalid action")
+	}
+
+	if (query.From != "" && !utils.IsValidDate(query.From)) ||
+		(query.To != "" && !utils.IsValidDate(query.To)) {
+		return createErrorResponse(http.StatusBadRequest, "Invalid date range")
+	}
+
+	entries, err := a.auditRepository.GetAuditEntries(ctx, query)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return createErrorResponse(http.StatusBadRequest, "Invalid cursor")
+	}
+	if err != nil {
+		return createInternalErrorResponse(ctx, a.logger, err, "Error fetching audit entries")
+	}
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       entries,
+	}
+}
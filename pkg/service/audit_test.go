@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"members.com/membership/pkg/auth"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/utils"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func TestMemberChangesAreAudited(t *testing.T) {
+	t.Parallel()
+
+	ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "jane.smith", Method: auth.MethodToken})
+	auditRepository := repository.NewMemoryAuditRepository()
+	memberService := NewMemberService(repository.NewMemoryMemberRepository(), new(MockPlanRepository), auditRepository,
+		repository.NewMemoryIdAllocator(repository.MemberIdSequence), testClock, logging.Discard())
+
+	created := memberService.CreateMember(ctx, &models.Member{FirstName: "John", LastName: "Doe", Email: "John.Doe@gmail.com", DateOfBirth: "1990-01-01"})
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+	memberId := created.Body.(*models.Member).ID
+	updated := memberService.UpdateMemberById(ctx, &models.UpdateMember{FirstName: "John", LastName: "Doe", Email: "john.doe@tennis.com", DateOfBirth: "1990-01-01"}, memberId, AnyVersion)
+	require.Equal(t, http.StatusOK, updated.StatusCode)
+	activated := memberService.ChangeMemberStatus(ctx, memberId, models.TransitionActivate, "Paid", AnyVersion)
+	require.Equal(t, http.StatusOK, activated.StatusCode)
+	deleted := memberService.DeleteMemberById(context.Background(), memberId, AnyVersion)
+	require.Equal(t, http.StatusOK, deleted.StatusCode)
+	failed := memberService.UpdateMemberById(ctx, &models.UpdateMember{FirstName: "John"}, memberId, AnyVersion)
+	require.Equal(t, http.StatusBadRequest, failed.StatusCode)
+
+	page, err := auditRepository.GetAuditEntries(context.Background(), models.AuditQuery{MemberId: memberId})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 4)
+
+	expected := []struct {
+		actor   string
+		action  string
+		changes string
+	}{
+		{
+			actor:   "system",
+			action:  models.AuditDelete,
+			changes: `[{"field":"deletedAt","after":"2024-06-01T09:30:00Z"}]`,
+		},
+		{
+			actor:   "jwt:jane.smith",
+			action:  models.AuditStatus,
+			changes: `[{"field":"status","before":"pending","after":"active"}]`,
+		},
+		{
+			actor:   "jwt:jane.smith",
+			action:  models.AuditUpdate,
+			changes: `[{"field":"email","before":"john.doe@gmail.com","after":"john.doe@tennis.com"}]`,
+		},
+		{
+			actor:  "jwt:jane.smith",
+			action: models.AuditCreate,
+			changes: `[{"field":"firstName","after":"John"},{"field":"lastName","after":"Doe"},{"field":"email","after":"john.doe@gmail.com"},` +
+				`{"field":"dateOfBirth","after":"1990-01-01"},{"field":"status","after":"pending"}]`,
+		},
+	}
+	for i, entry := range page.Items {
+		assert.Equal(t, expected[i].actor, entry.Actor)
+		assert.Equal(t, expected[i].action, entry.Action)
+		assert.Equal(t, memberId, entry.MemberId)
+		assert.Equal(t, "2024-06-01T09:30:00Z", entry.Timestamp)
+		changes, err := json.Marshal(entry.Changes)
+		require.NoError(t, err)
+		assert.JSONEq(t, expected[i].changes, string(changes))
+	}
+}
+
+func TestMemberRestoresAndPurgesAreAudited(t *testing.T) {
+	t.Parallel()
+
+	ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "jane.smith", Method: auth.MethodToken})
+	memberRepository := repository.NewMemoryMemberRepository()
+	auditRepository := repository.NewMemoryAuditRepository()
+	memberService := NewMemberService(memberRepository, new(MockPlanRepository), auditRepository,
+		repository.NewMemoryIdAllocator(repository.MemberIdSequence), testClock, logging.Discard())
+	purgeClock := utils.FixedClock{Time: testClock.Now().AddDate(0, 0, 31)}
+	adminService := NewMemberAdminService(memberRepository, memberService, NewMemberAuditor(auditRepository, purgeClock, logging.Discard()),
+		purgeClock, 30*24*time.Hour, logging.Discard())
+
+	created := memberService.CreateMember(ctx, &models.Member{FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com", DateOfBirth: "1990-01-01"})
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+	memberId := created.Body.(*models.Member).ID
+	require.Equal(t, http.StatusOK, memberService.DeleteMemberById(ctx, memberId, AnyVersion).StatusCode)
+	require.Equal(t, http.StatusOK, adminService.RestoreMemberById(ctx, memberId).StatusCode)
+	require.Equal(t, http.StatusOK, memberService.DeleteMemberById(ctx, memberId, AnyVersion).StatusCode)
+	require.Equal(t, http.StatusOK, adminService.PurgeDeletedMembers(ctx).StatusCode)
+
+	page, err := auditRepository.GetAuditEntries(context.Background(), models.AuditQuery{MemberId: memberId, Action: models.AuditPurge})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "jwt:jane.smith", page.Items[0].Actor)
+	assert.Equal(t, "2024-07-02T09:30:00Z", page.Items[0].Timestamp)
+	assert.Empty(t, page.Items[0].Changes)
+
+	page, err = auditRepository.GetAuditEntries(context.Background(), models.AuditQuery{MemberId: memberId, Action: models.AuditRestore})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "jwt:jane.smith", page.Items[0].Actor)
+	changes, err := json.Marshal(page.Items[0].Changes)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"field":"deletedAt","before":"2024-06-01T09:30:00Z"}]`, string(changes))
+}
+
+func TestMemberChangeStandsWhenAuditFails(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockRepo := new(MockMemberRepository)
+	mockRepo.On("CreateMember", ctx, mock.Anything).Return(nil)
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.Anything).Return(errors.New("audit log unavailable"))
+
+	memberService := NewMemberService(mockRepo, new(MockPlanRepository), mockAuditRepo, repository.NewMemoryIdAllocator(repository.MemberIdSequence), testClock, logging.Discard())
+	response := memberService.CreateMember(ctx, &models.Member{FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com", DateOfBirth: "1990-01-01"})
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	mockRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestGetAuditEntries(t *testing.T) {
+	t.Parallel()
+
+	entries := &models.AuditPage{
+		Items: []models.AuditEntry{{ID: "665ae9b8c3a5f1d2e4b6a7c8", Actor: "jwt:jane.smith", Timestamp: "2024-06-01T09:30:00Z", Action: models.AuditDelete, MemberId: 100001}},
+		Total: 1,
+	}
+
+	testCases := []struct {
+		name               string
+		query              models.AuditQuery
+		auditRepoMock      func(ctx context.Context, mockAuditRepo *MockAuditRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name:  "Success fetching audit entries",
+			query: models.AuditQuery{Actor: "jwt:jane.smith", Action: models.AuditDelete, From: "2024-06-01", To: "2024-06-30"},
+			auditRepoMock: func(ctx context.Context, mockAuditRepo *MockAuditRepository) {
+				mockAuditRepo.On("GetAuditEntries", ctx, models.AuditQuery{Actor: "jwt:jane.smith", Action: models.AuditDelete, From: "2024-06-01", To: "2024-06-30"}).Return(entries, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       entries,
+		},
+		{
+			name:               "Limit out of range",
+			query:              models.AuditQuery{Limit: 101},
+			auditRepoMock:      func(ctx context.Context, mockAuditRepo *MockAuditRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Limit must be between 1 and 100"},
+		},
+		{
+			name:               "Unknown action",
+			query:              models.AuditQuery{Action: "read"},
+			auditRepoMock:      func(ctx context.Context, mockAuditRepo *MockAuditRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Invalid action"},
+		},
+		{
+			name:               "Invalid date range",
+			query:              models.AuditQuery{From: "01/06/2024"},
+			auditRepoMock:      func(ctx context.Context, mockAuditRepo *MockAuditRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Invalid date range"},
+		},
+		{
+			name:  "Invalid cursor",
+			query: models.AuditQuery{Cursor: "garbage"},
+			auditRepoMock: func(ctx context.Context, mockAuditRepo *MockAuditRepository) {
+				mockAuditRepo.On("GetAuditEntries", ctx, models.AuditQuery{Cursor: "garbage"}).Return(nil, repository.ErrInvalidCursor)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Invalid cursor"},
+		},
+		{
+			name:  "Error fetching audit entries",
+			query: models.AuditQuery{},
+			auditRepoMock: func(ctx context.Context, mockAuditRepo *MockAuditRepository) {
+				mockAuditRepo.On("GetAuditEntries", ctx, models.AuditQuery{}).Return(nil, errors.New("database unavailable"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       models.ErrorMessage{Error: "Error fetching audit entries"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockAuditRepo := new(MockAuditRepository)
+			tc.auditRepoMock(ctx, mockAuditRepo)
+
+			auditService := NewAuditService(mockAuditRepo, logging.Discard())
+			response := auditService.GetAuditEntries(ctx, tc.query)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockAuditRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetMemberHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	history := &models.AuditPage{Items: []models.AuditEntry{}}
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("GetAuditEntries", ctx, models.AuditQuery{Limit: 10, MemberId: 100001}).Return(history, nil)
+
+	auditService := NewAuditService(mockAuditRepo, logging.Discard())
+	response := auditService.GetMemberHistory(ctx, 100001, models.AuditQuery{Limit: 10, MemberId: 100002})
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, history, response.Body)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func (m *MockAuditRepository) AppendAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) GetAuditEntries(ctx context.Context, query models.AuditQuery) (*models.AuditPage, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuditPage), args.Error(1)
+}
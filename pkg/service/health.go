@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/utils"
+)
+
+// healthCheckTimeout bounds each dependency check, so a hung dependency
+// reports down rather than hanging the readiness probe.
+const healthCheckTimeout = 2 * time.Second
+
+// HealthServiceI reports whether the application is alive and whether it is
+// ready to serve requests.
+type HealthServiceI interface {
+	Liveness(ctx context.Context) models.Response
+	Readiness(ctx context.Context) models.Response
+	// Drain makes the application report not ready from now on, so that load
+	// balancers stop sending it requests while it shuts down.
+	Drain()
+}
+
+// HealthCheck checks a dependency, such as the database, that the
+// application cannot serve requests without.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type HealthService struct {
+	checks   []HealthCheck
+	clock    utils.Clock
+	logger   *slog.Logger
+	draining atomic.Bool
+}
+
+func NewHealthService(clock utils.Clock, logger *slog.Logger, checks ...HealthCheck) HealthServiceI {
+	return &HealthService{
+		checks: checks,
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+// Liveness reports the application as alive whenever it can answer at all.
+func (h *HealthService) Liveness(ctx context.Context) models.Response {
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       models.HealthReport{Status: models.HealthOk},
+	}
+}
+
+// Readiness checks every dependency and reports the application ready if all
+// of them are up and it is not draining.
+func (h *HealthService) Readiness(ctx context.Context) models.Response {
+	report := models.HealthReport{Status: models.HealthOk, Checks: map[string]models.DependencyHealth{}}
+	for _, check := range h.checks {
+		dependency := h.runCheck(ctx, check)
+		if dependency.Status != models.DependencyUp {
+			report.Status = models.HealthUnavailable
+		}
+		report.Checks[check.Name] = dependency
+	}
+	if h.draining.Load() {
+		report.Status = models.HealthDraining
+	}
+
+	statusCode := http.StatusOK
+	if report.Status != models.HealthOk {
+		statusCode = http.StatusServiceUnavailable
+	}
+	return models.Response{
+		StatusCode: statusCode,
+		Body:       report,
+	}
+}
+
+func (h *HealthService) runCheck(ctx context.Context, check HealthCheck) models.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := h.clock.Now()
+	err := check.Check(ctx)
+	latency := h.clock.Now().Sub(start)
+
+	dependency := models.DependencyHealth{
+		Status:    models.DependencyUp,
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+	}
+	if err != nil {
+		h.logger.WarnContext(ctx, "health check failed", "check", check.Name, "error", err)
+		dependency.Status = models.DependencyDown
+	}
+	return dependency
+}
+
+func (h *HealthService) Drain() {
+	h.draining.Store(true)
+}
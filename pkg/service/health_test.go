@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+)
+
+// steppingClock moves on by step each time it is read, so checks take a
+// predictable time.
+type steppingClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (s *steppingClock) Now() time.Time {
+	now := s.now
+	s.now = s.now.Add(s.step)
+	return now
+}
+
+func upCheck(name string) HealthCheck {
+	return HealthCheck{Name: name, Check: func(ctx context.Context) error { return nil }}
+}
+
+func TestLiveness(t *testing.T) {
+	t.Parallel()
+
+	healthService := NewHealthService(testClock, logging.Discard(), HealthCheck{Name: "mongo", Check: func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}})
+
+	response := healthService.Liveness(context.Background())
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, models.HealthReport{Status: models.HealthOk}, response.Body)
+}
+
+func TestReadiness(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		checks             []HealthCheck
+		drain              bool
+		expectedStatusCode int
+		expectedReport     models.HealthReport
+	}{
+		{
+			name:               "Ready without dependencies",
+			expectedStatusCode: http.StatusOK,
+			expectedReport:     models.HealthReport{Status: models.HealthOk, Checks: map[string]models.DependencyHealth{}},
+		},
+		{
+			name:               "Ready when every dependency is up",
+			checks:             []HealthCheck{upCheck("mongo")},
+			expectedStatusCode: http.StatusOK,
+			expectedReport: models.HealthReport{Status: models.HealthOk, Checks: map[string]models.DependencyHealth{
+				"mongo": {Status: models.DependencyUp, LatencyMs: 1.5},
+			}},
+		},
+		{
+			name: "Unavailable when a dependency is down",
+			checks: []HealthCheck{upCheck("cache"), {Name: "mongo", Check: func(ctx context.Context) error {
+				return errors.New("connection refused")
+			}}},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedReport: models.HealthReport{Status: models.HealthUnavailable, Checks: map[string]models.DependencyHealth{
+				"cache": {Status: models.DependencyUp, LatencyMs: 1.5},
+				"mongo": {Status: models.DependencyDown, LatencyMs: 1.5},
+			}},
+		},
+		{
+			name:               "Not ready while draining",
+			checks:             []HealthCheck{upCheck("mongo")},
+			drain:              true,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedReport: models.HealthReport{Status: models.HealthDraining, Checks: map[string]models.DependencyHealth{
+				"mongo": {Status: models.DependencyUp, LatencyMs: 1.5},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := &steppingClock{now: testClock.Time, step: 1500 * time.Microsecond}
+			healthService := NewHealthService(clock, logging.Discard(), tc.checks...)
+			if tc.drain {
+				healthService.Drain()
+			}
+
+			response := healthService.Readiness(context.Background())
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedReport, response.Body)
+		})
+	}
+}
+
+func TestReadinessBoundsSlowChecks(t *testing.T) {
+	t.Parallel()
+
+	healthService := NewHealthService(testClock, logging.Discard(), HealthCheck{Name: "mongo", Check: func(ctx context.Context) error {
+		_, hasDeadline := ctx.Deadline()
+		require.True(t, hasDeadline)
+		return nil
+	}})
+
+	response := healthService.Readiness(context.Background())
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
@@ -2,48 +2,82 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"members.com/membership/pkg/models"
 	"members.com/membership/pkg/repository"
 	"members.com/membership/pkg/utils"
+	"members.com/membership/pkg/validation"
 )
 
 type MemberServiceI interface {
 	CreateMember(ctx context.Context, member *models.Member) models.Response
 	GetMemberById(ctx context.Context, memberId int) models.Response
-	GetAllMembers(ctx context.Context) models.Response
-	UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int) models.Response
-	DeleteMemberById(ctx context.Context, memberId int) models.Response
+	GetAllMembers(ctx context.Context, query models.MemberQuery) models.Response
+	SearchMembers(ctx context.Context, query models.MemberSearchQuery) models.Response
+	UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int, expectedVersion int) models.Response
+	PatchMemberById(ctx context.Context, patch models.MemberPatch, memberId int, expectedVersion int) models.Response
+	DeleteMemberById(ctx context.Context, memberId int, expectedVersion int) models.Response
+	RenewMemberById(ctx context.Context, memberId int, expectedVersion int) models.Response
+	ChangeMemberStatus(ctx context.Context, memberId int, transition string, reason string, expectedVersion int) models.Response
+	ImportMembers(ctx context.Context, memberImport *models.MemberImport) models.Response
+	ExportMembers(ctx context.Context, query models.MemberExportQuery) models.Response
 }
 
+// AnyVersion can be passed as the expected version when the caller did not
+// send an If-Match precondition. It is not 0, which is the version of members
+// stored before versions existed.
+const AnyVersion = -1
+
+// An allocated ID can still clash with a member created before IDs were
+// allocated sequentially, so creation retries with a fresh ID a few times.
+const maxIdAllocationAttempts = 5
+
+// Memberships expiring within this many days are reported as expiring soon.
+const expiringSoonDays = 30
+
 type MemberService struct {
 	memberRepository repository.MemberRepositoryI
+	planRepository   repository.PlanRepositoryI
+	auditor          *MemberAuditor
+	idAllocator      repository.IdAllocatorI
+	clock            utils.Clock
+	logger           *slog.Logger
 }
 
-func NewMemberService(memberRepository repository.MemberRepositoryI) MemberServiceI {
+// NewMemberService returns a service that records every change it makes to a
+// member in the audit log.
+func NewMemberService(memberRepository repository.MemberRepositoryI, planRepository repository.PlanRepositoryI, auditRepository repository.AuditRepositoryI, idAllocator repository.IdAllocatorI, clock utils.Clock, logger *slog.Logger) MemberServiceI {
 	return &MemberService{
 		memberRepository: memberRepository,
+		planRepository:   planRepository,
+		auditor:          NewMemberAuditor(auditRepository, clock, logger),
+		idAllocator:      idAllocator,
+		clock:            clock,
+		logger:           logger,
 	}
 }
 
 func (m *MemberService) CreateMember(ctx context.Context, member *models.Member) models.Response {
-	member.ID = utils.GenerateRandomNumber()
-
-	if !utils.IsValidEmail(member.Email) {
-		return createErrorResponse(http.StatusBadRequest, "Invalid email")
+	if response := m.prepareNewMember(ctx, member); response != nil {
+		return *response
 	}
 
-	if !utils.IsValidDate(member.DateOfBirth) {
-		return createErrorResponse(http.StatusBadRequest, "Invalid date of birth")
+	err := m.createMemberWithNewId(ctx, member)
+	if errors.Is(err, repository.ErrDuplicateEmail) {
+		return createEmailConflictResponse(member.Email)
 	}
-
-	err := m.memberRepository.CreateMember(ctx, member)
 	if err != nil {
-		return createErrorResponse(http.StatusInternalServerError, "Error creating member")
+		return createInternalErrorResponse(ctx, m.logger, err, "Error creating member")
 	}
+	m.auditor.RecordChange(ctx, models.AuditCreate, nil, member)
+	m.setDerivedFields(member)
 	return models.Response{
 		StatusCode: http.StatusCreated,
 		Body:       member,
@@ -53,18 +87,33 @@ func (m *MemberService) CreateMember(ctx context.Context, member *models.Member)
 func (m *MemberService) GetMemberById(ctx context.Context, memberId int) models.Response {
 	member, err := m.memberRepository.GetMemberById(ctx, memberId)
 	if err != nil {
-		return handleMemberFetchError(err, memberId)
+		return m.handleMemberFetchError(ctx, err, memberId)
 	}
+	m.setDerivedFields(member)
 	return models.Response{
 		StatusCode: http.StatusOK,
 		Body:       member,
 	}
 }
 
-func (m *MemberService) GetAllMembers(ctx context.Context) models.Response {
-	members, err := m.memberRepository.GetAllMembers(ctx)
+func (m *MemberService) GetAllMembers(ctx context.Context, query models.MemberQuery) models.Response {
+	if query.Limit < 0 || query.Limit > repository.MaxMemberPageSize {
+		return createErrorResponse(http.StatusBadRequest, fmt.Sprintf("Limit must be between 1 and %d", repository.MaxMemberPageSize))
+	}
+
+	if response := validateMemberQuery(query); response != nil {
+		return *response
+	}
+
+	members, err := m.memberRepository.GetAllMembers(ctx, query)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return createErrorResponse(http.StatusBadRequest, "Invalid cursor")
+	}
 	if err != nil {
-		return createErrorResponse(http.StatusInternalServerError, "Error fetching members")
+		return createInternalErrorResponse(ctx, m.logger, err, "Error fetching members")
+	}
+	for i := range members.Items {
+		m.setDerivedFields(&members.Items[i])
 	}
 	return models.Response{
 		StatusCode: http.StatusOK,
@@ -72,88 +121,374 @@ func (m *MemberService) GetAllMembers(ctx context.Context) models.Response {
 	}
 }
 
-func (m *MemberService) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int) models.Response {
-	if member.Email != "" && !utils.IsValidEmail(member.Email) {
-		return createErrorResponse(http.StatusBadRequest, "Invalid email")
+// SearchMembers finds members by name or email address, most relevant first.
+func (m *MemberService) SearchMembers(ctx context.Context, query models.MemberSearchQuery) models.Response {
+	query.Q = strings.TrimSpace(query.Q)
+	if query.Q == "" {
+		return createErrorResponse(http.StatusBadRequest, "Search query is required")
+	}
+
+	if query.Limit < 0 || query.Limit > repository.MaxMemberPageSize {
+		return createErrorResponse(http.StatusBadRequest, fmt.Sprintf("Limit must be between 1 and %d", repository.MaxMemberPageSize))
+	}
+
+	members, err := m.memberRepository.SearchMembers(ctx, query)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return createErrorResponse(http.StatusBadRequest, "Invalid cursor")
+	}
+	if err != nil {
+		return createInternalErrorResponse(ctx, m.logger, err, "Error searching members")
+	}
+	for i := range members.Items {
+		m.setDerivedFields(&members.Items[i])
+	}
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       members,
 	}
+}
 
-	if member.DateOfBirth != "" && !utils.IsValidDate(member.DateOfBirth) {
-		return createErrorResponse(http.StatusBadRequest, "Invalid date of birth")
+// UpdateMemberById replaces all editable fields of the member.
+func (m *MemberService) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int, expectedVersion int) models.Response {
+	member.Email = utils.NormalizeEmail(member.Email)
+	if fieldErrors := validation.ValidateMember(member); fieldErrors != nil {
+		return createValidationErrorResponse(fieldErrors)
 	}
 
 	fetchedMember, err := m.memberRepository.GetMemberById(ctx, memberId)
 	if err != nil {
-		return handleMemberFetchError(err, memberId)
+		return m.handleMemberFetchError(ctx, err, memberId)
+	}
+	if !versionMatches(fetchedMember, expectedVersion) {
+		return createVersionMismatchResponse(memberId)
 	}
 
-	fetchedMember = mergeUpdateMemberFieldsToMemberFields(fetchedMember, member)
+	return m.replaceMemberFields(ctx, fetchedMember, member)
+}
 
-	err = m.memberRepository.UpdateMemberById(ctx, mergeMemberFieldsToUpdateMemberFields(fetchedMember, member), memberId)
+// PatchMemberById applies a JSON Merge Patch or JSON Patch document to the
+// editable fields of the member. The patched member must pass the same
+// validation as a new member.
+func (m *MemberService) PatchMemberById(ctx context.Context, patch models.MemberPatch, memberId int, expectedVersion int) models.Response {
+	fetchedMember, err := m.memberRepository.GetMemberById(ctx, memberId)
 	if err != nil {
-		return createErrorResponse(http.StatusInternalServerError, "Error updating member")
+		return m.handleMemberFetchError(ctx, err, memberId)
+	}
+	if !versionMatches(fetchedMember, expectedVersion) {
+		return createVersionMismatchResponse(memberId)
 	}
+
+	member, err := applyMemberPatch(fetchedMember, patch)
+	if errors.Is(err, errUnsupportedPatch) {
+		return createErrorResponse(http.StatusUnsupportedMediaType, fmt.Sprintf("Patch must be %s or %s", models.MergePatchContentType, models.JsonPatchContentType))
+	}
+	if errors.Is(err, errInvalidPatch) {
+		return createErrorResponse(http.StatusBadRequest, "Invalid patch document")
+	}
+	if err != nil {
+		return createErrorResponse(http.StatusUnprocessableEntity, "Patch could not be applied to member")
+	}
+
+	member.Email = utils.NormalizeEmail(member.Email)
+	if fieldErrors := validation.ValidateMember(member); fieldErrors != nil {
+		return createValidationErrorResponse(fieldErrors)
+	}
+
+	return m.replaceMemberFields(ctx, fetchedMember, member)
+}
+
+// replaceMemberFields writes member over fetchedMember, provided nobody has
+// changed it since it was fetched.
+func (m *MemberService) replaceMemberFields(ctx context.Context, fetchedMember *models.Member, member *models.UpdateMember) models.Response {
+	if response := m.assignPlan(ctx, member, fetchedMember); response != nil {
+		return *response
+	}
+
+	memberId := fetchedMember.ID
+	err := m.memberRepository.UpdateMemberById(ctx, member, memberId, fetchedMember.Version)
+	if errors.Is(err, repository.ErrDuplicateEmail) {
+		return createEmailConflictResponse(member.Email)
+	}
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return createVersionMismatchResponse(memberId)
+	}
+	if err != nil {
+		return m.handleMemberWriteError(ctx, err, memberId, "Error updating member")
+	}
+
+	before := *fetchedMember
+	fetchedMember.FirstName = member.FirstName
+	fetchedMember.LastName = member.LastName
+	fetchedMember.Email = member.Email
+	fetchedMember.DateOfBirth = member.DateOfBirth
+	fetchedMember.PlanId = member.PlanId
+	fetchedMember.StartDate = member.StartDate
+	fetchedMember.ExpiryDate = member.ExpiryDate
+	fetchedMember.LapsedOn = member.LapsedOn
+	fetchedMember.Version++
+	m.auditor.RecordChange(ctx, models.AuditUpdate, &before, fetchedMember)
+	m.setDerivedFields(fetchedMember)
 	return models.Response{
 		StatusCode: http.StatusOK,
 		Body:       fetchedMember,
 	}
 }
 
-func (m *MemberService) DeleteMemberById(ctx context.Context, memberId int) models.Response {
-	_, err := m.memberRepository.GetMemberById(ctx, memberId)
+// DeleteMemberById soft deletes the member. It can be restored through the
+// admin endpoints until it is purged.
+func (m *MemberService) DeleteMemberById(ctx context.Context, memberId int, expectedVersion int) models.Response {
+	fetchedMember, err := m.memberRepository.GetMemberById(ctx, memberId)
 	if err != nil {
-		return handleMemberFetchError(err, memberId)
+		return m.handleMemberFetchError(ctx, err, memberId)
+	}
+	if !versionMatches(fetchedMember, expectedVersion) {
+		return createVersionMismatchResponse(memberId)
 	}
 
-	err = m.memberRepository.DeleteMemberById(ctx, memberId)
+	deletedAt := utils.FormatTimestamp(m.clock.Now())
+	err = m.memberRepository.DeleteMemberById(ctx, memberId, fetchedMember.Version, deletedAt)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return createVersionMismatchResponse(memberId)
+	}
 	if err != nil {
-		return createErrorResponse(http.StatusInternalServerError, fmt.Sprintf("Could not delete Member %d", memberId))
+		return m.handleMemberWriteError(ctx, err, memberId, fmt.Sprintf("Could not delete Member %d", memberId))
 	}
+
+	deletedMember := *fetchedMember
+	deletedMember.DeletedAt = deletedAt
+	m.auditor.RecordChange(ctx, models.AuditDelete, fetchedMember, &deletedMember)
 	return createSuccessResponse(http.StatusOK, fmt.Sprintf("Member %d deleted", memberId))
 }
 
-func mergeUpdateMemberFieldsToMemberFields(member *models.Member, updateMember *models.UpdateMember) *models.Member {
-	if updateMember.FirstName != "" {
-		member.FirstName = updateMember.FirstName
+// RenewMemberById extends the membership by another term of the member's
+// plan. A membership that has not expired yet is extended from its expiry
+// date, a lapsed one from today.
+func (m *MemberService) RenewMemberById(ctx context.Context, memberId int, expectedVersion int) models.Response {
+	fetchedMember, err := m.memberRepository.GetMemberById(ctx, memberId)
+	if err != nil {
+		return m.handleMemberFetchError(ctx, err, memberId)
+	}
+	if !versionMatches(fetchedMember, expectedVersion) {
+		return createVersionMismatchResponse(memberId)
+	}
+	if fetchedMember.PlanId == 0 {
+		return createErrorResponse(http.StatusConflict, fmt.Sprintf("Member %d has no plan to renew", memberId))
+	}
+	if currentStatus := memberStatus(fetchedMember); currentStatus == models.MemberCancelled {
+		return createInvalidTransitionResponse(memberId, currentStatus, "renewed")
 	}
 
-	if updateMember.LastName != "" {
-		member.LastName = updateMember.LastName
+	plan, err := m.planRepository.GetPlanById(ctx, fetchedMember.PlanId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return createErrorResponse(http.StatusConflict, fmt.Sprintf("Plan %d no longer exists", fetchedMember.PlanId))
+	}
+	if err != nil {
+		return createInternalErrorResponse(ctx, m.logger, err, "Error fetching plan")
 	}
 
-	if updateMember.Email != "" {
-		member.Email = updateMember.Email
+	today := utils.FormatDate(m.clock.Now())
+	renewFrom := fetchedMember.ExpiryDate
+	if renewFrom < today {
+		renewFrom = today
+	}
+	renewFromDate, err := utils.ParseDate(renewFrom)
+	if err != nil {
+		return createInternalErrorResponse(ctx, m.logger, err, "Error renewing member")
+	}
+
+	renewal := models.Renewal{
+		RenewedOn:          today,
+		PlanId:             plan.ID,
+		PreviousExpiryDate: fetchedMember.ExpiryDate,
+		ExpiryDate:         utils.FormatDate(renewFromDate.AddDate(0, plan.DurationMonths, 0)),
+	}
+	err = m.memberRepository.RenewMember(ctx, memberId, fetchedMember.Version, renewal)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return createVersionMismatchResponse(memberId)
+	}
+	if err != nil {
+		return m.handleMemberWriteError(ctx, err, memberId, "Error renewing member")
+	}
+
+	before := *fetchedMember
+	fetchedMember.ExpiryDate = renewal.ExpiryDate
+	fetchedMember.LapsedOn = ""
+	fetchedMember.Renewals = append(fetchedMember.Renewals, renewal)
+	fetchedMember.Version++
+	m.auditor.RecordChange(ctx, models.AuditRenew, &before, fetchedMember)
+	m.setDerivedFields(fetchedMember)
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       fetchedMember,
+	}
+}
+
+// prepareNewMember validates a new member and sets the fields the service
+// decides, returning the error response if the member cannot be created.
+// Only the fields clients can set are kept, so that a new member cannot be
+// created already deleted, lapsed or further along its lifecycle.
+func (m *MemberService) prepareNewMember(ctx context.Context, member *models.Member) *models.Response {
+	updateMember := toUpdateMember(member)
+	updateMember.Email = utils.NormalizeEmail(updateMember.Email)
+	updateMember.ExpiryDate = ""
+	updateMember.LapsedOn = ""
+	if fieldErrors := validation.ValidateMember(updateMember); fieldErrors != nil {
+		response := createValidationErrorResponse(fieldErrors)
+		return &response
+	}
+	if response := m.assignPlan(ctx, updateMember, nil); response != nil {
+		return response
+	}
+	*member = models.Member{
+		FirstName:   updateMember.FirstName,
+		LastName:    updateMember.LastName,
+		Email:       updateMember.Email,
+		DateOfBirth: updateMember.DateOfBirth,
+		PlanId:      updateMember.PlanId,
+		StartDate:   updateMember.StartDate,
+		ExpiryDate:  updateMember.ExpiryDate,
+		LapsedOn:    updateMember.LapsedOn,
+		Status:      models.MemberPending,
+		Version:     1,
+	}
+	return nil
+}
+
+func (m *MemberService) createMemberWithNewId(ctx context.Context, member *models.Member) error {
+	var err error
+	for attempt := 0; attempt < maxIdAllocationAttempts; attempt++ {
+		member.ID, err = m.idAllocator.NextId(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = m.memberRepository.CreateMember(ctx, member)
+		if !errors.Is(err, repository.ErrDuplicateMemberId) {
+			return err
+		}
+	}
+	return err
+}
+
+// assignPlan checks the member's plan and works out when the membership
+// expires, starting today unless a start date is given. currentMember is nil
+// for new members. The expiry and lapse dates of a member whose plan and
+// start date are unchanged are kept, so renewals stick and changing a plan's
+// duration only affects members who join it afterwards.
+func (m *MemberService) assignPlan(ctx context.Context, member *models.UpdateMember, currentMember *models.Member) *models.Response {
+	if member.PlanId == 0 {
+		member.ExpiryDate = ""
+		member.LapsedOn = ""
+		return nil
+	}
+
+	if member.StartDate == "" {
+		member.StartDate = utils.FormatDate(m.clock.Now())
+	}
+	if currentMember != nil && currentMember.PlanId == member.PlanId && currentMember.StartDate == member.StartDate {
+		member.ExpiryDate = currentMember.ExpiryDate
+		member.LapsedOn = currentMember.LapsedOn
+		return nil
 	}
 
-	if updateMember.DateOfBirth != "" {
-		member.DateOfBirth = updateMember.DateOfBirth
+	startDate, err := utils.ParseDate(member.StartDate)
+	if err != nil {
+		response := createValidationErrorResponse([]models.FieldError{
+			{Field: "startDate", Code: validation.CodeInvalidDate, Message: "Invalid start date"},
+		})
+		return &response
+	}
+
+	plan, err := m.planRepository.GetPlanById(ctx, member.PlanId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		response := createValidationErrorResponse([]models.FieldError{
+			{Field: "planId", Code: validation.CodeUnknownPlan, Message: fmt.Sprintf("Plan %d does not exist", member.PlanId)},
+		})
+		return &response
 	}
-	return member
+	if err != nil {
+		response := createInternalErrorResponse(ctx, m.logger, err, "Error fetching plan")
+		return &response
+	}
+
+	member.ExpiryDate = utils.FormatDate(startDate.AddDate(0, plan.DurationMonths, 0))
+	member.LapsedOn = ""
+	return nil
 }
 
-func mergeMemberFieldsToUpdateMemberFields(member *models.Member, updateMember *models.UpdateMember) *models.UpdateMember {
-	if updateMember.FirstName == "" {
-		updateMember.FirstName = member.FirstName
+// validateMemberQuery checks the sort order and filters of a query, returning
+// the error response if they are invalid.
+func validateMemberQuery(query models.MemberQuery) *models.Response {
+	var response models.Response
+	switch {
+	case query.SortBy != "" && !repository.IsMemberSortField(query.SortBy):
+		response = createErrorResponse(http.StatusBadRequest, "Invalid sort field")
+	case query.SortOrder != "" && query.SortOrder != repository.SortAscending && query.SortOrder != repository.SortDescending:
+		response = createErrorResponse(http.StatusBadRequest, "Invalid sort order")
+	case (query.DateOfBirthFrom != "" && !utils.IsValidDate(query.DateOfBirthFrom)) ||
+		(query.DateOfBirthTo != "" && !utils.IsValidDate(query.DateOfBirthTo)):
+		response = createErrorResponse(http.StatusBadRequest, "Invalid date of birth range")
+	default:
+		return nil
 	}
+	return &response
+}
 
-	if updateMember.LastName == "" {
-		updateMember.LastName = member.LastName
+// setDerivedFields fills in the fields of a member response that are not
+// stored as such.
+func (m *MemberService) setDerivedFields(member *models.Member) {
+	member.Status = memberStatus(member)
+	if member.LapsedOn != "" {
+		member.MembershipStatus = models.MembershipLapsed
+		return
 	}
+	member.MembershipStatus = membershipStatus(member.ExpiryDate, m.clock.Now())
+}
 
-	if updateMember.Email == "" {
-		updateMember.Email = member.Email
+// membershipStatus derives the status of a membership expiring on expiryDate.
+// Members without a plan have no expiry date and no status.
+func membershipStatus(expiryDate string, now time.Time) string {
+	if expiryDate == "" {
+		return ""
 	}
 
-	if updateMember.DateOfBirth == "" {
-		updateMember.DateOfBirth = member.DateOfBirth
+	// Dates are formatted as YYYY-MM-DD, so they compare as strings.
+	switch {
+	case expiryDate <= utils.FormatDate(now):
+		return models.MembershipLapsed
+	case expiryDate <= utils.FormatDate(now.AddDate(0, 0, expiringSoonDays)):
+		return models.MembershipExpiringSoon
+	default:
+		return models.MembershipActive
 	}
-	return updateMember
 }
 
-func handleMemberFetchError(err error, memberId int) models.Response {
+func (m *MemberService) handleMemberFetchError(ctx context.Context, err error, memberId int) models.Response {
 	if err == mongo.ErrNoDocuments {
 		return createErrorResponse(http.StatusNotFound, fmt.Sprintf("Member %d not found", memberId))
 	}
-	return createErrorResponse(http.StatusInternalServerError, "Error fetching member")
+	return createInternalErrorResponse(ctx, m.logger, err, "Error fetching member")
+}
+
+func versionMatches(member *models.Member, expectedVersion int) bool {
+	return expectedVersion == AnyVersion || member.Version == expectedVersion
+}
+
+// handleMemberWriteError reports a member that disappeared between being read
+// and written as not found, and anything else with errorMessage.
+func (m *MemberService) handleMemberWriteError(ctx context.Context, err error, memberId int, errorMessage string) models.Response {
+	if err == mongo.ErrNoDocuments {
+		return createErrorResponse(http.StatusNotFound, fmt.Sprintf("Member %d not found", memberId))
+	}
+	return createInternalErrorResponse(ctx, m.logger, err, errorMessage)
+}
+
+func createVersionMismatchResponse(memberId int) models.Response {
+	return createErrorResponse(http.StatusPreconditionFailed, fmt.Sprintf("Member %d has been modified, fetch it again and retry", memberId))
+}
+
+func createEmailConflictResponse(email string) models.Response {
+	return createErrorResponse(http.StatusConflict, fmt.Sprintf("Email %s is already registered to another member", email))
 }
 
 func createSuccessResponse(statusCode int, successMessage string) models.Response {
@@ -165,6 +500,23 @@ func createSuccessResponse(statusCode int, successMessage string) models.Respons
 	}
 }
 
+func createValidationErrorResponse(fieldErrors []models.FieldError) models.Response {
+	return models.Response{
+		StatusCode: http.StatusBadRequest,
+		Body: models.ErrorMessage{
+			Error:  validation.ErrorMessage,
+			Fields: fieldErrors,
+		},
+	}
+}
+
+// createInternalErrorResponse logs err, which the caller cannot do anything
+// about, and reports errorMessage with 500.
+func createInternalErrorResponse(ctx context.Context, logger *slog.Logger, err error, errorMessage string) models.Response {
+	logger.ErrorContext(ctx, errorMessage, "error", err)
+	return createErrorResponse(http.StatusInternalServerError, errorMessage)
+}
+
 func createErrorResponse(statusCode int, errorMessage string) models.Response {
 	return models.Response{
 		StatusCode: statusCode,
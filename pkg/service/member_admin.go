@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/utils"
+)
+
+// MemberAdminServiceI covers the administrative operations on soft deleted
+// members.
+type MemberAdminServiceI interface {
+	GetDeletedMembers(ctx context.Context, query models.MemberQuery) models.Response
+	RestoreMemberById(ctx context.Context, memberId int) models.Response
+	PurgeDeletedMembers(ctx context.Context) models.Response
+}
+
+type MemberAdminService struct {
+	memberRepository repository.MemberRepositoryI
+	memberService    MemberServiceI
+	auditor          *MemberAuditor
+	clock            utils.Clock
+	retention        time.Duration
+	logger           *slog.Logger
+}
+
+// NewMemberAdminService returns a service that purges members once they have
+// been deleted for longer than retention. Restores and purges are recorded
+// in the audit log.
+func NewMemberAdminService(memberRepository repository.MemberRepositoryI, memberService MemberServiceI, auditor *MemberAuditor, clock utils.Clock, retention time.Duration, logger *slog.Logger) MemberAdminServiceI {
+	return &MemberAdminService{
+		memberRepository: memberRepository,
+		memberService:    memberService,
+		auditor:          auditor,
+		clock:            clock,
+		retention:        retention,
+		logger:           logger,
+	}
+}
+
+// GetDeletedMembers lists soft deleted members, paged, sorted and filtered
+// like GetAllMembers.
+func (m *MemberAdminService) GetDeletedMembers(ctx context.Context, query models.MemberQuery) models.Response {
+	query.Deleted = true
+	return m.memberService.GetAllMembers(ctx, query)
+}
+
+func (m *MemberAdminService) RestoreMemberById(ctx context.Context, memberId int) models.Response {
+	deletedMember, err := m.memberRepository.RestoreMemberById(ctx, memberId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return createErrorResponse(http.StatusNotFound, fmt.Sprintf("Deleted member %d not found", memberId))
+	}
+	if err != nil {
+		return createInternalErrorResponse(ctx, m.logger, err, fmt.Sprintf("Could not restore Member %d", memberId))
+	}
+	restoredMember := *deletedMember
+	restoredMember.DeletedAt = ""
+	restoredMember.Version++
+	m.auditor.RecordChange(ctx, models.AuditRestore, deletedMember, &restoredMember)
+	return m.memberService.GetMemberById(ctx, memberId)
+}
+
+// PurgeDeletedMembers permanently removes members deleted longer ago than the
+// retention period.
+func (m *MemberAdminService) PurgeDeletedMembers(ctx context.Context) models.Response {
+	purged, err := m.memberRepository.PurgeDeletedMembers(ctx, utils.FormatTimestamp(m.clock.Now().Add(-m.retention)))
+	m.auditor.RecordPurges(ctx, purged)
+	if err != nil {
+		return createInternalErrorResponse(ctx, m.logger, err, "Error purging deleted members")
+	}
+	return createSuccessResponse(http.StatusOK, fmt.Sprintf("%d deleted members purged", len(purged)))
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+)
+
+func TestGetDeletedMembers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	deleted := &models.MemberPage{
+		Items: []models.Member{{ID: 1, FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com", DateOfBirth: "1990-01-01", DeletedAt: "2024-05-20T10:00:00Z", Version: 3}},
+		Total: 1,
+	}
+	mockRepo := new(MockMemberRepository)
+	mockRepo.On("GetAllMembers", ctx, models.MemberQuery{Limit: 20, Deleted: true}).Return(deleted, nil)
+
+	memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+	adminService := NewMemberAdminService(mockRepo, memberService, NewMemberAuditor(repository.NewMemoryAuditRepository(), testClock, logging.Discard()), testClock, 30*24*time.Hour, logging.Discard())
+	response := adminService.GetDeletedMembers(ctx, models.MemberQuery{Limit: 20})
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	page := response.Body.(*models.MemberPage)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, "2024-05-20T10:00:00Z", page.Items[0].DeletedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreMemberById(t *testing.T) {
+	t.Parallel()
+
+	memberId := 1
+	restored := &models.Member{
+		ID:          memberId,
+		FirstName:   "John",
+		LastName:    "Doe",
+		Email:       "john.doe@gmail.com",
+		DateOfBirth: "1990-01-01",
+		Status:      models.MemberActive,
+		Version:     4,
+	}
+	deleted := *restored
+	deleted.DeletedAt = "2024-05-20T10:00:00Z"
+	deleted.Version = 3
+
+	testCases := []struct {
+		name               string
+		memberRepoMock     func(ctx context.Context, mockRepo *MockMemberRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name: "Success restoring deleted member",
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("RestoreMemberById", ctx, memberId).Return(&deleted, nil)
+				mockRepo.On("GetMemberById", ctx, memberId).Return(restored, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       restored,
+		},
+		{
+			name: "Deleted member is not found",
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("RestoreMemberById", ctx, memberId).Return(nil, mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       models.ErrorMessage{Error: "Deleted member 1 not found"},
+		},
+		{
+			name: "Error restoring deleted member",
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("RestoreMemberById", ctx, memberId).Return(nil, errors.New("repository error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       models.ErrorMessage{Error: "Could not restore Member 1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockMemberRepository)
+			tc.memberRepoMock(ctx, mockRepo)
+
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			adminService := NewMemberAdminService(mockRepo, memberService, NewMemberAuditor(repository.NewMemoryAuditRepository(), testClock, logging.Discard()), testClock, 30*24*time.Hour, logging.Discard())
+			response := adminService.RestoreMemberById(ctx, memberId)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPurgeDeletedMembers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		memberRepoMock     func(ctx context.Context, mockRepo *MockMemberRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name: "Success purging members deleted before the retention period",
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("PurgeDeletedMembers", ctx, "2024-05-02T09:30:00Z").Return([]int{100001, 100002, 100003}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       models.SuccessMessage{Message: "3 deleted members purged"},
+		},
+		{
+			name: "Error purging deleted members",
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("PurgeDeletedMembers", ctx, "2024-05-02T09:30:00Z").Return([]int(nil), errors.New("repository error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       models.ErrorMessage{Error: "Error purging deleted members"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockMemberRepository)
+			tc.memberRepoMock(ctx, mockRepo)
+
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			adminService := NewMemberAdminService(mockRepo, memberService, NewMemberAuditor(repository.NewMemoryAuditRepository(), testClock, logging.Discard()), testClock, 30*24*time.Hour, logging.Discard())
+			response := adminService.PurgeDeletedMembers(ctx)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"members.com/membership/pkg/auth"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/utils"
+)
+
+// auditedMemberFields are the member fields compared for the audit log, by
+// their JSON names. The renewal and status histories are left out, they are
+// a record of changes themselves.
+var auditedMemberFields = []string{
+	"firstName",
+	"lastName",
+	"email",
+	"dateOfBirth",
+	"planId",
+	"startDate",
+	"expiryDate",
+	"lapsedOn",
+	"status",
+	"deletedAt",
+}
+
+// MemberAuditor appends entries to the audit log for changes to members. The
+// member service records its own changes, the admin service and background
+// jobs record theirs through one too.
+type MemberAuditor struct {
+	auditRepository repository.AuditRepositoryI
+	clock           utils.Clock
+	logger          *slog.Logger
+}
+
+func NewMemberAuditor(auditRepository repository.AuditRepositoryI, clock utils.Clock, logger *slog.Logger) *MemberAuditor {
+	return &MemberAuditor{
+		auditRepository: auditRepository,
+		clock:           clock,
+		logger:          logger,
+	}
+}
+
+// RecordChange appends an audit entry for a change that has already been
+// made, listing the audited fields that differ between before and after.
+// before is nil for new members. If the members cannot be compared, that is
+// logged and the entry is appended without changes, so the log still shows
+// who changed the member and when.
+func (a *MemberAuditor) RecordChange(ctx context.Context, action string, before *models.Member, after *models.Member) {
+	changes, err := memberChanges(before, after)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "error comparing member fields for the audit log", "action", action, "memberId", after.ID, "error", err)
+		changes = []models.FieldChange{}
+	}
+	a.RecordChanges(ctx, action, after.ID, changes)
+}
+
+// RecordChanges appends an audit entry with the given changes for a change
+// that has already been made. The change stands even if the entry cannot be
+// written, so that is only logged.
+func (a *MemberAuditor) RecordChanges(ctx context.Context, action string, memberId int, changes []models.FieldChange) {
+	entry := &models.AuditEntry{
+		Actor:     auditActor(ctx),
+		Timestamp: utils.FormatTimestamp(a.clock.Now()),
+		Action:    action,
+		MemberId:  memberId,
+		Changes:   changes,
+	}
+	// The entry is written even if the caller goes away now that the change
+	// has been made.
+	if err := a.auditRepository.AppendAuditEntry(context.WithoutCancel(ctx), entry); err != nil {
+		a.logger.ErrorContext(ctx, "error recording member change in the audit log", "action", action, "memberId", memberId, "error", err)
+	}
+}
+
+// RecordPurges appends an audit entry for each purged member. The entries
+// list no changes, so that the audit log does not keep the details of members
+// purged to be rid of them.
+func (a *MemberAuditor) RecordPurges(ctx context.Context, memberIds []int) {
+	for _, memberId := range memberIds {
+		a.RecordChanges(ctx, models.AuditPurge, memberId, []models.FieldChange{})
+	}
+}
+
+// RecordLapses appends an audit entry for each member lapsed on the date.
+func (a *MemberAuditor) RecordLapses(ctx context.Context, memberIds []int, lapsedOn string) {
+	for _, memberId := range memberIds {
+		a.RecordChanges(ctx, models.AuditLapse, memberId, []models.FieldChange{{Field: "lapsedOn", After: lapsedOn}})
+	}
+}
+
+func auditActor(ctx context.Context) string {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return principal.ID()
+	}
+	return models.AuditSystemActor
+}
+
+// memberChanges lists the audited fields that differ between before and
+// after.
+func memberChanges(before *models.Member, after *models.Member) ([]models.FieldChange, error) {
+	beforeFields, err := auditedFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditedFields(after)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]models.FieldChange, 0)
+	for _, field := range auditedMemberFields {
+		if beforeFields[field] != afterFields[field] {
+			changes = append(changes, models.FieldChange{
+				Field:  field,
+				Before: beforeFields[field],
+				After:  afterFields[field],
+			})
+		}
+	}
+	return changes, nil
+}
+
+// auditedFields returns the member's fields as they appear in JSON, leaving
+// out unset ones. Numbers are kept as json.Number so that they compare and
+// are stored exactly.
+func auditedFields(member *models.Member) (map[string]any, error) {
+	fields := make(map[string]any)
+	if member == nil {
+		return fields, nil
+	}
+
+	withStatus := *member
+	withStatus.Status = memberStatus(member)
+	data, err := json.Marshal(&withStatus)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/spreadsheet"
+	"members.com/membership/pkg/validation"
+)
+
+// memberExportColumn is a member field that can be exported, by its JSON
+// name. Empty optional fields are exported as nil.
+type memberExportColumn struct {
+	name  string
+	value func(member *models.Member) any
+}
+
+// memberExportColumns are exported in this order unless the query selects
+// other columns.
+var memberExportColumns = []memberExportColumn{
+	{"id", func(member *models.Member) any { return member.ID }},
+	{"firstName", func(member *models.Member) any { return member.FirstName }},
+	{"lastName", func(member *models.Member) any { return member.LastName }},
+	{"email", func(member *models.Member) any { return member.Email }},
+	{"dateOfBirth", func(member *models.Member) any { return member.DateOfBirth }},
+	{"status", func(member *models.Member) any { return member.Status }},
+	{"planId", func(member *models.Member) any { return optionalId(member.PlanId) }},
+	{"startDate", func(member *models.Member) any { return optional(member.StartDate) }},
+	{"expiryDate", func(member *models.Member) any { return optional(member.ExpiryDate) }},
+	{"membershipStatus", func(member *models.Member) any { return optional(member.MembershipStatus) }},
+	{"lapsedOn", func(member *models.Member) any { return optional(member.LapsedOn) }},
+	{"version", func(member *models.Member) any { return member.Version }},
+}
+
+// ExportMembers checks the export query and starts reading the matching
+// members. The export writes them as they are read, so exports do not hold
+// every member in memory. The export must be written, which releases the
+// members it reads.
+func (m *MemberService) ExportMembers(ctx context.Context, query models.MemberExportQuery) models.Response {
+	memberQuery := models.MemberQuery{
+		SortBy:          query.SortBy,
+		SortOrder:       query.SortOrder,
+		LastName:        query.LastName,
+		EmailDomain:     query.EmailDomain,
+		DateOfBirthFrom: query.DateOfBirthFrom,
+		DateOfBirthTo:   query.DateOfBirthTo,
+	}
+	if response := validateMemberQuery(memberQuery); response != nil {
+		return *response
+	}
+	if spreadsheet.ContentType(query.Format) == "" {
+		return createErrorResponse(http.StatusBadRequest, "Export format must be csv, ndjson or xlsx")
+	}
+	columns, fieldErrors := selectExportColumns(query.Columns)
+	if fieldErrors != nil {
+		return createValidationErrorResponse(fieldErrors)
+	}
+
+	members, err := m.memberRepository.StreamMembers(ctx, memberQuery)
+	if err != nil {
+		return createInternalErrorResponse(ctx, m.logger, err, "Error exporting members")
+	}
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	write := func(w io.Writer) error {
+		defer members.Close(ctx)
+		writer, err := spreadsheet.NewWriter(w, query.Format, header)
+		if err != nil {
+			return err
+		}
+		cells := make([]any, len(columns))
+		for err == nil && members.Next(ctx) {
+			member := members.Member()
+			m.setDerivedFields(member)
+			for i, column := range columns {
+				cells[i] = column.value(member)
+			}
+			err = writer.WriteRow(cells)
+		}
+		if err == nil {
+			err = members.Err()
+		}
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       &models.MemberExport{Format: query.Format, Write: write},
+	}
+}
+
+// selectExportColumns returns the columns named in the comma separated list,
+// or every column for an empty list.
+func selectExportColumns(names string) ([]memberExportColumn, []models.FieldError) {
+	if strings.TrimSpace(names) == "" {
+		return memberExportColumns, nil
+	}
+
+	var columns []memberExportColumn
+	var fieldErrors []models.FieldError
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		column, found := exportColumn(name)
+		if !found {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "columns", Code: validation.CodeInvalid, Message: fmt.Sprintf("%s cannot be exported", name)})
+			continue
+		}
+		columns = append(columns, column)
+	}
+	return columns, fieldErrors
+}
+
+func exportColumn(name string) (memberExportColumn, bool) {
+	for _, column := range memberExportColumns {
+		if column.name == name {
+			return column, true
+		}
+	}
+	return memberExportColumn{}, false
+}
+
+func optional(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func optionalId(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/validation"
+)
+
+func TestExportMembers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	memberRepo := repository.NewMemoryMemberRepository()
+	memberService := NewMemberService(memberRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(),
+		repository.NewMemoryIdAllocator(repository.MemberIdSequence), testClock, logging.Discard())
+	for _, member := range []*models.Member{
+		{FirstName: "Rafael", LastName: "Nadal", Email: "rafael.nadal@tennis.com", DateOfBirth: "1986-06-03"},
+		{FirstName: "Roger", LastName: "Federer", Email: "roger.federer@tennis.com", DateOfBirth: "1981-08-08"},
+		{FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com", DateOfBirth: "1990-01-01"},
+	} {
+		require.Equal(t, http.StatusCreated, memberService.CreateMember(ctx, member).StatusCode)
+	}
+
+	testCases := []struct {
+		name         string
+		query        models.MemberExportQuery
+		expectedFile string
+	}{
+		{
+			name:  "CSV of selected columns",
+			query: models.MemberExportQuery{EmailDomain: "tennis.com", SortBy: "lastName", Columns: "lastName, email,status", Format: "csv"},
+			expectedFile: "lastName,email,status\n" +
+				"Federer,roger.federer@tennis.com,pending\n" +
+				"Nadal,rafael.nadal@tennis.com,pending\n",
+		},
+		{
+			name:  "NDJSON of every column",
+			query: models.MemberExportQuery{LastName: "doe", Format: "ndjson"},
+			expectedFile: `{"id":100003,"firstName":"John","lastName":"Doe","email":"john.doe@gmail.com","dateOfBirth":"1990-01-01",` +
+				`"status":"pending","planId":null,"startDate":null,"expiryDate":null,"membershipStatus":null,"lapsedOn":null,"version":1}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := memberService.ExportMembers(ctx, tc.query)
+
+			require.Equal(t, http.StatusOK, response.StatusCode)
+			export := response.Body.(*models.MemberExport)
+			assert.Equal(t, tc.query.Format, export.Format)
+			var file bytes.Buffer
+			require.NoError(t, export.Write(&file))
+			assert.Equal(t, tc.expectedFile, file.String())
+		})
+	}
+}
+
+func TestExportMembersRejectsQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		query        models.MemberExportQuery
+		expectedBody any
+	}{
+		{
+			name:         "Invalid sort field",
+			query:        models.MemberExportQuery{SortBy: "planId", Format: "csv"},
+			expectedBody: models.ErrorMessage{Error: "Invalid sort field"},
+		},
+		{
+			name:         "Unsupported format",
+			query:        models.MemberExportQuery{Format: "pdf"},
+			expectedBody: models.ErrorMessage{Error: "Export format must be csv, ndjson or xlsx"},
+		},
+		{
+			name:  "Unknown column",
+			query: models.MemberExportQuery{Columns: "email,password", Format: "csv"},
+			expectedBody: models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{
+				{Field: "columns", Code: validation.CodeInvalid, Message: "password cannot be exported"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			memberService := NewMemberService(new(MockMemberRepository), new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.ExportMembers(context.Background(), tc.query)
+
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+		})
+	}
+}
+
+func TestExportMembersRepositoryError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockRepo := new(MockMemberRepository)
+	mockRepo.On("StreamMembers", ctx, models.MemberQuery{}).Return(nil, errors.New("repository error"))
+
+	memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+	response := memberService.ExportMembers(ctx, models.MemberExportQuery{Format: "csv"})
+
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	assert.Equal(t, models.ErrorMessage{Error: "Error exporting members"}, response.Body)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExportMembersStopsWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	memberService := NewMemberService(repository.NewMemoryMemberRepository(), new(MockPlanRepository), repository.NewMemoryAuditRepository(),
+		repository.NewMemoryIdAllocator(repository.MemberIdSequence), testClock, logging.Discard())
+	created := memberService.CreateMember(ctx, &models.Member{FirstName: "John", LastName: "Doe", Email: "john.doe@gmail.com", DateOfBirth: "1990-01-01"})
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+
+	response := memberService.ExportMembers(ctx, models.MemberExportQuery{Columns: "id", Format: "csv"})
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	cancel()
+
+	var file bytes.Buffer
+	err := response.Body.(*models.MemberExport).Write(&file)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "id\n", file.String())
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/validation"
+)
+
+// MaxImportRows is how many members one import can hold.
+const MaxImportRows = 10000
+
+// importFields are the member fields an import sets, by their JSON names.
+// The first four are required, like when creating a member.
+var importFields = []string{"firstName", "lastName", "email", "dateOfBirth", "planId", "startDate"}
+
+const requiredImportFields = 4
+
+// importRowError is reported for rows that failed for reasons other than
+// their fields, which importing them again may get past.
+const importRowError = "Error creating member, import the row again"
+
+// importedMember is a row that passed validation, waiting to be created.
+type importedMember struct {
+	member    models.Member
+	reportRow *models.ImportRow
+}
+
+// ImportMembers creates a member for every valid row of the import and
+// reports on each row. Rows are validated like members created one at a
+// time, and may not repeat an email address. All valid rows are written in
+// one bulk operation, on a dry run none are.
+func (m *MemberService) ImportMembers(ctx context.Context, memberImport *models.MemberImport) models.Response {
+	if len(memberImport.Rows) == 0 {
+		return createErrorResponse(http.StatusBadRequest, "Import file is empty")
+	}
+	if len(memberImport.Rows)-1 > MaxImportRows {
+		return createErrorResponse(http.StatusBadRequest, fmt.Sprintf("Import is limited to %d members", MaxImportRows))
+	}
+	columns, fieldErrors := importColumns(memberImport.Rows[0], memberImport.Columns)
+	if fieldErrors != nil {
+		return createValidationErrorResponse(fieldErrors)
+	}
+
+	report := &models.ImportReport{
+		DryRun: memberImport.DryRun,
+		Rows:   make([]models.ImportRow, 0, len(memberImport.Rows)-1),
+	}
+	valid := make([]importedMember, 0, len(memberImport.Rows)-1)
+	rowsByEmail := make(map[string]int)
+	for i, cells := range memberImport.Rows[1:] {
+		if isBlankRow(cells) {
+			continue
+		}
+		reportRow := models.ImportRow{Row: i + 2, Status: models.ImportRowFailed}
+
+		member, fieldErrors := memberFromRow(cells, columns)
+		if fieldErrors == nil {
+			if response := m.prepareNewMember(ctx, member); response != nil {
+				errorMessage, ok := response.Body.(models.ErrorMessage)
+				if response.StatusCode != http.StatusBadRequest || !ok {
+					return *response
+				}
+				fieldErrors = errorMessage.Fields
+			}
+		}
+		if fieldErrors == nil {
+			if firstRow, seen := rowsByEmail[member.Email]; seen {
+				fieldErrors = []models.FieldError{{Field: "email", Code: validation.CodeDuplicate, Message: fmt.Sprintf("Email %s is also in row %d", member.Email, firstRow)}}
+			} else {
+				rowsByEmail[member.Email] = reportRow.Row
+			}
+		}
+		reportRow.Errors = fieldErrors
+		report.Rows = append(report.Rows, reportRow)
+		if fieldErrors == nil {
+			valid = append(valid, importedMember{member: *member, reportRow: &report.Rows[len(report.Rows)-1]})
+		}
+	}
+
+	valid, err := m.withoutRegisteredEmails(ctx, valid)
+	if err != nil {
+		return createInternalErrorResponse(ctx, m.logger, err, "Error importing members")
+	}
+	if memberImport.DryRun {
+		for _, imported := range valid {
+			imported.reportRow.Status = models.ImportRowValid
+		}
+	} else if err := m.createImportedMembers(ctx, valid); err != nil {
+		return createInternalErrorResponse(ctx, m.logger, err, "Error importing members")
+	}
+
+	for _, row := range report.Rows {
+		if row.Status == models.ImportRowFailed {
+			report.Failed++
+		} else {
+			report.Imported++
+		}
+	}
+	report.Total = len(report.Rows)
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       report,
+	}
+}
+
+// withoutRegisteredEmails fails the rows whose email already belongs to a
+// member, so that a dry run reports them too.
+func (m *MemberService) withoutRegisteredEmails(ctx context.Context, valid []importedMember) ([]importedMember, error) {
+	if len(valid) == 0 {
+		return valid, nil
+	}
+	emails := make([]string, len(valid))
+	for i, imported := range valid {
+		emails[i] = imported.member.Email
+	}
+	registered, err := m.memberRepository.GetRegisteredEmails(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool, len(registered))
+	for _, email := range registered {
+		taken[email] = true
+	}
+	remaining := valid[:0]
+	for _, imported := range valid {
+		if taken[imported.member.Email] {
+			imported.reportRow.Errors = []models.FieldError{emailRegisteredError(imported.member.Email)}
+			continue
+		}
+		remaining = append(remaining, imported)
+	}
+	return remaining, nil
+}
+
+// createImportedMembers gives the members IDs and writes them at once. A
+// member registered by somebody else in the meantime only fails its row.
+// Members given an ID that is already taken, by a member with an ID from
+// before the counter, are given new IDs and written again.
+func (m *MemberService) createImportedMembers(ctx context.Context, valid []importedMember) error {
+	pending := valid
+	for attempt := 0; attempt < maxIdAllocationAttempts && len(pending) > 0; attempt++ {
+		ids, err := m.idAllocator.NextIds(ctx, len(pending))
+		if err != nil {
+			return err
+		}
+		members := make([]models.Member, len(pending))
+		for i := range pending {
+			members[i] = pending[i].member
+			members[i].ID = ids[i]
+		}
+
+		failed, err := m.memberRepository.CreateMembers(ctx, members)
+		if err != nil {
+			return err
+		}
+		var retry []importedMember
+		for i, imported := range pending {
+			err, isFailed := failed[i]
+			switch {
+			case !isFailed:
+				imported.reportRow.Status = models.ImportRowImported
+				imported.reportRow.MemberId = members[i].ID
+				m.auditor.RecordChange(ctx, models.AuditCreate, nil, &members[i])
+			case errors.Is(err, repository.ErrDuplicateEmail):
+				imported.reportRow.Errors = []models.FieldError{emailRegisteredError(members[i].Email)}
+			case errors.Is(err, repository.ErrDuplicateMemberId):
+				retry = append(retry, imported)
+			default:
+				imported.reportRow.Error = importRowError
+			}
+		}
+		pending = retry
+	}
+	for _, imported := range pending {
+		imported.reportRow.Error = importRowError
+	}
+	return nil
+}
+
+// importColumns finds the column of every import field in the header row.
+// Fields without a column have the index -1.
+func importColumns(header []string, columnNames map[string]string) (map[string]int, []models.FieldError) {
+	var fieldErrors []models.FieldError
+	for field := range columnNames {
+		if !isImportField(field) {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field, Code: validation.CodeInvalid, Message: fmt.Sprintf("%s cannot be imported", field)})
+		}
+	}
+
+	columns := make(map[string]int, len(importFields))
+	for i, field := range importFields {
+		name, mapped := columnNames[field]
+		if !mapped {
+			name = field
+		}
+		columns[field] = -1
+		for j, heading := range header {
+			if columnKey(heading) == columnKey(name) {
+				columns[field] = j
+				break
+			}
+		}
+
+		switch {
+		case columns[field] >= 0:
+		case mapped:
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field, Code: validation.CodeInvalid, Message: fmt.Sprintf("Column %s not found", name)})
+		case i < requiredImportFields:
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field, Code: validation.CodeRequired, Message: fmt.Sprintf("No column for %s", field)})
+		}
+	}
+	return columns, fieldErrors
+}
+
+func isImportField(field string) bool {
+	for _, importField := range importFields {
+		if importField == field {
+			return true
+		}
+	}
+	return false
+}
+
+// columnKey makes headers such as "First Name" and "first_name" match the
+// field firstName.
+func columnKey(heading string) string {
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, heading)
+}
+
+func memberFromRow(cells []string, columns map[string]int) (*models.Member, []models.FieldError) {
+	cell := func(field string) string {
+		column := columns[field]
+		if column < 0 || column >= len(cells) {
+			return ""
+		}
+		return strings.TrimSpace(cells[column])
+	}
+
+	member := &models.Member{
+		FirstName:   cell("firstName"),
+		LastName:    cell("lastName"),
+		Email:       cell("email"),
+		DateOfBirth: cell("dateOfBirth"),
+		StartDate:   cell("startDate"),
+	}
+	if planId := cell("planId"); planId != "" {
+		var err error
+		member.PlanId, err = strconv.Atoi(planId)
+		if err != nil {
+			return nil, []models.FieldError{validation.InvalidType("planId")}
+		}
+	}
+	return member, nil
+}
+
+func isBlankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func emailRegisteredError(email string) models.FieldError {
+	return models.FieldError{Field: "email", Code: validation.CodeDuplicate, Message: fmt.Sprintf("Email %s is already registered to another member", email)}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/validation"
+)
+
+func TestImportMembers(t *testing.T) {
+	t.Parallel()
+
+	rows := [][]string{
+		{"First Name", "last_name", "E-mail", "DateOfBirth"},
+		{"John", "Doe", "John.Doe@gmail.com", "1990-01-01"},
+		{"Jane", "", "jane.smith@gmail", "1985-05-05"},
+		{"", "", "", ""},
+		{"Johnny", "Doe", "john.doe@gmail.com", "1991-02-02"},
+		{"Rafael", "Nadal", "rafael.nadal@gmail.com", "1986-06-03"},
+		{"Roger", "Federer", "roger.federer@gmail.com", "1981-08-08"},
+	}
+
+	testCases := []struct {
+		name             string
+		dryRun           bool
+		expectedStatuses []string
+		expectedMembers  int
+	}{
+		{
+			name:             "Success importing valid rows",
+			expectedStatuses: []string{models.ImportRowImported, models.ImportRowFailed, models.ImportRowFailed, models.ImportRowImported, models.ImportRowFailed},
+			expectedMembers:  3,
+		},
+		{
+			name:             "Dry run creates no members",
+			dryRun:           true,
+			expectedStatuses: []string{models.ImportRowValid, models.ImportRowFailed, models.ImportRowFailed, models.ImportRowValid, models.ImportRowFailed},
+			expectedMembers:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			memberRepo := repository.NewMemoryMemberRepository()
+			memberService := NewMemberService(memberRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(),
+				repository.NewMemoryIdAllocator(repository.MemberIdSequence), testClock, logging.Discard())
+			registered := memberService.CreateMember(ctx, &models.Member{FirstName: "Roger", LastName: "Federer", Email: "roger.federer@gmail.com", DateOfBirth: "1981-08-08"})
+			require.Equal(t, http.StatusCreated, registered.StatusCode)
+
+			response := memberService.ImportMembers(ctx, &models.MemberImport{
+				Rows:    rows,
+				Columns: map[string]string{"email": "E-mail"},
+				DryRun:  tc.dryRun,
+			})
+
+			require.Equal(t, http.StatusOK, response.StatusCode)
+			report := response.Body.(*models.ImportReport)
+			assert.Equal(t, tc.dryRun, report.DryRun)
+			assert.Equal(t, 5, report.Total)
+			assert.Equal(t, 2, report.Imported)
+			assert.Equal(t, 3, report.Failed)
+
+			statuses := make([]string, len(report.Rows))
+			for i, row := range report.Rows {
+				statuses[i] = row.Status
+			}
+			assert.Equal(t, tc.expectedStatuses, statuses)
+			assert.Equal(t, []int{2, 3, 5, 6, 7}, []int{report.Rows[0].Row, report.Rows[1].Row, report.Rows[2].Row, report.Rows[3].Row, report.Rows[4].Row})
+			assert.Equal(t, []string{"lastName", "email"}, []string{report.Rows[1].Errors[0].Field, report.Rows[1].Errors[1].Field})
+			assert.Equal(t, models.FieldError{Field: "email", Code: validation.CodeDuplicate, Message: "Email john.doe@gmail.com is also in row 2"}, report.Rows[2].Errors[0])
+			assert.Equal(t, models.FieldError{Field: "email", Code: validation.CodeDuplicate, Message: "Email roger.federer@gmail.com is already registered to another member"}, report.Rows[4].Errors[0])
+
+			page, err := memberRepo.GetAllMembers(ctx, models.MemberQuery{Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, int64(tc.expectedMembers), page.Total)
+			if !tc.dryRun {
+				member, err := memberRepo.GetMemberById(ctx, report.Rows[0].MemberId)
+				require.NoError(t, err)
+				assert.Equal(t, "john.doe@gmail.com", member.Email)
+				assert.Equal(t, models.MemberPending, member.Status)
+			}
+		})
+	}
+}
+
+func TestImportMembersRejectsFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		memberImport       *models.MemberImport
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name:               "Empty file",
+			memberImport:       &models.MemberImport{},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Import file is empty"},
+		},
+		{
+			name: "Required column missing and unknown field mapped",
+			memberImport: &models.MemberImport{
+				Rows:    [][]string{{"firstName", "lastName", "email"}},
+				Columns: map[string]string{"status": "Status"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: models.ErrorMessage{Error: "Validation failed", Fields: []models.FieldError{
+				{Field: "status", Code: validation.CodeInvalid, Message: "status cannot be imported"},
+				{Field: "dateOfBirth", Code: validation.CodeRequired, Message: "No column for dateOfBirth"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			memberService := NewMemberService(new(MockMemberRepository), new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.ImportMembers(context.Background(), tc.memberImport)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+		})
+	}
+}
+
+func TestImportMembersRepositoryError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockRepo := new(MockMemberRepository)
+	mockRepo.On("GetRegisteredEmails", ctx, []string{"john.doe@gmail.com"}).Return(nil, nil)
+	mockRepo.On("CreateMembers", ctx, mock.Anything).Return(nil, errors.New("repository error"))
+	mockIdAllocator := new(MockIdAllocator)
+	mockIdAllocator.On("NextIds", ctx, 1).Return([]int{100001}, nil)
+
+	memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), mockIdAllocator, testClock, logging.Discard())
+	response := memberService.ImportMembers(ctx, &models.MemberImport{Rows: [][]string{
+		{"firstName", "lastName", "email", "dateOfBirth"},
+		{"John", "Doe", "john.doe@gmail.com", "1990-01-01"},
+	}})
+
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	assert.Equal(t, models.ErrorMessage{Error: "Error importing members"}, response.Body)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportMembersReassignsTakenIds(t *testing.T) {
+	t.Parallel()
+
+	rows := [][]string{
+		{"firstName", "lastName", "email", "dateOfBirth"},
+		{"John", "Doe", "john.doe@gmail.com", "1990-01-01"},
+		{"Rafael", "Nadal", "rafael.nadal@gmail.com", "1986-06-03"},
+		{"Roger", "Federer", "roger.federer@gmail.com", "1981-08-08"},
+	}
+	// A member given a random ID before the counter existed.
+	legacyMember := &models.Member{ID: 100002, FirstName: "Jane", LastName: "Smith", Email: "jane.smith@gmail.com", DateOfBirth: "1985-05-05"}
+
+	t.Run("Rows given a taken id are given another", func(t *testing.T) {
+		ctx := context.Background()
+		memberRepo := repository.NewMemoryMemberRepository()
+		require.NoError(t, memberRepo.CreateMember(ctx, legacyMember))
+		memberService := NewMemberService(memberRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(),
+			repository.NewMemoryIdAllocator(repository.MemberIdSequence), testClock, logging.Discard())
+
+		response := memberService.ImportMembers(ctx, &models.MemberImport{Rows: rows})
+
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		report := response.Body.(*models.ImportReport)
+		assert.Equal(t, 3, report.Imported)
+		assert.Equal(t, []int{100001, 100004, 100003}, []int{report.Rows[0].MemberId, report.Rows[1].MemberId, report.Rows[2].MemberId})
+		member, err := memberRepo.GetMemberById(ctx, 100004)
+		require.NoError(t, err)
+		assert.Equal(t, "rafael.nadal@gmail.com", member.Email)
+	})
+
+	t.Run("Rows still given taken ids fail", func(t *testing.T) {
+		ctx := context.Background()
+		memberRepo := repository.NewMemoryMemberRepository()
+		require.NoError(t, memberRepo.CreateMember(ctx, legacyMember))
+		mockIdAllocator := new(MockIdAllocator)
+		mockIdAllocator.On("NextIds", ctx, 1).Return([]int{100002}, nil).Times(maxIdAllocationAttempts)
+		memberService := NewMemberService(memberRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), mockIdAllocator, testClock, logging.Discard())
+
+		response := memberService.ImportMembers(ctx, &models.MemberImport{Rows: rows[:2]})
+
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		report := response.Body.(*models.ImportReport)
+		assert.Equal(t, 0, report.Imported)
+		assert.Equal(t, models.ImportRowFailed, report.Rows[0].Status)
+		assert.Equal(t, "Error creating member, import the row again", report.Rows[0].Error)
+		mockIdAllocator.AssertExpectations(t)
+	})
+}
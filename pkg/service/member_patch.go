@@ -0,0 +1,72 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"members.com/membership/pkg/models"
+)
+
+var (
+	errInvalidPatch       = errors.New("invalid patch document")
+	errUnsupportedPatch   = errors.New("unsupported patch content type")
+	errPatchNotApplicable = errors.New("patch could not be applied")
+)
+
+// applyMemberPatch applies patch to the editable fields of member and returns
+// the result. The result still has to be validated.
+func applyMemberPatch(member *models.Member, patch models.MemberPatch) (*models.UpdateMember, error) {
+	original, err := json.Marshal(toUpdateMember(member))
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch patch.ContentType {
+	case models.MergePatchContentType:
+		if !json.Valid(patch.Document) {
+			return nil, errInvalidPatch
+		}
+		patched, err = jsonpatch.MergePatch(original, patch.Document)
+		if err != nil {
+			return nil, errInvalidPatch
+		}
+	case models.JsonPatchContentType:
+		operations, err := jsonpatch.DecodePatch(patch.Document)
+		if err != nil {
+			return nil, errInvalidPatch
+		}
+		patched, err = operations.Apply(original)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errPatchNotApplicable, err)
+		}
+	default:
+		return nil, errUnsupportedPatch
+	}
+
+	// Fields that are not editable, or values of the wrong type, make the
+	// patch inapplicable rather than being silently dropped.
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	var updateMember models.UpdateMember
+	if err := decoder.Decode(&updateMember); err != nil {
+		return nil, fmt.Errorf("%w: %v", errPatchNotApplicable, err)
+	}
+	return &updateMember, nil
+}
+
+func toUpdateMember(member *models.Member) *models.UpdateMember {
+	return &models.UpdateMember{
+		FirstName:   member.FirstName,
+		LastName:    member.LastName,
+		Email:       member.Email,
+		DateOfBirth: member.DateOfBirth,
+		PlanId:      member.PlanId,
+		StartDate:   member.StartDate,
+		ExpiryDate:  member.ExpiryDate,
+		LapsedOn:    member.LapsedOn,
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/utils"
+)
+
+type memberTransition struct {
+	from      []string
+	to        string
+	pastTense string
+}
+
+// memberTransitions is the member lifecycle. Cancelled is final, a cancelled
+// member has to be registered again.
+var memberTransitions = map[string]memberTransition{
+	models.TransitionActivate: {
+		from:      []string{models.MemberPending},
+		to:        models.MemberActive,
+		pastTense: "activated",
+	},
+	models.TransitionSuspend: {
+		from:      []string{models.MemberActive},
+		to:        models.MemberSuspended,
+		pastTense: "suspended",
+	},
+	models.TransitionReactivate: {
+		from:      []string{models.MemberSuspended},
+		to:        models.MemberActive,
+		pastTense: "reactivated",
+	},
+	models.TransitionCancel: {
+		from:      []string{models.MemberPending, models.MemberActive, models.MemberSuspended},
+		to:        models.MemberCancelled,
+		pastTense: "cancelled",
+	},
+}
+
+// ChangeMemberStatus makes a lifecycle transition, recording the reason for
+// it. Transitions that are not allowed from the member's current status are
+// rejected with 409.
+func (m *MemberService) ChangeMemberStatus(ctx context.Context, memberId int, transitionName string, reason string, expectedVersion int) models.Response {
+	transition, known := memberTransitions[transitionName]
+	if !known {
+		return createErrorResponse(http.StatusBadRequest, fmt.Sprintf("Unknown transition %s", transitionName))
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return createErrorResponse(http.StatusBadRequest, "Reason is required")
+	}
+
+	fetchedMember, err := m.memberRepository.GetMemberById(ctx, memberId)
+	if err != nil {
+		return m.handleMemberFetchError(ctx, err, memberId)
+	}
+	if !versionMatches(fetchedMember, expectedVersion) {
+		return createVersionMismatchResponse(memberId)
+	}
+
+	currentStatus := memberStatus(fetchedMember)
+	if !transitionAllowed(transition, currentStatus) {
+		return createInvalidTransitionResponse(memberId, currentStatus, transition.pastTense)
+	}
+
+	change := models.StatusChange{
+		From:      currentStatus,
+		To:        transition.to,
+		Reason:    reason,
+		ChangedAt: utils.FormatTimestamp(m.clock.Now()),
+	}
+	err = m.memberRepository.ChangeMemberStatus(ctx, memberId, fetchedMember.Version, change)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return createVersionMismatchResponse(memberId)
+	}
+	if err != nil {
+		return m.handleMemberWriteError(ctx, err, memberId, "Error changing member status")
+	}
+
+	before := *fetchedMember
+	fetchedMember.Status = change.To
+	fetchedMember.StatusChanges = append(fetchedMember.StatusChanges, change)
+	fetchedMember.Version++
+	m.auditor.RecordChange(ctx, models.AuditStatus, &before, fetchedMember)
+	m.setDerivedFields(fetchedMember)
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       fetchedMember,
+	}
+}
+
+// memberStatus returns the member's lifecycle status. Members stored before
+// statuses were introduced have none and count as active.
+func memberStatus(member *models.Member) string {
+	if member.Status == "" {
+		return models.MemberActive
+	}
+	return member.Status
+}
+
+func transitionAllowed(transition memberTransition, currentStatus string) bool {
+	for _, from := range transition.from {
+		if from == currentStatus {
+			return true
+		}
+	}
+	return false
+}
+
+func createInvalidTransitionResponse(memberId int, currentStatus string, pastTense string) models.Response {
+	return createErrorResponse(http.StatusConflict, fmt.Sprintf("Member %d is %s and cannot be %s", memberId, currentStatus, pastTense))
+}
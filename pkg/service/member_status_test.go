@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+)
+
+func TestChangeMemberStatus(t *testing.T) {
+	t.Parallel()
+
+	memberId := 1
+	fetchedMember := func(status string) *models.Member {
+		return &models.Member{
+			ID:          memberId,
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       "john.doe@gmail.com",
+			DateOfBirth: "1990-01-01",
+			Status:      status,
+			Version:     2,
+		}
+	}
+
+	allowedTransitions := []struct {
+		transition string
+		from       string
+		to         string
+	}{
+		{models.TransitionActivate, models.MemberPending, models.MemberActive},
+		{models.TransitionSuspend, models.MemberActive, models.MemberSuspended},
+		{models.TransitionSuspend, "", models.MemberSuspended},
+		{models.TransitionReactivate, models.MemberSuspended, models.MemberActive},
+		{models.TransitionCancel, models.MemberPending, models.MemberCancelled},
+		{models.TransitionCancel, models.MemberActive, models.MemberCancelled},
+		{models.TransitionCancel, models.MemberSuspended, models.MemberCancelled},
+	}
+	for _, tc := range allowedTransitions {
+		t.Run(tc.transition+" from "+tc.from, func(t *testing.T) {
+			ctx := context.Background()
+			from := tc.from
+			if from == "" {
+				from = models.MemberActive
+			}
+			change := models.StatusChange{From: from, To: tc.to, Reason: "Requested by member", ChangedAt: "2024-06-01T09:30:00Z"}
+			mockRepo := new(MockMemberRepository)
+			mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(tc.from), nil)
+			mockRepo.On("ChangeMemberStatus", ctx, memberId, 2, change).Return(nil)
+
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.ChangeMemberStatus(ctx, memberId, tc.transition, " Requested by member ", AnyVersion)
+
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			member := response.Body.(*models.Member)
+			assert.Equal(t, tc.to, member.Status)
+			assert.Equal(t, []models.StatusChange{change}, member.StatusChanges)
+			assert.Equal(t, 3, member.Version)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	rejectedTransitions := []struct {
+		transition    string
+		from          string
+		expectedError string
+	}{
+		{models.TransitionActivate, models.MemberActive, "Member 1 is active and cannot be activated"},
+		{models.TransitionSuspend, models.MemberPending, "Member 1 is pending and cannot be suspended"},
+		{models.TransitionReactivate, models.MemberActive, "Member 1 is active and cannot be reactivated"},
+		{models.TransitionCancel, models.MemberCancelled, "Member 1 is cancelled and cannot be cancelled"},
+		{models.TransitionReactivate, models.MemberCancelled, "Member 1 is cancelled and cannot be reactivated"},
+	}
+	for _, tc := range rejectedTransitions {
+		t.Run(tc.transition+" from "+tc.from+" is rejected", func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockMemberRepository)
+			mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(tc.from), nil)
+
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.ChangeMemberStatus(ctx, memberId, tc.transition, "Requested by member", AnyVersion)
+
+			assert.Equal(t, http.StatusConflict, response.StatusCode)
+			assert.Equal(t, models.ErrorMessage{Error: tc.expectedError}, response.Body)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	testCases := []struct {
+		name               string
+		reason             string
+		expectedVersion    int
+		memberRepoMock     func(ctx context.Context, mockRepo *MockMemberRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name:               "Reason is required",
+			reason:             "  ",
+			memberRepoMock:     func(ctx context.Context, mockRepo *MockMemberRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Reason is required"},
+		},
+		{
+			name:   "Member not found",
+			reason: "Requested by member",
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(nil, mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 not found"},
+		},
+		{
+			name:            "If-Match version is stale",
+			reason:          "Requested by member",
+			expectedVersion: 1,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(models.MemberActive), nil)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"},
+		},
+		{
+			name:   "Member changed while changing status",
+			reason: "Requested by member",
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(models.MemberActive), nil)
+				mockRepo.On("ChangeMemberStatus", ctx, memberId, 2, mock.Anything).Return(repository.ErrVersionConflict)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockMemberRepository)
+			tc.memberRepoMock(ctx, mockRepo)
+
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.ChangeMemberStatus(ctx, memberId, models.TransitionSuspend, tc.reason, expectedVersionOrAny(tc.expectedVersion))
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -1,21 +1,54 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/logging"
 	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/utils"
+	"members.com/membership/pkg/validation"
 )
 
 type MockMemberRepository struct {
 	mock.Mock
 }
 
+type MockIdAllocator struct {
+	mock.Mock
+}
+
+var testClock = utils.FixedClock{Time: time.Date(2024, time.June, 1, 9, 30, 0, 0, time.UTC)}
+
+// expectedVersionOrAny returns the If-Match version of a test case, or
+// AnyVersion for cases that leave it out.
+func expectedVersionOrAny(version int) int {
+	if version == 0 {
+		return AnyVersion
+	}
+	return version
+}
+
+func TestVersionMatches(t *testing.T) {
+	assert.True(t, versionMatches(&models.Member{Version: 3}, AnyVersion))
+	assert.True(t, versionMatches(&models.Member{Version: 3}, 3))
+	assert.False(t, versionMatches(&models.Member{Version: 3}, 2))
+	// Members stored before versions existed are at version 0.
+	assert.True(t, versionMatches(&models.Member{}, 0))
+	assert.False(t, versionMatches(&models.Member{}, 1))
+}
+
 func TestCreateMember(t *testing.T) {
 	t.Parallel()
 
@@ -29,23 +62,59 @@ func TestCreateMember(t *testing.T) {
 	testCases := []struct {
 		name               string
 		createMember       *models.Member
-		memberRepoMock     func(ctx context.Context, mockRepo *MockMemberRepository)
+		memberRepoMock     func(ctx context.Context, mockRepo *MockMemberRepository, mockIdAllocator *MockIdAllocator)
 		expectedStatusCode int
 		expectedBody       any
+		expectedId         int
 	}{
 		{
 			name:         "Success creating new member",
 			createMember: member,
-			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockIdAllocator *MockIdAllocator) {
+				mockIdAllocator.On("NextId", ctx).Return(100001, nil)
 				mockRepo.On("CreateMember", ctx, member).Return(nil)
 			},
 			expectedStatusCode: http.StatusCreated,
 			expectedBody:       member,
+			expectedId:         100001,
+		},
+		{
+			name:         "Retry with a new id when the id is already taken",
+			createMember: member,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockIdAllocator *MockIdAllocator) {
+				mockIdAllocator.On("NextId", ctx).Return(100001, nil).Once()
+				mockIdAllocator.On("NextId", ctx).Return(100002, nil).Once()
+				mockRepo.On("CreateMember", ctx, member).Return(repository.ErrDuplicateMemberId).Once()
+				mockRepo.On("CreateMember", ctx, member).Return(nil).Once()
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedBody:       member,
+			expectedId:         100002,
+		},
+		{
+			name:         "Email already registered",
+			createMember: member,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockIdAllocator *MockIdAllocator) {
+				mockIdAllocator.On("NextId", ctx).Return(100001, nil)
+				mockRepo.On("CreateMember", ctx, member).Return(repository.ErrDuplicateEmail)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       models.ErrorMessage{Error: "Email john.doe@gmail.com is already registered to another member"},
+		},
+		{
+			name:         "Error allocating member id",
+			createMember: member,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockIdAllocator *MockIdAllocator) {
+				mockIdAllocator.On("NextId", ctx).Return(0, errors.New("counter error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       models.ErrorMessage{Error: "Error creating member"},
 		},
 		{
 			name:         "Error creating new member",
 			createMember: member,
-			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockIdAllocator *MockIdAllocator) {
+				mockIdAllocator.On("NextId", ctx).Return(100001, nil)
 				mockRepo.On("CreateMember", ctx, member).Return(errors.New("repository error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
@@ -59,10 +128,10 @@ func TestCreateMember(t *testing.T) {
 				Email:       "John.Doegmail.com",
 				DateOfBirth: "1990-01-01",
 			},
-			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockIdAllocator *MockIdAllocator) {
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedBody:       models.ErrorMessage{Error: "Invalid email"},
+			expectedBody:       models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{{Field: "email", Code: validation.CodeInvalidEmail, Message: "Invalid email"}}},
 		},
 		{
 			name: "Invalid date of birth",
@@ -72,10 +141,26 @@ func TestCreateMember(t *testing.T) {
 				Email:       "John.Doe@gmail.com",
 				DateOfBirth: "1st April 1990",
 			},
-			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockIdAllocator *MockIdAllocator) {
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedBody:       models.ErrorMessage{Error: "Invalid date of birth"},
+			expectedBody:       models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{{Field: "dateOfBirth", Code: validation.CodeInvalidDate, Message: "Invalid date of birth"}}},
+		},
+		{
+			name: "Every invalid field is reported",
+			createMember: &models.Member{
+				FirstName:   "John",
+				Email:       "John.Doegmail.com",
+				DateOfBirth: "1st April 1990",
+			},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockIdAllocator *MockIdAllocator) {
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{
+				{Field: "lastName", Code: validation.CodeRequired, Message: "lastName is required"},
+				{Field: "email", Code: validation.CodeInvalidEmail, Message: "Invalid email"},
+				{Field: "dateOfBirth", Code: validation.CodeInvalidDate, Message: "Invalid date of birth"},
+			}},
 		},
 	}
 
@@ -83,18 +168,102 @@ func TestCreateMember(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.Background()
 			mockRepo := new(MockMemberRepository)
-			tc.memberRepoMock(ctx, mockRepo)
+			mockIdAllocator := new(MockIdAllocator)
+			tc.memberRepoMock(ctx, mockRepo, mockIdAllocator)
 
-			memberService := NewMemberService(mockRepo)
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), mockIdAllocator, testClock, logging.Discard())
 			response := memberService.CreateMember(ctx, tc.createMember)
 
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
 			assert.Equal(t, tc.expectedBody, response.Body)
+			if tc.expectedId != 0 {
+				assert.Equal(t, tc.expectedId, tc.createMember.ID)
+				assert.Equal(t, 1, tc.createMember.Version)
+				assert.Equal(t, models.MemberPending, tc.createMember.Status)
+			}
 			mockRepo.AssertExpectations(t)
+			mockIdAllocator.AssertExpectations(t)
 		})
 	}
 }
 
+func TestCreateMemberConcurrently(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockRepo := new(MockMemberRepository)
+	mockRepo.On("CreateMember", ctx, mock.Anything).Return(nil)
+	memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), repository.NewMemoryIdAllocator(repository.MemberIdSequence), testClock, logging.Discard())
+
+	const creates = 50
+	ids := make(chan int, creates)
+	var wg sync.WaitGroup
+	for i := 0; i < creates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			member := &models.Member{
+				FirstName:   "John",
+				LastName:    "Doe",
+				Email:       "John.Doe@gmail.com",
+				DateOfBirth: "1990-01-01",
+			}
+			response := memberService.CreateMember(ctx, member)
+			assert.Equal(t, http.StatusCreated, response.StatusCode)
+			ids <- member.ID
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int]bool)
+	for id := range ids {
+		assert.Falsef(t, seen[id], "member id %d allocated twice", id)
+		seen[id] = true
+	}
+	assert.Len(t, seen, creates)
+}
+
+func TestCreateMemberIgnoresServerOwnedFields(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	memberRepository := repository.NewMemoryMemberRepository()
+	memberService := NewMemberService(memberRepository, new(MockPlanRepository), repository.NewMemoryAuditRepository(), repository.NewMemoryIdAllocator(repository.MemberIdSequence), testClock, logging.Discard())
+
+	response := memberService.CreateMember(ctx, &models.Member{
+		ID:            7,
+		FirstName:     "John",
+		LastName:      "Doe",
+		Email:         "John.Doe@gmail.com",
+		DateOfBirth:   "1990-01-01",
+		ExpiryDate:    "2030-01-01",
+		LapsedOn:      "2000-01-01",
+		Renewals:      []models.Renewal{{RenewedOn: "2000-01-01"}},
+		Status:        models.MemberActive,
+		StatusChanges: []models.StatusChange{{From: models.MemberPending, To: models.MemberActive}},
+		DeletedAt:     "2000-01-01T00:00:00Z",
+		Version:       9,
+	})
+
+	require.Equal(t, http.StatusCreated, response.StatusCode)
+	created := response.Body.(*models.Member)
+	assert.NotEqual(t, 7, created.ID)
+	assert.Equal(t, "", created.DeletedAt)
+	assert.Equal(t, "", created.ExpiryDate)
+	assert.Equal(t, "", created.LapsedOn)
+	assert.Nil(t, created.Renewals)
+	assert.Equal(t, models.MemberPending, created.Status)
+	assert.Nil(t, created.StatusChanges)
+	assert.Equal(t, 1, created.Version)
+
+	fetched := memberService.GetMemberById(ctx, created.ID)
+	assert.Equal(t, http.StatusOK, fetched.StatusCode)
+	purged, err := memberRepository.PurgeDeletedMembers(ctx, "2024-06-01T09:30:00Z")
+	require.NoError(t, err)
+	assert.Empty(t, purged)
+}
+
 func TestGetMemberById(t *testing.T) {
 	t.Parallel()
 
@@ -145,7 +314,7 @@ func TestGetMemberById(t *testing.T) {
 			mockRepo := new(MockMemberRepository)
 			tc.memberRepoMock(ctx, mockRepo)
 
-			memberService := NewMemberService(mockRepo)
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
 			response := memberService.GetMemberById(ctx, memberId)
 
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
@@ -155,47 +324,204 @@ func TestGetMemberById(t *testing.T) {
 	}
 }
 
+func TestInternalErrorsAreLogged(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	ctx := logging.WithRequestID(context.Background(), "abc-123")
+	mockRepo := new(MockMemberRepository)
+	mockRepo.On("GetMemberById", ctx, 1).Return(nil, errors.New("connection reset"))
+
+	memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.New(&output, "info"))
+	response := memberService.GetMemberById(ctx, 1)
+
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(output.Bytes(), &line))
+	assert.Equal(t, "ERROR", line["level"])
+	assert.Equal(t, "Error fetching member", line["msg"])
+	assert.Equal(t, "connection reset", line["error"])
+	assert.Equal(t, "abc-123", line[logging.RequestIDKey])
+}
+
 func TestGetAllMembers(t *testing.T) {
 	t.Parallel()
 
-	members := []models.Member{
+	members := &models.MemberPage{
+		Items: []models.Member{
+			{
+				ID:          1,
+				FirstName:   "John",
+				LastName:    "Doe",
+				Email:       "John.Doe@gmail.com",
+				DateOfBirth: "1990-01-01",
+			},
+			{
+				ID:          2,
+				FirstName:   "Jane",
+				LastName:    "Smith",
+				Email:       "Jane.Smith@gmail.com",
+				DateOfBirth: "1985-05-05",
+			},
+		},
+		Total: 2,
+	}
+
+	testCases := []struct {
+		name               string
+		query              models.MemberQuery
+		memberRepoMock     func(ctx context.Context, mockRepo *MockMemberRepository, query models.MemberQuery)
+		expectedStatusCode int
+		expectedBody       any
+	}{
 		{
-			ID:          1,
-			FirstName:   "John",
-			LastName:    "Doe",
-			Email:       "John.Doe@gmail.com",
-			DateOfBirth: "1990-01-01",
+			name:  "Success getting all members",
+			query: models.MemberQuery{},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, query models.MemberQuery) {
+				mockRepo.On("GetAllMembers", ctx, query).Return(members, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       members,
+		},
+		{
+			name: "Success getting filtered and sorted members",
+			query: models.MemberQuery{
+				Limit:           10,
+				SortBy:          "lastName",
+				SortOrder:       "desc",
+				EmailDomain:     "gmail.com",
+				DateOfBirthFrom: "1980-01-01",
+				DateOfBirthTo:   "1999-12-31",
+			},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, query models.MemberQuery) {
+				mockRepo.On("GetAllMembers", ctx, query).Return(members, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       members,
+		},
+		{
+			name:  "Limit too large",
+			query: models.MemberQuery{Limit: 101},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, query models.MemberQuery) {
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Limit must be between 1 and 100"},
+		},
+		{
+			name:  "Invalid sort field",
+			query: models.MemberQuery{SortBy: "password"},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, query models.MemberQuery) {
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Invalid sort field"},
+		},
+		{
+			name:  "Invalid sort order",
+			query: models.MemberQuery{SortOrder: "sideways"},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, query models.MemberQuery) {
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Invalid sort order"},
 		},
 		{
-			ID:          2,
-			FirstName:   "Jane",
-			LastName:    "Smith",
-			Email:       "Jane.Smith@gmail.com",
-			DateOfBirth: "1985-05-05",
+			name:  "Invalid date of birth range",
+			query: models.MemberQuery{DateOfBirthFrom: "01/01/1980"},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, query models.MemberQuery) {
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Invalid date of birth range"},
+		},
+		{
+			name:  "Invalid cursor",
+			query: models.MemberQuery{Cursor: "not-a-cursor"},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, query models.MemberQuery) {
+				mockRepo.On("GetAllMembers", ctx, query).Return(nil, repository.ErrInvalidCursor)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Invalid cursor"},
 		},
+		{
+			name:  "Error getting all members",
+			query: models.MemberQuery{},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository, query models.MemberQuery) {
+				mockRepo.On("GetAllMembers", ctx, query).Return(nil, errors.New("repository error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       models.ErrorMessage{Error: "Error fetching members"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockMemberRepository)
+			tc.memberRepoMock(ctx, mockRepo, tc.query)
+
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.GetAllMembers(ctx, tc.query)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchMembers(t *testing.T) {
+	t.Parallel()
+
+	found := &models.MemberPage{
+		Items: []models.Member{{ID: 1, FirstName: "Rafael", LastName: "Nadal", Email: "rafael.nadal@gmail.com", DateOfBirth: "1986-06-03"}},
+		Total: 1,
 	}
 
 	testCases := []struct {
 		name               string
+		query              models.MemberSearchQuery
 		memberRepoMock     func(ctx context.Context, mockRepo *MockMemberRepository)
 		expectedStatusCode int
 		expectedBody       any
 	}{
 		{
-			name: "Success getting all members",
+			name:  "Success searching members",
+			query: models.MemberSearchQuery{Q: "  Rafa Nadal ", Limit: 10},
 			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
-				mockRepo.On("GetAllMembers", ctx).Return(members, nil)
+				mockRepo.On("SearchMembers", ctx, models.MemberSearchQuery{Q: "Rafa Nadal", Limit: 10}).Return(found, nil)
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedBody:       members,
+			expectedBody:       found,
 		},
 		{
-			name: "Error getting all members",
+			name:               "Empty search query",
+			query:              models.MemberSearchQuery{Q: "   "},
+			memberRepoMock:     func(ctx context.Context, mockRepo *MockMemberRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Search query is required"},
+		},
+		{
+			name:               "Limit out of range",
+			query:              models.MemberSearchQuery{Q: "Rafa", Limit: 101},
+			memberRepoMock:     func(ctx context.Context, mockRepo *MockMemberRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Limit must be between 1 and 100"},
+		},
+		{
+			name:  "Invalid cursor",
+			query: models.MemberSearchQuery{Q: "Rafa", Cursor: "garbage"},
 			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
-				mockRepo.On("GetAllMembers", ctx).Return(nil, errors.New("repository error"))
+				mockRepo.On("SearchMembers", ctx, models.MemberSearchQuery{Q: "Rafa", Cursor: "garbage"}).Return(nil, repository.ErrInvalidCursor)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Invalid cursor"},
+		},
+		{
+			name:  "Error searching members",
+			query: models.MemberSearchQuery{Q: "Rafa"},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("SearchMembers", ctx, models.MemberSearchQuery{Q: "Rafa"}).Return(nil, errors.New("text index required"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedBody:       models.ErrorMessage{Error: "Error fetching members"},
+			expectedBody:       models.ErrorMessage{Error: "Error searching members"},
 		},
 	}
 
@@ -205,8 +531,8 @@ func TestGetAllMembers(t *testing.T) {
 			mockRepo := new(MockMemberRepository)
 			tc.memberRepoMock(ctx, mockRepo)
 
-			memberService := NewMemberService(mockRepo)
-			response := memberService.GetAllMembers(ctx)
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.SearchMembers(ctx, tc.query)
 
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
 			assert.Equal(t, tc.expectedBody, response.Body)
@@ -220,20 +546,26 @@ func TestUpdateMemberById(t *testing.T) {
 
 	memberId := 1
 	updateMember := &models.UpdateMember{
-		FirstName: "Jonathan",
-		Email:     "Jonathan.Doe@gmail.com",
-	}
-	member := &models.Member{
-		ID:          memberId,
-		FirstName:   "John",
+		FirstName:   "Jonathan",
 		LastName:    "Doe",
-		Email:       "John.Doe@gmail.com",
+		Email:       "Jonathan.Doe@gmail.com",
 		DateOfBirth: "1990-01-01",
 	}
+	fetchedMember := func() *models.Member {
+		return &models.Member{
+			ID:          memberId,
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       "John.Doe@gmail.com",
+			DateOfBirth: "1990-01-01",
+			Version:     2,
+		}
+	}
 
 	testCases := []struct {
 		name               string
 		updateMember       *models.UpdateMember
+		expectedVersion    int
 		memberRepoMock     func(ctx context.Context, mockRepo *MockMemberRepository)
 		expectedStatusCode int
 		expectedBody       any
@@ -243,8 +575,8 @@ func TestUpdateMemberById(t *testing.T) {
 			name:         "Success updating member by id",
 			updateMember: updateMember,
 			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
-				mockRepo.On("GetMemberById", ctx, memberId).Return(member, nil)
-				mockRepo.On("UpdateMemberById", ctx, updateMember, memberId).Return(nil)
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+				mockRepo.On("UpdateMemberById", ctx, updateMember, memberId, 2).Return(nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			wantErr:            false,
@@ -273,33 +605,107 @@ func TestUpdateMemberById(t *testing.T) {
 			name:         "Error updating member by id",
 			updateMember: updateMember,
 			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
-				mockRepo.On("GetMemberById", ctx, memberId).Return(member, nil)
-				mockRepo.On("UpdateMemberById", ctx, updateMember, memberId).Return(errors.New("repository error"))
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+				mockRepo.On("UpdateMemberById", ctx, updateMember, memberId, 2).Return(errors.New("repository error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedBody:       models.ErrorMessage{Error: "Error updating member"},
 			wantErr:            true,
 		},
+		{
+			name:         "Updated email already registered",
+			updateMember: updateMember,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+				mockRepo.On("UpdateMemberById", ctx, updateMember, memberId, 2).Return(repository.ErrDuplicateEmail)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       models.ErrorMessage{Error: "Email jonathan.doe@gmail.com is already registered to another member"},
+			wantErr:            true,
+		},
+		{
+			name:            "Success updating member at expected version",
+			updateMember:    updateMember,
+			expectedVersion: 2,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+				mockRepo.On("UpdateMemberById", ctx, updateMember, memberId, 2).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			wantErr:            false,
+		},
+		{
+			name:            "Member is not at expected version",
+			updateMember:    updateMember,
+			expectedVersion: 1,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"},
+			wantErr:            true,
+		},
+		{
+			name:         "Member modified by a concurrent update",
+			updateMember: updateMember,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+				mockRepo.On("UpdateMemberById", ctx, updateMember, memberId, 2).Return(repository.ErrVersionConflict)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"},
+			wantErr:            true,
+		},
+		{
+			name:         "Member deleted by a concurrent request",
+			updateMember: updateMember,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+				mockRepo.On("UpdateMemberById", ctx, updateMember, memberId, 2).Return(mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 not found"},
+			wantErr:            true,
+		},
 		{
 			name: "Invalid updated email",
 			updateMember: &models.UpdateMember{
-				Email: "Jonathan.Doegmail.com",
+				FirstName:   "Jonathan",
+				LastName:    "Doe",
+				Email:       "Jonathan.Doegmail.com",
+				DateOfBirth: "1990-01-01",
 			},
 			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedBody:       models.ErrorMessage{Error: "Invalid email"},
+			expectedBody:       models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{{Field: "email", Code: validation.CodeInvalidEmail, Message: "Invalid email"}}},
 			wantErr:            true,
 		},
 		{
 			name: "Invalid updated date of birth",
 			updateMember: &models.UpdateMember{
+				FirstName:   "Jonathan",
+				LastName:    "Doe",
+				Email:       "Jonathan.Doe@gmail.com",
 				DateOfBirth: "1990-01-01T00:00:00Z",
 			},
 			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedBody:       models.ErrorMessage{Error: "Invalid date of birth"},
+			expectedBody:       models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{{Field: "dateOfBirth", Code: validation.CodeInvalidDate, Message: "Invalid date of birth"}}},
+			wantErr:            true,
+		},
+		{
+			name: "Fields cannot be left out of a replacement",
+			updateMember: &models.UpdateMember{
+				FirstName:   "Jonathan",
+				Email:       "Jonathan.Doe@gmail.com",
+				DateOfBirth: "1990-01-01",
+			},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{{Field: "lastName", Code: validation.CodeRequired, Message: "lastName is required"}}},
 			wantErr:            true,
 		},
 	}
@@ -310,8 +716,8 @@ func TestUpdateMemberById(t *testing.T) {
 			mockRepo := new(MockMemberRepository)
 			tc.memberRepoMock(ctx, mockRepo)
 
-			memberService := NewMemberService(mockRepo)
-			response := memberService.UpdateMemberById(ctx, tc.updateMember, memberId)
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.UpdateMemberById(ctx, tc.updateMember, memberId, expectedVersionOrAny(tc.expectedVersion))
 
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
 			if !tc.wantErr {
@@ -319,8 +725,9 @@ func TestUpdateMemberById(t *testing.T) {
 				assert.Equal(t, 1, updatedMember.ID)
 				assert.Equal(t, "Jonathan", updatedMember.FirstName)
 				assert.Equal(t, "Doe", updatedMember.LastName)
-				assert.Equal(t, "Jonathan.Doe@gmail.com", updatedMember.Email)
+				assert.Equal(t, "jonathan.doe@gmail.com", updatedMember.Email)
 				assert.Equal(t, "1990-01-01", updatedMember.DateOfBirth)
+				assert.Equal(t, 3, updatedMember.Version)
 			} else {
 				assert.Equal(t, tc.expectedBody, response.Body)
 			}
@@ -329,6 +736,164 @@ func TestUpdateMemberById(t *testing.T) {
 	}
 }
 
+func TestPatchMemberById(t *testing.T) {
+	t.Parallel()
+
+	memberId := 1
+	fetchedMember := func() *models.Member {
+		return &models.Member{
+			ID:          memberId,
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       "john.doe@gmail.com",
+			DateOfBirth: "1990-01-01",
+			Version:     2,
+		}
+	}
+	mergePatch := func(document string) models.MemberPatch {
+		return models.MemberPatch{ContentType: models.MergePatchContentType, Document: []byte(document)}
+	}
+	jsonPatch := func(document string) models.MemberPatch {
+		return models.MemberPatch{ContentType: models.JsonPatchContentType, Document: []byte(document)}
+	}
+
+	testCases := []struct {
+		name               string
+		patch              models.MemberPatch
+		expectedVersion    int
+		memberRepoMock     func(ctx context.Context, mockRepo *MockMemberRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name:  "Success applying merge patch",
+			patch: mergePatch(`{"firstName": "Jonathan", "email": "Jonathan.Doe@Tennis.com"}`),
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+				mockRepo.On("UpdateMemberById", ctx, &models.UpdateMember{
+					FirstName:   "Jonathan",
+					LastName:    "Doe",
+					Email:       "jonathan.doe@tennis.com",
+					DateOfBirth: "1990-01-01",
+				}, memberId, 2).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: &models.Member{
+				ID:          memberId,
+				FirstName:   "Jonathan",
+				LastName:    "Doe",
+				Email:       "jonathan.doe@tennis.com",
+				DateOfBirth: "1990-01-01",
+				Status:      models.MemberActive,
+				Version:     3,
+			},
+		},
+		{
+			name:            "Success applying json patch",
+			patch:           jsonPatch(`[{"op": "test", "path": "/lastName", "value": "Doe"}, {"op": "replace", "path": "/lastName", "value": "Smith"}]`),
+			expectedVersion: 2,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+				mockRepo.On("UpdateMemberById", ctx, &models.UpdateMember{
+					FirstName:   "John",
+					LastName:    "Smith",
+					Email:       "john.doe@gmail.com",
+					DateOfBirth: "1990-01-01",
+				}, memberId, 2).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: &models.Member{
+				ID:          memberId,
+				FirstName:   "John",
+				LastName:    "Smith",
+				Email:       "john.doe@gmail.com",
+				DateOfBirth: "1990-01-01",
+				Status:      models.MemberActive,
+				Version:     3,
+			},
+		},
+		{
+			name:  "Clearing a required field fails validation",
+			patch: mergePatch(`{"lastName": null}`),
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{{Field: "lastName", Code: validation.CodeRequired, Message: "lastName is required"}}},
+		},
+		{
+			name:  "Json patch test operation fails",
+			patch: jsonPatch(`[{"op": "test", "path": "/lastName", "value": "Smith"}]`),
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       models.ErrorMessage{Error: "Patch could not be applied to member"},
+		},
+		{
+			name:  "Patch adds a field that is not editable",
+			patch: mergePatch(`{"id": 2}`),
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       models.ErrorMessage{Error: "Patch could not be applied to member"},
+		},
+		{
+			name:  "Invalid patch document",
+			patch: jsonPatch(`{"op": "replace"}`),
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: "Invalid patch document"},
+		},
+		{
+			name:  "Unsupported patch content type",
+			patch: models.MemberPatch{ContentType: "application/json", Document: []byte(`{}`)},
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+			},
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+			expectedBody:       models.ErrorMessage{Error: "Patch must be application/merge-patch+json or application/json-patch+json"},
+		},
+		{
+			name:            "Member is not at expected version",
+			patch:           mergePatch(`{"firstName": "Jonathan"}`),
+			expectedVersion: 1,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"},
+		},
+		{
+			name:  "Member is not found",
+			patch: mergePatch(`{"firstName": "Jonathan"}`),
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(nil, mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 not found"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockMemberRepository)
+			tc.memberRepoMock(ctx, mockRepo)
+
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.PatchMemberById(ctx, tc.patch, memberId, expectedVersionOrAny(tc.expectedVersion))
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestDeleteMemberById(t *testing.T) {
 	t.Parallel()
 
@@ -339,10 +904,12 @@ func TestDeleteMemberById(t *testing.T) {
 		LastName:    "Doe",
 		Email:       "John.Doe@gmail.com",
 		DateOfBirth: "1990-01-01",
+		Version:     2,
 	}
 
 	testCases := []struct {
 		name               string
+		expectedVersion    int
 		memberRepoMock     func(ctx context.Context, mockRepo *MockMemberRepository)
 		expectedStatusCode int
 		expectedBody       any
@@ -351,7 +918,7 @@ func TestDeleteMemberById(t *testing.T) {
 			name: "Success deleting existing member",
 			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
 				mockRepo.On("GetMemberById", ctx, memberId).Return(member, nil)
-				mockRepo.On("DeleteMemberById", ctx, memberId).Return(nil)
+				mockRepo.On("DeleteMemberById", ctx, memberId, 2, "2024-06-01T09:30:00Z").Return(nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedBody:       models.SuccessMessage{Message: "Member 1 deleted"},
@@ -364,11 +931,29 @@ func TestDeleteMemberById(t *testing.T) {
 			expectedStatusCode: http.StatusNotFound,
 			expectedBody:       models.ErrorMessage{Error: "Member 1 not found"},
 		},
+		{
+			name:            "Member is not at expected version",
+			expectedVersion: 1,
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(member, nil)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"},
+		},
+		{
+			name: "Member modified by a concurrent update",
+			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(member, nil)
+				mockRepo.On("DeleteMemberById", ctx, memberId, 2, "2024-06-01T09:30:00Z").Return(repository.ErrVersionConflict)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"},
+		},
 		{
 			name: "Error deleting existing member",
 			memberRepoMock: func(ctx context.Context, mockRepo *MockMemberRepository) {
 				mockRepo.On("GetMemberById", ctx, memberId).Return(member, nil)
-				mockRepo.On("DeleteMemberById", ctx, memberId).Return(errors.New("repository error"))
+				mockRepo.On("DeleteMemberById", ctx, memberId, 2, "2024-06-01T09:30:00Z").Return(errors.New("repository error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedBody:       models.ErrorMessage{Error: "Could not delete Member 1"},
@@ -381,8 +966,8 @@ func TestDeleteMemberById(t *testing.T) {
 			mockRepo := new(MockMemberRepository)
 			tc.memberRepoMock(ctx, mockRepo)
 
-			memberService := NewMemberService(mockRepo)
-			response := memberService.DeleteMemberById(ctx, memberId)
+			memberService := NewMemberService(mockRepo, new(MockPlanRepository), repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.DeleteMemberById(ctx, memberId, expectedVersionOrAny(tc.expectedVersion))
 
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
 			assert.Equal(t, tc.expectedBody, response.Body)
@@ -391,11 +976,183 @@ func TestDeleteMemberById(t *testing.T) {
 	}
 }
 
+func TestRenewMemberById(t *testing.T) {
+	t.Parallel()
+
+	memberId := 1
+	plan := &models.Plan{ID: 1, Name: "Gold", DurationMonths: 12}
+	fetchedMember := func(expiryDate string, lapsedOn string) *models.Member {
+		return &models.Member{
+			ID:          memberId,
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       "john.doe@gmail.com",
+			DateOfBirth: "1990-01-01",
+			PlanId:      1,
+			StartDate:   "2023-06-20",
+			ExpiryDate:  expiryDate,
+			LapsedOn:    lapsedOn,
+			Version:     2,
+		}
+	}
+
+	testCases := []struct {
+		name               string
+		expectedVersion    int
+		repoMock           func(ctx context.Context, mockRepo *MockMemberRepository, mockPlanRepo *MockPlanRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name: "Renewing before expiry extends from the expiry date",
+			repoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockPlanRepo *MockPlanRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember("2024-06-20", ""), nil)
+				mockPlanRepo.On("GetPlanById", ctx, 1).Return(plan, nil)
+				mockRepo.On("RenewMember", ctx, memberId, 2, models.Renewal{
+					RenewedOn:          "2024-06-01",
+					PlanId:             1,
+					PreviousExpiryDate: "2024-06-20",
+					ExpiryDate:         "2025-06-20",
+				}).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: &models.Member{
+				ID:               memberId,
+				FirstName:        "John",
+				LastName:         "Doe",
+				Email:            "john.doe@gmail.com",
+				DateOfBirth:      "1990-01-01",
+				PlanId:           1,
+				StartDate:        "2023-06-20",
+				ExpiryDate:       "2025-06-20",
+				MembershipStatus: models.MembershipActive,
+				Renewals: []models.Renewal{{
+					RenewedOn:          "2024-06-01",
+					PlanId:             1,
+					PreviousExpiryDate: "2024-06-20",
+					ExpiryDate:         "2025-06-20",
+				}},
+				Status:  models.MemberActive,
+				Version: 3,
+			},
+		},
+		{
+			name: "Renewing a lapsed membership extends from today",
+			repoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockPlanRepo *MockPlanRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember("2024-03-01", "2024-03-01"), nil)
+				mockPlanRepo.On("GetPlanById", ctx, 1).Return(plan, nil)
+				mockRepo.On("RenewMember", ctx, memberId, 2, models.Renewal{
+					RenewedOn:          "2024-06-01",
+					PlanId:             1,
+					PreviousExpiryDate: "2024-03-01",
+					ExpiryDate:         "2025-06-01",
+				}).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: &models.Member{
+				ID:               memberId,
+				FirstName:        "John",
+				LastName:         "Doe",
+				Email:            "john.doe@gmail.com",
+				DateOfBirth:      "1990-01-01",
+				PlanId:           1,
+				StartDate:        "2023-06-20",
+				ExpiryDate:       "2025-06-01",
+				MembershipStatus: models.MembershipActive,
+				Renewals: []models.Renewal{{
+					RenewedOn:          "2024-06-01",
+					PlanId:             1,
+					PreviousExpiryDate: "2024-03-01",
+					ExpiryDate:         "2025-06-01",
+				}},
+				Status:  models.MemberActive,
+				Version: 3,
+			},
+		},
+		{
+			name: "Member without a plan",
+			repoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockPlanRepo *MockPlanRepository) {
+				member := fetchedMember("", "")
+				member.PlanId = 0
+				mockRepo.On("GetMemberById", ctx, memberId).Return(member, nil)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 has no plan to renew"},
+		},
+		{
+			name: "Cancelled member",
+			repoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockPlanRepo *MockPlanRepository) {
+				member := fetchedMember("2024-06-20", "")
+				member.Status = models.MemberCancelled
+				mockRepo.On("GetMemberById", ctx, memberId).Return(member, nil)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 is cancelled and cannot be renewed"},
+		},
+		{
+			name:            "If-Match version is stale",
+			expectedVersion: 1,
+			repoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockPlanRepo *MockPlanRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember("2024-06-20", ""), nil)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"},
+		},
+		{
+			name: "Member changed while renewing",
+			repoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockPlanRepo *MockPlanRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember("2024-06-20", ""), nil)
+				mockPlanRepo.On("GetPlanById", ctx, 1).Return(plan, nil)
+				mockRepo.On("RenewMember", ctx, memberId, 2, mock.Anything).Return(repository.ErrVersionConflict)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 has been modified, fetch it again and retry"},
+		},
+		{
+			name: "Member not found",
+			repoMock: func(ctx context.Context, mockRepo *MockMemberRepository, mockPlanRepo *MockPlanRepository) {
+				mockRepo.On("GetMemberById", ctx, memberId).Return(nil, mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       models.ErrorMessage{Error: "Member 1 not found"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockMemberRepository)
+			mockPlanRepo := new(MockPlanRepository)
+			tc.repoMock(ctx, mockRepo, mockPlanRepo)
+
+			memberService := NewMemberService(mockRepo, mockPlanRepo, repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.RenewMemberById(ctx, memberId, expectedVersionOrAny(tc.expectedVersion))
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockRepo.AssertExpectations(t)
+			mockPlanRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func (m *MockMemberRepository) CreateMember(ctx context.Context, member *models.Member) error {
 	args := m.Called(ctx, member)
 	return args.Error(0)
 }
 
+func (m *MockMemberRepository) CreateMembers(ctx context.Context, members []models.Member) (map[int]error, error) {
+	args := m.Called(ctx, members)
+	failed, _ := args.Get(0).(map[int]error)
+	return failed, args.Error(1)
+}
+
+func (m *MockMemberRepository) GetRegisteredEmails(ctx context.Context, emails []string) ([]string, error) {
+	args := m.Called(ctx, emails)
+	registered, _ := args.Get(0).([]string)
+	return registered, args.Error(1)
+}
+
 func (m *MockMemberRepository) GetMemberById(ctx context.Context, memberId int) (*models.Member, error) {
 	args := m.Called(ctx, memberId)
 	member, ok := args.Get(0).(*models.Member)
@@ -405,21 +1162,288 @@ func (m *MockMemberRepository) GetMemberById(ctx context.Context, memberId int)
 	return member, args.Error(1)
 }
 
-func (m *MockMemberRepository) GetAllMembers(ctx context.Context) ([]models.Member, error) {
-	args := m.Called(ctx)
-	members, ok := args.Get(0).([]models.Member)
+func (m *MockMemberRepository) GetAllMembers(ctx context.Context, query models.MemberQuery) (*models.MemberPage, error) {
+	args := m.Called(ctx, query)
+	members, ok := args.Get(0).(*models.MemberPage)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return members, args.Error(1)
+}
+
+func (m *MockMemberRepository) StreamMembers(ctx context.Context, query models.MemberQuery) (repository.MemberIterator, error) {
+	args := m.Called(ctx, query)
+	members, _ := args.Get(0).(repository.MemberIterator)
+	return members, args.Error(1)
+}
+
+func (m *MockMemberRepository) SearchMembers(ctx context.Context, query models.MemberSearchQuery) (*models.MemberPage, error) {
+	args := m.Called(ctx, query)
+	members, ok := args.Get(0).(*models.MemberPage)
 	if !ok {
 		return nil, args.Error(1)
 	}
 	return members, args.Error(1)
 }
 
-func (m *MockMemberRepository) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int) error {
-	args := m.Called(ctx, member, memberId)
+func (m *MockMemberRepository) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int, version int) error {
+	args := m.Called(ctx, member, memberId, version)
+	return args.Error(0)
+}
+
+func (m *MockMemberRepository) DeleteMemberById(ctx context.Context, memberId int, version int, deletedAt string) error {
+	args := m.Called(ctx, memberId, version, deletedAt)
 	return args.Error(0)
 }
 
-func (m *MockMemberRepository) DeleteMemberById(ctx context.Context, memberId int) error {
+func (m *MockMemberRepository) RestoreMemberById(ctx context.Context, memberId int) (*models.Member, error) {
 	args := m.Called(ctx, memberId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Member), args.Error(1)
+}
+
+func (m *MockMemberRepository) PurgeDeletedMembers(ctx context.Context, deletedBefore string) ([]int, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockMemberRepository) CountMembersWithPlan(ctx context.Context, planId int) (int64, error) {
+	args := m.Called(ctx, planId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMemberRepository) RenewMember(ctx context.Context, memberId int, version int, renewal models.Renewal) error {
+	args := m.Called(ctx, memberId, version, renewal)
 	return args.Error(0)
 }
+
+func (m *MockMemberRepository) LapseExpiredMembers(ctx context.Context, today string) ([]int, error) {
+	args := m.Called(ctx, today)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockMemberRepository) ChangeMemberStatus(ctx context.Context, memberId int, version int, change models.StatusChange) error {
+	args := m.Called(ctx, memberId, version, change)
+	return args.Error(0)
+}
+
+func (m *MockIdAllocator) NextId(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockIdAllocator) NextIds(ctx context.Context, n int) ([]int, error) {
+	args := m.Called(ctx, n)
+	ids, ok := args.Get(0).([]int)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return ids, args.Error(1)
+}
+
+func TestCreateMemberWithPlan(t *testing.T) {
+	t.Parallel()
+
+	plan := &models.Plan{ID: 1, Name: "Gold", DurationMonths: 12, PriceCents: 12000}
+	newMember := func(planId int, startDate string) *models.Member {
+		return &models.Member{
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       "john.doe@gmail.com",
+			DateOfBirth: "1990-01-01",
+			PlanId:      planId,
+			StartDate:   startDate,
+		}
+	}
+
+	testCases := []struct {
+		name               string
+		createMember       *models.Member
+		planRepoMock       func(ctx context.Context, mockPlanRepo *MockPlanRepository)
+		expectedStatusCode int
+		expectedStartDate  string
+		expectedExpiryDate string
+		expectedStatus     string
+		expectedBody       any
+	}{
+		{
+			name:         "Membership starts today by default",
+			createMember: newMember(1, ""),
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository) {
+				mockPlanRepo.On("GetPlanById", ctx, 1).Return(plan, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedStartDate:  "2024-06-01",
+			expectedExpiryDate: "2025-06-01",
+			expectedStatus:     models.MembershipActive,
+		},
+		{
+			name:         "Membership from a past start date",
+			createMember: newMember(1, "2023-06-20"),
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository) {
+				mockPlanRepo.On("GetPlanById", ctx, 1).Return(plan, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedStartDate:  "2023-06-20",
+			expectedExpiryDate: "2024-06-20",
+			expectedStatus:     models.MembershipExpiringSoon,
+		},
+		{
+			name:         "Unknown plan",
+			createMember: newMember(2, ""),
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository) {
+				mockPlanRepo.On("GetPlanById", ctx, 2).Return(nil, mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{{Field: "planId", Code: validation.CodeUnknownPlan, Message: "Plan 2 does not exist"}}},
+		},
+		{
+			name:               "Start date without a plan",
+			createMember:       newMember(0, "2024-01-01"),
+			planRepoMock:       func(ctx context.Context, mockPlanRepo *MockPlanRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{{Field: "startDate", Code: validation.CodeInvalid, Message: "Start date requires a plan"}}},
+		},
+		{
+			name:               "Invalid start date",
+			createMember:       newMember(1, "01-01-2024"),
+			planRepoMock:       func(ctx context.Context, mockPlanRepo *MockPlanRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{{Field: "startDate", Code: validation.CodeInvalidDate, Message: "Invalid start date"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockMemberRepository)
+			mockRepo.On("CreateMember", ctx, tc.createMember).Return(nil).Maybe()
+			mockPlanRepo := new(MockPlanRepository)
+			tc.planRepoMock(ctx, mockPlanRepo)
+			mockIdAllocator := new(MockIdAllocator)
+			mockIdAllocator.On("NextId", ctx).Return(100001, nil).Maybe()
+
+			memberService := NewMemberService(mockRepo, mockPlanRepo, repository.NewMemoryAuditRepository(), mockIdAllocator, testClock, logging.Discard())
+			response := memberService.CreateMember(ctx, tc.createMember)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			if tc.expectedBody != nil {
+				assert.Equal(t, tc.expectedBody, response.Body)
+				mockRepo.AssertNotCalled(t, "CreateMember", ctx, tc.createMember)
+			} else {
+				createdMember := response.Body.(*models.Member)
+				assert.Equal(t, tc.expectedStartDate, createdMember.StartDate)
+				assert.Equal(t, tc.expectedExpiryDate, createdMember.ExpiryDate)
+				assert.Equal(t, tc.expectedStatus, createdMember.MembershipStatus)
+			}
+			mockPlanRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateMemberPlan(t *testing.T) {
+	t.Parallel()
+
+	memberId := 1
+	fetchedMember := func() *models.Member {
+		return &models.Member{
+			ID:          memberId,
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       "john.doe@gmail.com",
+			DateOfBirth: "1990-01-01",
+			PlanId:      1,
+			StartDate:   "2024-01-01",
+			ExpiryDate:  "2025-01-01",
+			Version:     2,
+		}
+	}
+	updateMember := func(planId int, startDate string) *models.UpdateMember {
+		return &models.UpdateMember{
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       "john.doe@gmail.com",
+			DateOfBirth: "1990-01-01",
+			PlanId:      planId,
+			StartDate:   startDate,
+		}
+	}
+
+	testCases := []struct {
+		name               string
+		updateMember       *models.UpdateMember
+		planRepoMock       func(ctx context.Context, mockPlanRepo *MockPlanRepository)
+		expectedExpiryDate string
+		expectedStatus     string
+	}{
+		{
+			name:               "Unchanged plan keeps its expiry date",
+			updateMember:       updateMember(1, "2024-01-01"),
+			planRepoMock:       func(ctx context.Context, mockPlanRepo *MockPlanRepository) {},
+			expectedExpiryDate: "2025-01-01",
+			expectedStatus:     models.MembershipActive,
+		},
+		{
+			name:         "Changed plan recalculates the expiry date",
+			updateMember: updateMember(2, "2024-01-01"),
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository) {
+				mockPlanRepo.On("GetPlanById", ctx, 2).Return(&models.Plan{ID: 2, Name: "Trial", DurationMonths: 6}, nil)
+			},
+			expectedExpiryDate: "2024-07-01",
+			expectedStatus:     models.MembershipExpiringSoon,
+		},
+		{
+			name:               "Removing the plan clears the membership",
+			updateMember:       updateMember(0, ""),
+			planRepoMock:       func(ctx context.Context, mockPlanRepo *MockPlanRepository) {},
+			expectedExpiryDate: "",
+			expectedStatus:     "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockMemberRepository)
+			mockRepo.On("GetMemberById", ctx, memberId).Return(fetchedMember(), nil)
+			mockRepo.On("UpdateMemberById", ctx, tc.updateMember, memberId, 2).Return(nil)
+			mockPlanRepo := new(MockPlanRepository)
+			tc.planRepoMock(ctx, mockPlanRepo)
+
+			memberService := NewMemberService(mockRepo, mockPlanRepo, repository.NewMemoryAuditRepository(), new(MockIdAllocator), testClock, logging.Discard())
+			response := memberService.UpdateMemberById(ctx, tc.updateMember, memberId, AnyVersion)
+
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			updatedMember := response.Body.(*models.Member)
+			assert.Equal(t, tc.updateMember.PlanId, updatedMember.PlanId)
+			assert.Equal(t, tc.expectedExpiryDate, updatedMember.ExpiryDate)
+			assert.Equal(t, tc.expectedStatus, updatedMember.MembershipStatus)
+			mockRepo.AssertExpectations(t)
+			mockPlanRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMembershipStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		expiryDate string
+		expected   string
+	}{
+		{"", ""},
+		{"2024-05-31", models.MembershipLapsed},
+		{"2024-06-01", models.MembershipLapsed},
+		{"2024-06-02", models.MembershipExpiringSoon},
+		{"2024-07-01", models.MembershipExpiringSoon},
+		{"2024-07-02", models.MembershipActive},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expiryDate, func(t *testing.T) {
+			assert.Equal(t, tc.expected, membershipStatus(tc.expiryDate, testClock.Now()))
+		})
+	}
+}
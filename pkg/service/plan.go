@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/repository"
+	"members.com/membership/pkg/validation"
+)
+
+type PlanServiceI interface {
+	CreatePlan(ctx context.Context, plan *models.Plan) models.Response
+	GetPlanById(ctx context.Context, planId int) models.Response
+	GetAllPlans(ctx context.Context) models.Response
+	UpdatePlanById(ctx context.Context, plan *models.UpdatePlan, planId int) models.Response
+	DeletePlanById(ctx context.Context, planId int) models.Response
+}
+
+type PlanService struct {
+	planRepository   repository.PlanRepositoryI
+	memberRepository repository.MemberRepositoryI
+	idAllocator      repository.IdAllocatorI
+	logger           *slog.Logger
+}
+
+func NewPlanService(planRepository repository.PlanRepositoryI, memberRepository repository.MemberRepositoryI, idAllocator repository.IdAllocatorI, logger *slog.Logger) PlanServiceI {
+	return &PlanService{
+		planRepository:   planRepository,
+		memberRepository: memberRepository,
+		idAllocator:      idAllocator,
+		logger:           logger,
+	}
+}
+
+func (p *PlanService) CreatePlan(ctx context.Context, plan *models.Plan) models.Response {
+	plan.Name = strings.TrimSpace(plan.Name)
+	if fieldErrors := validation.ValidatePlan(toUpdatePlan(plan)); fieldErrors != nil {
+		return createValidationErrorResponse(fieldErrors)
+	}
+	if plan.Benefits == nil {
+		plan.Benefits = []string{}
+	}
+
+	planId, err := p.idAllocator.NextId(ctx)
+	if err != nil {
+		return createInternalErrorResponse(ctx, p.logger, err, "Error creating plan")
+	}
+	plan.ID = planId
+
+	if err := p.planRepository.CreatePlan(ctx, plan); err != nil {
+		return createInternalErrorResponse(ctx, p.logger, err, "Error creating plan")
+	}
+	return models.Response{
+		StatusCode: http.StatusCreated,
+		Body:       plan,
+	}
+}
+
+func (p *PlanService) GetPlanById(ctx context.Context, planId int) models.Response {
+	plan, err := p.planRepository.GetPlanById(ctx, planId)
+	if err != nil {
+		return p.handlePlanError(ctx, err, planId, "Error fetching plan")
+	}
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       plan,
+	}
+}
+
+func (p *PlanService) GetAllPlans(ctx context.Context) models.Response {
+	plans, err := p.planRepository.GetAllPlans(ctx)
+	if err != nil {
+		return createInternalErrorResponse(ctx, p.logger, err, "Error fetching plans")
+	}
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body:       plans,
+	}
+}
+
+// UpdatePlanById replaces all editable fields of the plan. Members already on
+// the plan keep their expiry dates.
+func (p *PlanService) UpdatePlanById(ctx context.Context, plan *models.UpdatePlan, planId int) models.Response {
+	plan.Name = strings.TrimSpace(plan.Name)
+	if fieldErrors := validation.ValidatePlan(plan); fieldErrors != nil {
+		return createValidationErrorResponse(fieldErrors)
+	}
+	if plan.Benefits == nil {
+		plan.Benefits = []string{}
+	}
+
+	if err := p.planRepository.UpdatePlanById(ctx, plan, planId); err != nil {
+		return p.handlePlanError(ctx, err, planId, "Error updating plan")
+	}
+	return models.Response{
+		StatusCode: http.StatusOK,
+		Body: &models.Plan{
+			ID:             planId,
+			Name:           plan.Name,
+			DurationMonths: plan.DurationMonths,
+			PriceCents:     plan.PriceCents,
+			Benefits:       plan.Benefits,
+		},
+	}
+}
+
+// DeletePlanById refuses to delete a plan that members still hold. Members
+// are counted again once the plan is deleted, and the plan is created again
+// if one was put on it in the meantime, so no member is left on a deleted
+// plan.
+func (p *PlanService) DeletePlanById(ctx context.Context, planId int) models.Response {
+	errorMessage := fmt.Sprintf("Could not delete Plan %d", planId)
+	members, err := p.memberRepository.CountMembersWithPlan(ctx, planId)
+	if err != nil {
+		return createInternalErrorResponse(ctx, p.logger, err, errorMessage)
+	}
+	if members > 0 {
+		return planHeldResponse(planId, members)
+	}
+
+	plan, err := p.planRepository.DeletePlanById(ctx, planId)
+	if err != nil {
+		return p.handlePlanError(ctx, err, planId, errorMessage)
+	}
+
+	members, err = p.memberRepository.CountMembersWithPlan(ctx, planId)
+	if err == nil && members == 0 {
+		return createSuccessResponse(http.StatusOK, fmt.Sprintf("Plan %d deleted", planId))
+	}
+	// The members could not be counted, or some were put on the plan while
+	// it was being deleted. Either way, the delete is undone.
+	if createErr := p.planRepository.CreatePlan(context.WithoutCancel(ctx), plan); createErr != nil {
+		return createInternalErrorResponse(ctx, p.logger, errors.Join(err, createErr), fmt.Sprintf("Plan %d was deleted but could not be created again", planId))
+	}
+	if err != nil {
+		return createInternalErrorResponse(ctx, p.logger, err, errorMessage)
+	}
+	return planHeldResponse(planId, members)
+}
+
+func planHeldResponse(planId int, members int64) models.Response {
+	return createErrorResponse(http.StatusConflict, fmt.Sprintf("Plan %d cannot be deleted while %d member(s) hold it", planId, members))
+}
+
+func (p *PlanService) handlePlanError(ctx context.Context, err error, planId int, errorMessage string) models.Response {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return createErrorResponse(http.StatusNotFound, fmt.Sprintf("Plan %d not found", planId))
+	}
+	return createInternalErrorResponse(ctx, p.logger, err, errorMessage)
+}
+
+func toUpdatePlan(plan *models.Plan) *models.UpdatePlan {
+	return &models.UpdatePlan{
+		Name:           plan.Name,
+		DurationMonths: plan.DurationMonths,
+		PriceCents:     plan.PriceCents,
+		Benefits:       plan.Benefits,
+	}
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+	"members.com/membership/pkg/logging"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/validation"
+)
+
+type MockPlanRepository struct {
+	mock.Mock
+}
+
+func TestCreatePlan(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		createPlan         *models.Plan
+		planRepoMock       func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockIdAllocator *MockIdAllocator)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name:       "Success creating new plan",
+			createPlan: &models.Plan{Name: " Gold ", DurationMonths: 12, PriceCents: 12000},
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockIdAllocator *MockIdAllocator) {
+				mockIdAllocator.On("NextId", ctx).Return(1, nil)
+				mockPlanRepo.On("CreatePlan", ctx, mock.Anything).Return(nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedBody:       &models.Plan{ID: 1, Name: "Gold", DurationMonths: 12, PriceCents: 12000, Benefits: []string{}},
+		},
+		{
+			name:       "Error creating new plan",
+			createPlan: &models.Plan{Name: "Gold", DurationMonths: 12},
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockIdAllocator *MockIdAllocator) {
+				mockIdAllocator.On("NextId", ctx).Return(1, nil)
+				mockPlanRepo.On("CreatePlan", ctx, mock.Anything).Return(errors.New("insert failed"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       models.ErrorMessage{Error: "Error creating plan"},
+		},
+		{
+			name:               "Missing name",
+			createPlan:         &models.Plan{Name: "  ", DurationMonths: 12},
+			planRepoMock:       func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockIdAllocator *MockIdAllocator) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{
+				{Field: "name", Code: validation.CodeRequired, Message: "name is required"},
+			}},
+		},
+		{
+			name:               "Invalid duration",
+			createPlan:         &models.Plan{Name: "Gold", DurationMonths: -1},
+			planRepoMock:       func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockIdAllocator *MockIdAllocator) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{
+				{Field: "durationMonths", Code: validation.CodeInvalid, Message: "Duration must be at least 1 month"},
+			}},
+		},
+		{
+			name:               "Negative price",
+			createPlan:         &models.Plan{Name: "Gold", DurationMonths: 12, PriceCents: -100},
+			planRepoMock:       func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockIdAllocator *MockIdAllocator) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{
+				{Field: "priceCents", Code: validation.CodeInvalid, Message: "Price must not be negative"},
+			}},
+		},
+		{
+			name:               "Every invalid field is reported",
+			createPlan:         &models.Plan{Name: " ", DurationMonths: -1, PriceCents: -100},
+			planRepoMock:       func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockIdAllocator *MockIdAllocator) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{
+				{Field: "name", Code: validation.CodeRequired, Message: "name is required"},
+				{Field: "durationMonths", Code: validation.CodeInvalid, Message: "Duration must be at least 1 month"},
+				{Field: "priceCents", Code: validation.CodeInvalid, Message: "Price must not be negative"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockPlanRepo := new(MockPlanRepository)
+			mockIdAllocator := new(MockIdAllocator)
+			tc.planRepoMock(ctx, mockPlanRepo, mockIdAllocator)
+
+			planService := NewPlanService(mockPlanRepo, new(MockMemberRepository), mockIdAllocator, logging.Discard())
+			response := planService.CreatePlan(ctx, tc.createPlan)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockPlanRepo.AssertExpectations(t)
+			mockIdAllocator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPlanById(t *testing.T) {
+	t.Parallel()
+
+	plan := &models.Plan{ID: 1, Name: "Gold", DurationMonths: 12, PriceCents: 12000, Benefits: []string{"Sauna"}}
+
+	testCases := []struct {
+		name               string
+		planRepoMock       func(ctx context.Context, mockPlanRepo *MockPlanRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name: "Success getting plan by id",
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository) {
+				mockPlanRepo.On("GetPlanById", ctx, 1).Return(plan, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       plan,
+		},
+		{
+			name: "Plan not found",
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository) {
+				mockPlanRepo.On("GetPlanById", ctx, 1).Return(nil, mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       models.ErrorMessage{Error: "Plan 1 not found"},
+		},
+		{
+			name: "Error getting plan by id",
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository) {
+				mockPlanRepo.On("GetPlanById", ctx, 1).Return(nil, errors.New("find failed"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       models.ErrorMessage{Error: "Error fetching plan"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockPlanRepo := new(MockPlanRepository)
+			tc.planRepoMock(ctx, mockPlanRepo)
+
+			planService := NewPlanService(mockPlanRepo, new(MockMemberRepository), new(MockIdAllocator), logging.Discard())
+			response := planService.GetPlanById(ctx, 1)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockPlanRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAllPlans(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	plans := []models.Plan{
+		{ID: 1, Name: "Gold", DurationMonths: 12, PriceCents: 12000},
+		{ID: 2, Name: "Trial", DurationMonths: 1},
+	}
+	mockPlanRepo := new(MockPlanRepository)
+	mockPlanRepo.On("GetAllPlans", ctx).Return(plans, nil)
+
+	planService := NewPlanService(mockPlanRepo, new(MockMemberRepository), new(MockIdAllocator), logging.Discard())
+	response := planService.GetAllPlans(ctx)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, plans, response.Body)
+}
+
+func TestUpdatePlanById(t *testing.T) {
+	t.Parallel()
+
+	updatePlan := func() *models.UpdatePlan {
+		return &models.UpdatePlan{Name: "Platinum", DurationMonths: 24, PriceCents: 20000}
+	}
+
+	testCases := []struct {
+		name               string
+		updatePlan         *models.UpdatePlan
+		planRepoMock       func(ctx context.Context, mockPlanRepo *MockPlanRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name:       "Success updating plan by id",
+			updatePlan: updatePlan(),
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository) {
+				mockPlanRepo.On("UpdatePlanById", ctx, mock.Anything, 1).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       &models.Plan{ID: 1, Name: "Platinum", DurationMonths: 24, PriceCents: 20000, Benefits: []string{}},
+		},
+		{
+			name:       "Plan not found",
+			updatePlan: updatePlan(),
+			planRepoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository) {
+				mockPlanRepo.On("UpdatePlanById", ctx, mock.Anything, 1).Return(mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       models.ErrorMessage{Error: "Plan 1 not found"},
+		},
+		{
+			name:               "Invalid duration",
+			updatePlan:         &models.UpdatePlan{Name: "Platinum", DurationMonths: -12},
+			planRepoMock:       func(ctx context.Context, mockPlanRepo *MockPlanRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: models.ErrorMessage{Error: validation.ErrorMessage, Fields: []models.FieldError{
+				{Field: "durationMonths", Code: validation.CodeInvalid, Message: "Duration must be at least 1 month"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockPlanRepo := new(MockPlanRepository)
+			tc.planRepoMock(ctx, mockPlanRepo)
+
+			planService := NewPlanService(mockPlanRepo, new(MockMemberRepository), new(MockIdAllocator), logging.Discard())
+			response := planService.UpdatePlanById(ctx, tc.updatePlan, 1)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockPlanRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeletePlanById(t *testing.T) {
+	t.Parallel()
+
+	plan := &models.Plan{ID: 1, Name: "Gold", DurationMonths: 12, PriceCents: 12000, Benefits: []string{"Sauna"}}
+
+	testCases := []struct {
+		name               string
+		repoMock           func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockRepo *MockMemberRepository)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name: "Success deleting plan by id",
+			repoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockRepo *MockMemberRepository) {
+				mockRepo.On("CountMembersWithPlan", ctx, 1).Return(int64(0), nil).Twice()
+				mockPlanRepo.On("DeletePlanById", ctx, 1).Return(plan, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       models.SuccessMessage{Message: "Plan 1 deleted"},
+		},
+		{
+			name: "Member put on the plan while it was deleted",
+			repoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockRepo *MockMemberRepository) {
+				mockRepo.On("CountMembersWithPlan", ctx, 1).Return(int64(0), nil).Once()
+				mockPlanRepo.On("DeletePlanById", ctx, 1).Return(plan, nil)
+				mockRepo.On("CountMembersWithPlan", ctx, 1).Return(int64(1), nil).Once()
+				mockPlanRepo.On("CreatePlan", mock.Anything, plan).Return(nil)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       models.ErrorMessage{Error: "Plan 1 cannot be deleted while 1 member(s) hold it"},
+		},
+		{
+			name: "Error counting members once the plan is deleted",
+			repoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockRepo *MockMemberRepository) {
+				mockRepo.On("CountMembersWithPlan", ctx, 1).Return(int64(0), nil).Once()
+				mockPlanRepo.On("DeletePlanById", ctx, 1).Return(plan, nil)
+				mockRepo.On("CountMembersWithPlan", ctx, 1).Return(int64(0), errors.New("count failed")).Once()
+				mockPlanRepo.On("CreatePlan", mock.Anything, plan).Return(nil)
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       models.ErrorMessage{Error: "Could not delete Plan 1"},
+		},
+		{
+			name: "Error creating the plan again",
+			repoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockRepo *MockMemberRepository) {
+				mockRepo.On("CountMembersWithPlan", ctx, 1).Return(int64(0), nil).Once()
+				mockPlanRepo.On("DeletePlanById", ctx, 1).Return(plan, nil)
+				mockRepo.On("CountMembersWithPlan", ctx, 1).Return(int64(1), nil).Once()
+				mockPlanRepo.On("CreatePlan", mock.Anything, plan).Return(errors.New("insert failed"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       models.ErrorMessage{Error: "Plan 1 was deleted but could not be created again"},
+		},
+		{
+			name: "Plan held by members",
+			repoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockRepo *MockMemberRepository) {
+				mockRepo.On("CountMembersWithPlan", ctx, 1).Return(int64(3), nil)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       models.ErrorMessage{Error: "Plan 1 cannot be deleted while 3 member(s) hold it"},
+		},
+		{
+			name: "Plan not found",
+			repoMock: func(ctx context.Context, mockPlanRepo *MockPlanRepository, mockRepo *MockMemberRepository) {
+				mockRepo.On("CountMembersWithPlan", ctx, 1).Return(int64(0), nil)
+				mockPlanRepo.On("DeletePlanById", ctx, 1).Return(nil, mongo.ErrNoDocuments)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       models.ErrorMessage{Error: "Plan 1 not found"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockPlanRepo := new(MockPlanRepository)
+			mockRepo := new(MockMemberRepository)
+			tc.repoMock(ctx, mockPlanRepo, mockRepo)
+
+			planService := NewPlanService(mockPlanRepo, mockRepo, new(MockIdAllocator), logging.Discard())
+			response := planService.DeletePlanById(ctx, 1)
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, response.Body)
+			mockPlanRepo.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func (m *MockPlanRepository) CreatePlan(ctx context.Context, plan *models.Plan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockPlanRepository) GetPlanById(ctx context.Context, planId int) (*models.Plan, error) {
+	args := m.Called(ctx, planId)
+	plan, ok := args.Get(0).(*models.Plan)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return plan, args.Error(1)
+}
+
+func (m *MockPlanRepository) GetAllPlans(ctx context.Context) ([]models.Plan, error) {
+	args := m.Called(ctx)
+	plans, ok := args.Get(0).([]models.Plan)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return plans, args.Error(1)
+}
+
+func (m *MockPlanRepository) UpdatePlanById(ctx context.Context, plan *models.UpdatePlan, planId int) error {
+	args := m.Called(ctx, plan, planId)
+	return args.Error(0)
+}
+
+func (m *MockPlanRepository) DeletePlanById(ctx context.Context, planId int) (*models.Plan, error) {
+	args := m.Called(ctx, planId)
+	plan, ok := args.Get(0).(*models.Plan)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return plan, args.Error(1)
+}
@@ -0,0 +1,91 @@
+// Package spreadsheet reads the tabular files members are imported from and
+// writes those they are exported to.
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Supported file formats.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+const (
+	CSVContentType  = "text/csv"
+	XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported spreadsheet format")
+
+// FormatOf works out the format of a file from its content type, or from its
+// name when the content type does not tell, as with uploads sent as
+// application/octet-stream.
+func FormatOf(filename string, contentType string) (string, bool) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case CSVContentType:
+		return FormatCSV, true
+	case XLSXContentType:
+		return FormatXLSX, true
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, true
+	case ".xlsx":
+		return FormatXLSX, true
+	}
+	return "", false
+}
+
+// ReadRows returns every row of the file, the header row first. XLSX files
+// are read from their first sheet. Rows may have fewer cells than the
+// header when trailing cells are empty.
+func ReadRows(r io.Reader, format string) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatXLSX:
+		return readXLSX(r)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	// Spreadsheet programs often start UTF-8 CSV files with a byte order
+	// mark, which would otherwise end up in the first header.
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+	return rows, nil
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+	return file.GetRows(sheets[0])
+}
@@ -0,0 +1,134 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestFormatOf(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		filename       string
+		contentType    string
+		expectedFormat string
+		expectedOk     bool
+	}{
+		{name: "CSV content type", contentType: "text/csv; charset=utf-8", expectedFormat: FormatCSV, expectedOk: true},
+		{name: "XLSX content type", contentType: XLSXContentType, expectedFormat: FormatXLSX, expectedOk: true},
+		{name: "File name decides for generic content", filename: "Members.XLSX", contentType: "application/octet-stream", expectedFormat: FormatXLSX, expectedOk: true},
+		{name: "Unsupported", filename: "members.json", contentType: "application/json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			format, ok := FormatOf(tc.filename, tc.contentType)
+
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedFormat, format)
+		})
+	}
+}
+
+func TestReadRows(t *testing.T) {
+	t.Parallel()
+
+	expected := [][]string{
+		{"First Name", "Last Name", "Email"},
+		{"Rafael", "Nadal", "rafael.nadal@gmail.com"},
+		{"José", "Müller, Jr.", ""},
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		csv := "\ufeffFirst Name,Last Name,Email\nRafael,Nadal,rafael.nadal@gmail.com\nJosé,\"Müller, Jr.\",\n"
+
+		rows, err := ReadRows(strings.NewReader(csv), FormatCSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, rows)
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		file := excelize.NewFile()
+		for i, row := range expected {
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			values := make([]any, len(row))
+			for j, value := range row {
+				values[j] = value
+			}
+			require.NoError(t, file.SetSheetRow("Sheet1", cell, &values))
+		}
+		var data bytes.Buffer
+		require.NoError(t, file.Write(&data))
+
+		rows, err := ReadRows(&data, FormatXLSX)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{expected[0], expected[1], {"José", "Müller, Jr."}}, rows)
+	})
+
+	t.Run("Malformed CSV", func(t *testing.T) {
+		_, err := ReadRows(strings.NewReader("name\n\"unterminated\n"), FormatCSV)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		_, err := ReadRows(strings.NewReader(""), "json")
+
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	header := []string{"id", "lastName", "planId"}
+	rows := [][]any{
+		{100001, "Nadal", 2},
+		{100002, "=HYPERLINK(\"http://evil\")", nil},
+	}
+
+	writeFile := func(t *testing.T, format string) *bytes.Buffer {
+		var data bytes.Buffer
+		writer, err := NewWriter(&data, format, header)
+		require.NoError(t, err)
+		for _, row := range rows {
+			require.NoError(t, writer.WriteRow(row))
+		}
+		require.NoError(t, writer.Close())
+		return &data
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		data := writeFile(t, FormatCSV)
+
+		assert.Equal(t, "id,lastName,planId\n100001,Nadal,2\n100002,\"'=HYPERLINK(\"\"http://evil\"\")\",\n", data.String())
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		data := writeFile(t, FormatNDJSON)
+
+		assert.Equal(t, "{\"id\":100001,\"lastName\":\"Nadal\",\"planId\":2}\n{\"id\":100002,\"lastName\":\"=HYPERLINK(\\\"http://evil\\\")\",\"planId\":null}\n", data.String())
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		data := writeFile(t, FormatXLSX)
+
+		read, err := ReadRows(data, FormatXLSX)
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{header, {"100001", "Nadal", "2"}, {"100002", "=HYPERLINK(\"http://evil\")"}}, read)
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		_, err := NewWriter(&bytes.Buffer{}, "json", header)
+
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
@@ -0,0 +1,174 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// FormatNDJSON is newline delimited JSON, one object per row. It can be
+// written but not read.
+const (
+	FormatNDJSON      = "ndjson"
+	NDJSONContentType = "application/x-ndjson"
+)
+
+// ContentType returns the content type files in format are sent with.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return CSVContentType
+	case FormatXLSX:
+		return XLSXContentType
+	case FormatNDJSON:
+		return NDJSONContentType
+	}
+	return ""
+}
+
+// Writer writes a file a row at a time. Cells are strings, integers or nil
+// for empty cells. Close finishes the file and must be called even when
+// writing a row failed.
+type Writer interface {
+	WriteRow(cells []any) error
+	Close() error
+}
+
+// NewWriter starts a file in format on w, with header as its first row. In
+// NDJSON files the header names the fields of every row's object instead.
+func NewWriter(w io.Writer, format string, header []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, header)
+	case FormatXLSX:
+		return newXLSXWriter(w, header)
+	case FormatNDJSON:
+		return &ndjsonWriter{writer: bufio.NewWriter(w), header: header}, nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, header []string) (*csvWriter, error) {
+	c := &csvWriter{writer: csv.NewWriter(w), record: make([]string, len(header))}
+	if err := c.writer.Write(header); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *csvWriter) WriteRow(cells []any) error {
+	for i, cell := range cells {
+		c.record[i] = csvCell(cell)
+	}
+	return c.writer.Write(c.record[:len(cells)])
+}
+
+func (c *csvWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// csvCell formats a cell. Text that spreadsheet programs would run as a
+// formula is prefixed with a quote, so that opening an export cannot run
+// formulas smuggled in through member fields.
+func csvCell(cell any) string {
+	switch value := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+			return "'" + value
+		}
+		return value
+	}
+	return fmt.Sprint(cell)
+}
+
+// xlsxWriter streams rows into the first sheet of a workbook. The stream
+// writer keeps large sheets in a temporary file, and the workbook is only
+// written out on Close.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	rows   int
+}
+
+func newXLSXWriter(w io.Writer, header []string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	x := &xlsxWriter{out: w, file: file, stream: stream}
+	cells := make([]any, len(header))
+	for i, heading := range header {
+		cells[i] = heading
+	}
+	if err := x.WriteRow(cells); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxWriter) WriteRow(cells []any) error {
+	x.rows++
+	cell, err := excelize.CoordinatesToCellName(1, x.rows)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, cells)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
+
+type ndjsonWriter struct {
+	writer *bufio.Writer
+	header []string
+}
+
+// WriteRow writes the row as an object with its fields in header order.
+func (n *ndjsonWriter) WriteRow(cells []any) error {
+	n.writer.WriteByte('{')
+	for i, cell := range cells {
+		if i > 0 {
+			n.writer.WriteByte(',')
+		}
+		key, err := json.Marshal(n.header[i])
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(cell)
+		if err != nil {
+			return err
+		}
+		n.writer.Write(key)
+		n.writer.WriteByte(':')
+		n.writer.Write(value)
+	}
+	n.writer.WriteByte('}')
+	_, err := n.writer.WriteString("\n")
+	return err
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.writer.Flush()
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/service"
+)
+
+// memberIdKey is the attribute the member a call is about is recorded as.
+const memberIdKey = attribute.Key("membership.member.id")
+
+// memberService starts a span for every call to the member service it wraps.
+type memberService struct {
+	members service.MemberServiceI
+	tracer  trace.Tracer
+}
+
+// NewMemberService wraps members so its calls are traced.
+func NewMemberService(members service.MemberServiceI, provider trace.TracerProvider) service.MemberServiceI {
+	return &memberService{
+		members: members,
+		tracer:  provider.Tracer(tracerName),
+	}
+}
+
+func (m *memberService) start(ctx context.Context, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return m.tracer.Start(ctx, "MemberService."+operation, trace.WithAttributes(attributes...))
+}
+
+// end ends span with the response's status code. Only 5xx responses mark it
+// as failed; the others are answers to the caller's request.
+func end(span trace.Span, response models.Response) models.Response {
+	span.SetAttributes(attribute.Int("membership.response.status_code", response.StatusCode))
+	if response.StatusCode >= 500 {
+		span.SetStatus(codes.Error, http.StatusText(response.StatusCode))
+	}
+	span.End()
+	return response
+}
+
+func (m *memberService) CreateMember(ctx context.Context, member *models.Member) models.Response {
+	ctx, span := m.start(ctx, "CreateMember")
+	return end(span, m.members.CreateMember(ctx, member))
+}
+
+func (m *memberService) GetMemberById(ctx context.Context, memberId int) models.Response {
+	ctx, span := m.start(ctx, "GetMemberById", memberIdKey.Int(memberId))
+	return end(span, m.members.GetMemberById(ctx, memberId))
+}
+
+func (m *memberService) GetAllMembers(ctx context.Context, query models.MemberQuery) models.Response {
+	ctx, span := m.start(ctx, "GetAllMembers")
+	return end(span, m.members.GetAllMembers(ctx, query))
+}
+
+func (m *memberService) SearchMembers(ctx context.Context, query models.MemberSearchQuery) models.Response {
+	ctx, span := m.start(ctx, "SearchMembers")
+	return end(span, m.members.SearchMembers(ctx, query))
+}
+
+func (m *memberService) UpdateMemberById(ctx context.Context, member *models.UpdateMember, memberId int, expectedVersion int) models.Response {
+	ctx, span := m.start(ctx, "UpdateMemberById", memberIdKey.Int(memberId))
+	return end(span, m.members.UpdateMemberById(ctx, member, memberId, expectedVersion))
+}
+
+func (m *memberService) PatchMemberById(ctx context.Context, patch models.MemberPatch, memberId int, expectedVersion int) models.Response {
+	ctx, span := m.start(ctx, "PatchMemberById", memberIdKey.Int(memberId))
+	return end(span, m.members.PatchMemberById(ctx, patch, memberId, expectedVersion))
+}
+
+func (m *memberService) DeleteMemberById(ctx context.Context, memberId int, expectedVersion int) models.Response {
+	ctx, span := m.start(ctx, "DeleteMemberById", memberIdKey.Int(memberId))
+	return end(span, m.members.DeleteMemberById(ctx, memberId, expectedVersion))
+}
+
+func (m *memberService) RenewMemberById(ctx context.Context, memberId int, expectedVersion int) models.Response {
+	ctx, span := m.start(ctx, "RenewMemberById", memberIdKey.Int(memberId))
+	return end(span, m.members.RenewMemberById(ctx, memberId, expectedVersion))
+}
+
+func (m *memberService) ChangeMemberStatus(ctx context.Context, memberId int, transition string, reason string, expectedVersion int) models.Response {
+	ctx, span := m.start(ctx, "ChangeMemberStatus", memberIdKey.Int(memberId), attribute.String("membership.member.transition", transition))
+	return end(span, m.members.ChangeMemberStatus(ctx, memberId, transition, reason, expectedVersion))
+}
+
+func (m *memberService) ImportMembers(ctx context.Context, memberImport *models.MemberImport) models.Response {
+	ctx, span := m.start(ctx, "ImportMembers")
+	return end(span, m.members.ImportMembers(ctx, memberImport))
+}
+
+// ExportMembers traces opening the export. The members are streamed to the
+// client after it returns, within the request's span.
+func (m *memberService) ExportMembers(ctx context.Context, query models.MemberExportQuery) models.Response {
+	ctx, span := m.start(ctx, "ExportMembers")
+	return end(span, m.members.ExportMembers(ctx, query))
+}
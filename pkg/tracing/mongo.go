@@ -0,0 +1,103 @@
+// Package tracing traces the member service and the Mongo commands run on
+// its behalf with OpenTelemetry. The spans are children of the span the
+// handler layer starts for the request, which reaches them on the context.
+package tracing
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans this package starts.
+const tracerName = "members.com/membership/pkg/tracing"
+
+// commandKey identifies a command between its started and finished events.
+type commandKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// NewCommandMonitor returns a Mongo command monitor that starts a client
+// span for every command run with a context that carries a span, and ends it
+// when the command succeeds or fails. Commands run outside a traced request,
+// such as the health checks' pings and the scheduler's jobs, are not traced.
+// Command documents are not recorded, as they hold members' details.
+func NewCommandMonitor(provider trace.TracerProvider) *event.CommandMonitor {
+	tracer := provider.Tracer(tracerName)
+	var spans sync.Map
+	finish := func(event event.CommandFinishedEvent) trace.Span {
+		span, found := spans.LoadAndDelete(commandKey{event.ConnectionID, event.RequestID})
+		if !found {
+			return nil
+		}
+		return span.(trace.Span)
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, event *event.CommandStartedEvent) {
+			if !trace.SpanContextFromContext(ctx).IsValid() {
+				return
+			}
+			name := event.CommandName
+			attributes := []attribute.KeyValue{
+				semconv.DBSystemMongoDB,
+				semconv.DBNamespace(event.DatabaseName),
+				semconv.DBOperationName(event.CommandName),
+			}
+			if collection := commandCollection(event); collection != "" {
+				name += " " + collection
+				attributes = append(attributes, semconv.DBCollectionName(collection))
+			}
+			attributes = append(attributes, serverAttributes(event.ConnectionID)...)
+
+			_, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+			spans.Store(commandKey{event.ConnectionID, event.RequestID}, span)
+		},
+		Succeeded: func(ctx context.Context, event *event.CommandSucceededEvent) {
+			if span := finish(event.CommandFinishedEvent); span != nil {
+				span.End()
+			}
+		},
+		Failed: func(ctx context.Context, event *event.CommandFailedEvent) {
+			if span := finish(event.CommandFinishedEvent); span != nil {
+				span.SetStatus(codes.Error, event.Failure)
+				span.End()
+			}
+		},
+	}
+}
+
+// commandCollection returns the collection a command runs against. Most
+// commands name it as the value of their first field; getMore names it in
+// its collection field.
+func commandCollection(event *event.CommandStartedEvent) string {
+	if collection, ok := event.Command.Lookup(event.CommandName).StringValueOK(); ok {
+		return collection
+	}
+	collection, _ := event.Command.Lookup("collection").StringValueOK()
+	return collection
+}
+
+// serverAttributes describes the server from the ID the driver gives a
+// connection, such as localhost:27017[-4].
+func serverAttributes(connectionID string) []attribute.KeyValue {
+	address, _, _ := strings.Cut(connectionID, "[")
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil
+	}
+	attributes := []attribute.KeyValue{semconv.ServerAddress(host)}
+	if number, err := strconv.Atoi(port); err == nil {
+		attributes = append(attributes, semconv.ServerPort(number))
+	}
+	return attributes
+}
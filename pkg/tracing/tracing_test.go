@@ -0,0 +1,133 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/service"
+)
+
+func newProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	spans := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)), spans
+}
+
+func started(t *testing.T, requestID int64, command bson.D) *event.CommandStartedEvent {
+	t.Helper()
+	raw, err := bson.Marshal(command)
+	require.NoError(t, err)
+	return &event.CommandStartedEvent{
+		Command:      raw,
+		DatabaseName: "membership",
+		CommandName:  command[0].Key,
+		RequestID:    requestID,
+		ConnectionID: "mongo:27017[-3]",
+	}
+}
+
+func finished(requestID int64) event.CommandFinishedEvent {
+	return event.CommandFinishedEvent{RequestID: requestID, ConnectionID: "mongo:27017[-3]"}
+}
+
+func TestCommandMonitor(t *testing.T) {
+	provider, spans := newProvider()
+	monitor := NewCommandMonitor(provider)
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "GET /member/:id")
+
+	monitor.Started(ctx, started(t, 1, bson.D{{Key: "find", Value: "members"}, {Key: "filter", Value: bson.D{{Key: "memberId", Value: 100001}}}}))
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished(1)})
+	monitor.Started(ctx, started(t, 2, bson.D{{Key: "getMore", Value: int64(42)}, {Key: "collection", Value: "members"}}))
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished(2), Failure: "cursor not found"})
+	parent.End()
+
+	ended := spans.Ended()
+	require.Len(t, ended, 3)
+	find, getMore := ended[0], ended[1]
+
+	assert.Equal(t, "find members", find.Name())
+	assert.Equal(t, trace.SpanKindClient, find.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), find.Parent().SpanID())
+	assert.Equal(t, codes.Unset, find.Status().Code)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("db.system", "mongodb"),
+		attribute.String("db.namespace", "membership"),
+		attribute.String("db.operation.name", "find"),
+		attribute.String("db.collection.name", "members"),
+		attribute.String("server.address", "mongo"),
+		attribute.Int("server.port", 27017),
+	}, find.Attributes())
+	for _, attr := range find.Attributes() {
+		assert.NotContains(t, attr.Value.Emit(), "100001")
+	}
+
+	assert.Equal(t, "getMore members", getMore.Name())
+	assert.Equal(t, codes.Error, getMore.Status().Code)
+	assert.Equal(t, "cursor not found", getMore.Status().Description)
+}
+
+func TestCommandMonitorIgnoresCommandsOutsideTraces(t *testing.T) {
+	provider, spans := newProvider()
+	monitor := NewCommandMonitor(provider)
+
+	monitor.Started(context.Background(), started(t, 1, bson.D{{Key: "ping", Value: 1}}))
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{CommandFinishedEvent: finished(1)})
+
+	assert.Empty(t, spans.Started())
+}
+
+// stubMemberService answers GetMemberById with response, remembering the
+// span it was called in.
+type stubMemberService struct {
+	service.MemberServiceI
+	response models.Response
+	span     trace.SpanContext
+}
+
+func (s *stubMemberService) GetMemberById(ctx context.Context, memberId int) models.Response {
+	s.span = trace.SpanContextFromContext(ctx)
+	return s.response
+}
+
+func TestMemberService(t *testing.T) {
+	testCases := []struct {
+		name               string
+		statusCode         int
+		expectedSpanStatus codes.Code
+	}{
+		{name: "Found", statusCode: http.StatusOK, expectedSpanStatus: codes.Unset},
+		{name: "Not found", statusCode: http.StatusNotFound, expectedSpanStatus: codes.Unset},
+		{name: "Failed", statusCode: http.StatusInternalServerError, expectedSpanStatus: codes.Error},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, spans := newProvider()
+			members := &stubMemberService{response: models.Response{StatusCode: tc.statusCode}}
+			ctx, parent := provider.Tracer("test").Start(context.Background(), "GET /member/:id")
+
+			response := NewMemberService(members, provider).GetMemberById(ctx, 100001)
+			parent.End()
+
+			assert.Equal(t, tc.statusCode, response.StatusCode)
+			ended := spans.Ended()
+			require.Len(t, ended, 2)
+			span := ended[0]
+			assert.Equal(t, "MemberService.GetMemberById", span.Name())
+			assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+			assert.Equal(t, span.SpanContext().SpanID(), members.span.SpanID())
+			assert.Equal(t, tc.expectedSpanStatus, span.Status().Code)
+			assert.Contains(t, span.Attributes(), attribute.Int("membership.member.id", 100001))
+		})
+	}
+}
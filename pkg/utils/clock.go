@@ -0,0 +1,23 @@
+package utils
+
+import "time"
+
+// Clock tells the current time. Anything that depends on today's date takes
+// a Clock so tests can pin it with a FixedClock.
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+type FixedClock struct {
+	Time time.Time
+}
+
+func (f FixedClock) Now() time.Time {
+	return f.Time
+}
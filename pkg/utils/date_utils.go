@@ -10,3 +10,17 @@ func IsValidDate(dateStr string) bool {
 	_, err := time.Parse(dateFormat, dateStr)
 	return err == nil
 }
+
+func ParseDate(dateStr string) (time.Time, error) {
+	return time.Parse(dateFormat, dateStr)
+}
+
+func FormatDate(date time.Time) string {
+	return date.Format(dateFormat)
+}
+
+// FormatTimestamp formats t as RFC 3339 in UTC, so timestamps compare in
+// time order as strings.
+func FormatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -29,3 +30,17 @@ func TestIsValidDate(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAndFormatDate(t *testing.T) {
+	date, err := ParseDate("2024-02-29")
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-02-29", FormatDate(date))
+
+	_, err = ParseDate("29-02-2024")
+	assert.Error(t, err)
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	sydney := time.FixedZone("AEST", 10*60*60)
+	assert.Equal(t, "2024-05-31T23:30:00Z", FormatTimestamp(time.Date(2024, time.June, 1, 9, 30, 0, 0, sydney)))
+}
@@ -29,3 +29,22 @@ func TestIsValidEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeEmail(t *testing.T) {
+	testCases := []struct {
+		emailStr string
+		expected string
+	}{
+		{"test@example.com", "test@example.com"},
+		{"Rafael.Nadal@Gmail.com", "rafael.nadal@gmail.com"},
+		{"  user@example.com ", "user@example.com"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.emailStr, func(t *testing.T) {
+			result := NormalizeEmail(tc.emailStr)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package utils
 
-import "regexp"
+import (
+	"regexp"
+	"strings"
+)
 
 func IsValidEmail(emailStr string) bool {
 	// Define a regular expression for validating an email address
@@ -8,3 +11,9 @@ func IsValidEmail(emailStr string) bool {
 	emailRegex := regexp.MustCompile(emailRegexPattern)
 	return emailRegex.MatchString(emailStr)
 }
+
+// NormalizeEmail returns the form emails are stored and compared in, so that
+// addresses differing only in case or surrounding spaces are treated as one.
+func NormalizeEmail(emailStr string) string {
+	return strings.ToLower(strings.TrimSpace(emailStr))
+}
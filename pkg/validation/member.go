@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"members.com/membership/pkg/models"
+	"members.com/membership/pkg/utils"
+)
+
+// ValidateMember returns every problem with the member's fields, or nil when
+// they are all valid. Whether the plan exists is checked by the service.
+func ValidateMember(member *models.UpdateMember) []models.FieldError {
+	var fieldErrors []models.FieldError
+
+	if member.FirstName == "" {
+		fieldErrors = append(fieldErrors, Required("firstName"))
+	}
+
+	if member.LastName == "" {
+		fieldErrors = append(fieldErrors, Required("lastName"))
+	}
+
+	switch {
+	case member.Email == "":
+		fieldErrors = append(fieldErrors, Required("email"))
+	case !utils.IsValidEmail(member.Email):
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "email", Code: CodeInvalidEmail, Message: "Invalid email"})
+	}
+
+	switch {
+	case member.DateOfBirth == "":
+		fieldErrors = append(fieldErrors, Required("dateOfBirth"))
+	case !utils.IsValidDate(member.DateOfBirth):
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "dateOfBirth", Code: CodeInvalidDate, Message: "Invalid date of birth"})
+	}
+
+	switch {
+	case member.StartDate == "":
+	case member.PlanId == 0:
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "startDate", Code: CodeInvalid, Message: "Start date requires a plan"})
+	case !utils.IsValidDate(member.StartDate):
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "startDate", Code: CodeInvalidDate, Message: "Invalid start date"})
+	}
+
+	if member.PlanId < 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "planId", Code: CodeInvalid, Message: "Invalid plan"})
+	}
+	return fieldErrors
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"members.com/membership/pkg/models"
+)
+
+func TestValidateMember(t *testing.T) {
+	t.Parallel()
+
+	validMember := func() *models.UpdateMember {
+		return &models.UpdateMember{
+			FirstName:   "John",
+			LastName:    "Doe",
+			Email:       "john.doe@gmail.com",
+			DateOfBirth: "1990-01-01",
+			PlanId:      1,
+			StartDate:   "2024-06-01",
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		modify   func(member *models.UpdateMember)
+		expected []models.FieldError
+	}{
+		{
+			name:   "Valid member",
+			modify: func(member *models.UpdateMember) {},
+		},
+		{
+			name: "Valid member without a plan",
+			modify: func(member *models.UpdateMember) {
+				member.PlanId = 0
+				member.StartDate = ""
+			},
+		},
+		{
+			name: "Every missing field is reported",
+			modify: func(member *models.UpdateMember) {
+				*member = models.UpdateMember{}
+			},
+			expected: []models.FieldError{
+				{Field: "firstName", Code: CodeRequired, Message: "firstName is required"},
+				{Field: "lastName", Code: CodeRequired, Message: "lastName is required"},
+				{Field: "email", Code: CodeRequired, Message: "email is required"},
+				{Field: "dateOfBirth", Code: CodeRequired, Message: "dateOfBirth is required"},
+			},
+		},
+		{
+			name: "Every malformed field is reported",
+			modify: func(member *models.UpdateMember) {
+				member.Email = "john.doegmail.com"
+				member.DateOfBirth = "01/01/1990"
+				member.StartDate = "June 2024"
+			},
+			expected: []models.FieldError{
+				{Field: "email", Code: CodeInvalidEmail, Message: "Invalid email"},
+				{Field: "dateOfBirth", Code: CodeInvalidDate, Message: "Invalid date of birth"},
+				{Field: "startDate", Code: CodeInvalidDate, Message: "Invalid start date"},
+			},
+		},
+		{
+			name: "Start date without a plan",
+			modify: func(member *models.UpdateMember) {
+				member.PlanId = 0
+			},
+			expected: []models.FieldError{
+				{Field: "startDate", Code: CodeInvalid, Message: "Start date requires a plan"},
+			},
+		},
+		{
+			name: "Negative plan",
+			modify: func(member *models.UpdateMember) {
+				member.PlanId = -1
+			},
+			expected: []models.FieldError{
+				{Field: "planId", Code: CodeInvalid, Message: "Invalid plan"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			member := validMember()
+			tc.modify(member)
+			assert.Equal(t, tc.expected, ValidateMember(member))
+		})
+	}
+}
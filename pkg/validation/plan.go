@@ -0,0 +1,25 @@
+package validation
+
+import "members.com/membership/pkg/models"
+
+// ValidatePlan returns every problem with the plan's fields, or nil when they
+// are all valid.
+func ValidatePlan(plan *models.UpdatePlan) []models.FieldError {
+	var fieldErrors []models.FieldError
+
+	if plan.Name == "" {
+		fieldErrors = append(fieldErrors, Required("name"))
+	}
+
+	switch {
+	case plan.DurationMonths == 0:
+		fieldErrors = append(fieldErrors, Required("durationMonths"))
+	case plan.DurationMonths < 1:
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "durationMonths", Code: CodeInvalid, Message: "Duration must be at least 1 month"})
+	}
+
+	if plan.PriceCents < 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "priceCents", Code: CodeInvalid, Message: "Price must not be negative"})
+	}
+	return fieldErrors
+}
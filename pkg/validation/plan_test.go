@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"members.com/membership/pkg/models"
+)
+
+func TestValidatePlan(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		plan     *models.UpdatePlan
+		expected []models.FieldError
+	}{
+		{
+			name: "Valid plan",
+			plan: &models.UpdatePlan{Name: "Gold", DurationMonths: 12, PriceCents: 12000},
+		},
+		{
+			name: "Free plan",
+			plan: &models.UpdatePlan{Name: "Trial", DurationMonths: 1},
+		},
+		{
+			name: "Missing fields",
+			plan: &models.UpdatePlan{PriceCents: 12000},
+			expected: []models.FieldError{
+				{Field: "name", Code: CodeRequired, Message: "name is required"},
+				{Field: "durationMonths", Code: CodeRequired, Message: "durationMonths is required"},
+			},
+		},
+		{
+			name: "Every field is reported",
+			plan: &models.UpdatePlan{DurationMonths: -1, PriceCents: -100},
+			expected: []models.FieldError{
+				{Field: "name", Code: CodeRequired, Message: "name is required"},
+				{Field: "durationMonths", Code: CodeInvalid, Message: "Duration must be at least 1 month"},
+				{Field: "priceCents", Code: CodeInvalid, Message: "Price must not be negative"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ValidatePlan(tc.plan))
+		})
+	}
+}
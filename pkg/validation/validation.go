@@ -0,0 +1,85 @@
+// Package validation checks request bodies field by field, so that every
+// problem can be reported at once.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"members.com/membership/pkg/models"
+)
+
+// Codes of the problems a field can have.
+const (
+	CodeRequired          = "required"
+	CodeInvalidType       = "invalid_type"
+	CodeInvalidEmail      = "invalid_email"
+	CodeInvalidDate       = "invalid_date"
+	CodeUnknownPlan       = "unknown_plan"
+	CodeUnknownPermission = "unknown_permission"
+	CodeUnknownRole       = "unknown_role"
+	CodeDuplicate         = "duplicate"
+	CodeInvalid           = "invalid"
+)
+
+// ErrorMessage is the error reported alongside field errors.
+const ErrorMessage = "Validation failed"
+
+func Required(field string) models.FieldError {
+	return models.FieldError{Field: field, Code: CodeRequired, Message: fmt.Sprintf("%s is required", field)}
+}
+
+func InvalidType(field string) models.FieldError {
+	return models.FieldError{Field: field, Code: CodeInvalidType, Message: fmt.Sprintf("%s has the wrong type", field)}
+}
+
+// FromBindingError translates an error from binding a JSON body into obj
+// into field errors. It returns nil when the error is not about particular
+// fields, such as malformed JSON.
+func FromBindingError(err error, obj any) []models.FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fieldErrors := make([]models.FieldError, 0, len(validationErrors))
+		for _, validationError := range validationErrors {
+			field := jsonFieldName(obj, validationError.StructField())
+			if validationError.Tag() == "required" {
+				fieldErrors = append(fieldErrors, Required(field))
+			} else {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: field, Code: CodeInvalid, Message: fmt.Sprintf("%s is invalid", field)})
+			}
+		}
+		return fieldErrors
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		return []models.FieldError{InvalidType(typeError.Field)}
+	}
+	return nil
+}
+
+// jsonFieldName returns the name structField of obj has in JSON, falling back
+// to the Go name when it has no json tag.
+func jsonFieldName(obj any, structField string) string {
+	objType := reflect.TypeOf(obj)
+	for objType.Kind() == reflect.Pointer {
+		objType = objType.Elem()
+	}
+	if objType.Kind() != reflect.Struct {
+		return structField
+	}
+
+	field, found := objType.FieldByName(structField)
+	if !found {
+		return structField
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return structField
+	}
+	return name
+}
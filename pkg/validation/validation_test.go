@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"members.com/membership/pkg/models"
+)
+
+// boundPlan has the binding rules whose errors FromBindingError translates.
+type boundPlan struct {
+	Name           string `json:"name" binding:"required"`
+	DurationMonths int    `json:"durationMonths" binding:"required"`
+	PriceCents     int64  `json:"priceCents"`
+}
+
+func TestFromBindingError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected []models.FieldError
+	}{
+		{
+			name: "Missing fields are reported by their JSON names",
+			body: `{"priceCents": 1000}`,
+			expected: []models.FieldError{
+				{Field: "name", Code: CodeRequired, Message: "name is required"},
+				{Field: "durationMonths", Code: CodeRequired, Message: "durationMonths is required"},
+			},
+		},
+		{
+			name: "Field of the wrong type",
+			body: `{"name": "Gold", "durationMonths": "twelve"}`,
+			expected: []models.FieldError{
+				{Field: "durationMonths", Code: CodeInvalidType, Message: "durationMonths has the wrong type"},
+			},
+		},
+		{
+			name: "Malformed JSON is not about any field",
+			body: `{"name": "Gold",`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, "/plans", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+
+			var plan boundPlan
+			err = binding.JSON.Bind(request, &plan)
+			require.Error(t, err)
+			assert.Equal(t, tc.expected, FromBindingError(err, &plan))
+		})
+	}
+
+	assert.Nil(t, FromBindingError(errors.New("unexpected EOF"), &boundPlan{}))
+}